@@ -25,11 +25,17 @@ func (r *AztecReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxing.
 
 // Decode : Locates and decodes a Data Matrix code in an image.
 //
+// If the ALSO_INVERTED hint is set, the inverted image is also tried.
+//
 // @return a String representing the content encoded by the Data Matrix code
 // @throws NotFoundException if a Data Matrix code cannot be found
 // @throws FormatException if a Data Matrix code cannot be decoded
 //
 func (r *AztecReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return gozxing.Reader_DecodeAlsoInverted(image, hints, r.decode)
+}
+
+func (r *AztecReader) decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
 
 	var notFoundException error
 	var formatException error
@@ -40,6 +46,7 @@ func (r *AztecReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.Deco
 	detector := detector.NewDetector(bmp)
 	var points []gozxing.ResultPoint
 	var decoderResult *common.DecoderResult
+	mirrored := false
 
 	detectorResult, err := detector.Detect(false)
 	if err != nil {
@@ -52,6 +59,7 @@ func (r *AztecReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.Deco
 		}
 	}
 	if decoderResult == nil {
+		mirrored = true
 		detectorResult, err = detector.Detect(true)
 		if err != nil {
 			err = gozxing.WrapNotFoundException(err)
@@ -82,11 +90,22 @@ func (r *AztecReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.Deco
 		}
 	}
 
+	return AztecReader_CreateResult(decoderResult, detectorResult, mirrored), nil
+}
+
+// AztecReader_CreateResult Creates the result of the decoded Aztec code with its metadata.
+//
+// @param decoderResult the decoded Aztec code
+// @param detectorResult the detected Aztec code
+// @param mirrored true if the image is a mirror-image of the code
+// @return the result of the Aztec code
+//
+func AztecReader_CreateResult(decoderResult *common.DecoderResult, detectorResult *detector.AztecDetectorResult, mirrored bool) *gozxing.Result {
 	result := gozxing.NewResultWithNumBits(
 		decoderResult.GetText(),
 		decoderResult.GetRawBytes(),
 		decoderResult.GetNumBits(),
-		points,
+		detectorResult.GetPoints(),
 		gozxing.BarcodeFormat_AZTEC,
 		time.Now().UnixNano()/int64(time.Millisecond))
 
@@ -99,8 +118,16 @@ func (r *AztecReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.Deco
 		result.PutMetadata(gozxing.ResultMetadataType_ERROR_CORRECTION_LEVEL, ecLevel)
 	}
 	result.PutMetadata(gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER, "]z"+strconv.Itoa(decoderResult.GetSymbologyModifier()))
+	if segments := decoderResult.GetSegments(); len(segments) > 0 {
+		result.PutMetadata(gozxing.ResultMetadataType_DECODED_SEGMENTS, segments)
+	}
+	result.PutMetadata(gozxing.ResultMetadataType_AZTEC_COMPACT, detectorResult.IsCompact())
+	result.PutMetadata(gozxing.ResultMetadataType_AZTEC_LAYERS, detectorResult.GetNbLayers())
+	result.PutMetadata(gozxing.ResultMetadataType_SYMBOL_ROWS, detectorResult.GetBits().GetHeight())
+	result.PutMetadata(gozxing.ResultMetadataType_SYMBOL_COLUMNS, detectorResult.GetBits().GetWidth())
+	result.PutMetadata(gozxing.ResultMetadataType_MIRRORED, mirrored)
 
-	return result, nil
+	return result
 }
 
 func (r *AztecReader) Reset() {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
 	"github.com/makiuchi-d/gozxing/testutil"
 )
 
@@ -137,6 +138,38 @@ func TestAztecReader_Decode(t *testing.T) {
 	if si, wants := metadata[gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER], "]z0"; si != wants {
 		t.Fatalf("Metadata[SYMBOLOGY_IDENTIFIER]: %v, wants %v", si, wants)
 	}
+	testSymbolMetadata(t, metadata, true, 1, 15, false)
+	if segments, ok := metadata[gozxing.ResultMetadataType_DECODED_SEGMENTS].([]*common.DecodedSegment); !ok || len(segments) == 0 {
+		t.Fatalf("Metadata[DECODED_SEGMENTS]: %v", metadata[gozxing.ResultMetadataType_DECODED_SEGMENTS])
+	}
+
+	// mirrored correct image
+	bmp = testutil.NewBinaryBitmapFromBitMatrix(testutil.ExpandBitMatrix(testutil.MirrorBitMatrix(img), 3))
+	r, e = d.Decode(bmp, nil)
+	if e != nil {
+		t.Fatalf("Decode error: %+v", e)
+	}
+	if txt, wants := r.GetText(), "Histórico"; txt != wants {
+		t.Fatalf("GetText: %v, wants %v", txt, wants)
+	}
+	testSymbolMetadata(t, r.GetResultMetadata(), true, 1, 15, true)
+}
+
+func testSymbolMetadata(t testing.TB, metadata map[gozxing.ResultMetadataType]interface{},
+	compact bool, layers, size int, mirrored bool) {
+	t.Helper()
+	wants := map[gozxing.ResultMetadataType]interface{}{
+		gozxing.ResultMetadataType_AZTEC_COMPACT:  compact,
+		gozxing.ResultMetadataType_AZTEC_LAYERS:   layers,
+		gozxing.ResultMetadataType_SYMBOL_ROWS:    size,
+		gozxing.ResultMetadataType_SYMBOL_COLUMNS: size,
+		gozxing.ResultMetadataType_MIRRORED:       mirrored,
+	}
+	for k, v := range wants {
+		if r, ok := metadata[k]; !ok || r != v {
+			t.Fatalf("Metadata[%v]: %v, wants %v", k, r, v)
+		}
+	}
 }
 
 func TestAztecReader_Decode_Blackbox(t *testing.T) {
@@ -190,4 +223,51 @@ func TestAztecReader_Decode_Blackbox(t *testing.T) {
 	for _, test := range tests {
 		testutil.TestFile(t, reader, test.file, test.wants, format, nil, nil)
 	}
+
+	// symbol metadata
+	testutil.TestFile(t, reader, "testdata/aztec-1/abc-19x19C.png", "abcdefghijklmnopqrstuvwxyz", format, nil,
+		map[gozxing.ResultMetadataType]interface{}{
+			gozxing.ResultMetadataType_AZTEC_COMPACT:  true,
+			gozxing.ResultMetadataType_AZTEC_LAYERS:   2,
+			gozxing.ResultMetadataType_SYMBOL_ROWS:    19,
+			gozxing.ResultMetadataType_SYMBOL_COLUMNS: 19,
+		})
+	testutil.TestFile(t, reader, "testdata/aztec-1/abc-37x37.png", "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ", format, nil,
+		map[gozxing.ResultMetadataType]interface{}{
+			gozxing.ResultMetadataType_AZTEC_COMPACT:  false,
+			gozxing.ResultMetadataType_AZTEC_LAYERS:   5,
+			gozxing.ResultMetadataType_SYMBOL_ROWS:    37,
+			gozxing.ResultMetadataType_SYMBOL_COLUMNS: 37,
+		})
+}
+
+func TestAztecReader_DecodeAlsoInverted(t *testing.T) {
+	file := "testdata/aztec-1/7.png"
+	bmp, _ := testutil.NewBinaryBitmapFromFile(file).Invert()
+	reader := NewAztecReader()
+
+	if _, e := reader.Decode(bmp, nil); e == nil {
+		t.Fatalf("Decode(inverted %s) without ALSO_INVERTED must be error", file)
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	result, e := reader.Decode(bmp, hints)
+	if e != nil {
+		t.Fatalf("Decode(inverted %s) returns error, %v", file, e)
+	}
+	if txt, expect := result.GetText(), "Code 2D!"; txt != expect {
+		t.Fatalf("Decode(inverted %s) = \"%v\", expect \"%v\"", file, txt, expect)
+	}
+	if inv := result.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; inv != true {
+		t.Fatalf("Decode(inverted %s) INVERTED = %v, expect true", file, inv)
+	}
+}
+
+func TestAztecReader_Concurrent(t *testing.T) {
+	testutil.TestConcurrentDecode(t, NewAztecReader(),
+		[]string{"testdata/aztec-1/7.png", "testdata/aztec-1/hello.png", "testdata/aztec-2/01.png"},
+		[]string{"Code 2D!", "hello", "This is a real world Aztec barcode test."},
+		nil)
 }
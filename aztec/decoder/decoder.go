@@ -72,12 +72,13 @@ func (this *Decoder) Decode(detectorResult *detector.AztecDetectorResult) (*comm
 		return nil, gozxing.WrapFormatException(err)
 	}
 	rawBytes := convertBoolArrayToByteArray(correctedBits.correctBits)
-	result, e := this.getEncodedData(correctedBits.correctBits)
+	result, segments, e := this.getEncodedSegments(correctedBits.correctBits)
 	if e != nil {
 		return nil, gozxing.WrapFormatException(e)
 	}
 	decoderResult := common.NewDecoderResult(rawBytes, result, nil, fmt.Sprintf("%d%%", correctedBits.ecLevel))
 	decoderResult.SetNumBits(len(correctedBits.correctBits))
+	decoderResult.SetSegments(segments)
 	return decoderResult, nil
 }
 
@@ -91,6 +92,23 @@ func (this *Decoder) HighLevelDecode(correctedBits []bool) (string, error) {
 // @return the decoded string
 //
 func (this *Decoder) getEncodedData(correctedBits []bool) (string, error) {
+	result, _, e := this.getEncodedSegments(correctedBits)
+	return result, e
+}
+
+// pendingSegment a decoded segment whose bytes are not converted into the result yet
+type pendingSegment struct {
+	table Table
+	eci   int
+	from  int // offset in the decoded bytes
+	to    int
+}
+
+// getEncodedSegments Gets the string and the segments encoded in the aztec code bits
+//
+// @return the decoded string and the decoded segments
+//
+func (this *Decoder) getEncodedSegments(correctedBits []bool) (string, []*common.DecodedSegment, error) {
 	endIndex := len(correctedBits)
 	latchTable := TableUPPER // table most recently latched to
 	shiftTable := TableUPPER // table to use for the next read
@@ -103,6 +121,36 @@ func (this *Decoder) getEncodedData(correctedBits []bool) (string, error) {
 	// when character encoding changes (ECI) or input ends.
 	decodedBytes := make([]byte, 0)
 	encoding := DEFAULT_ENCODING
+	eciValue := -1
+
+	var segments []*common.DecodedSegment
+	pendings := make([]pendingSegment, 0)
+	addPending := func(table Table, from, to int) {
+		if n := len(pendings); n > 0 {
+			last := &pendings[n-1]
+			if last.table == table && last.eci == eciValue && last.to == from {
+				last.to = to
+				return
+			}
+		}
+		pendings = append(pendings, pendingSegment{table, eciValue, from, to})
+	}
+	// flush decodes the bytes into the result and fixes the ranges of the pending segments
+	flush := func() error {
+		base := len(result)
+		var e error
+		result, _, e = transform.Append(encoding.NewDecoder(), result, decodedBytes)
+		if e != nil {
+			return e
+		}
+		for _, p := range pendings {
+			segments = common.DecodedSegment_Append(segments, p.table.String(), p.eci, decodedBytes[p.from:p.to],
+				base+decodedLength(encoding, decodedBytes[:p.from]), base+decodedLength(encoding, decodedBytes[:p.to]))
+		}
+		decodedBytes = decodedBytes[:0]
+		pendings = pendings[:0]
+		return nil
+	}
 
 	index := 0
 	for index < endIndex {
@@ -119,6 +167,7 @@ func (this *Decoder) getEncodedData(correctedBits []bool) (string, error) {
 				length = readCode(correctedBits, index, 11) + 31
 				index += 11
 			}
+			from := len(decodedBytes)
 			for charCount := 0; charCount < length; charCount++ {
 				if endIndex-index < 8 {
 					index = endIndex // Force outer loop to exit
@@ -128,6 +177,9 @@ func (this *Decoder) getEncodedData(correctedBits []bool) (string, error) {
 				decodedBytes = append(decodedBytes, byte(code))
 				index += 8
 			}
+			if len(decodedBytes) > from {
+				addPending(TableBINARY, from, len(decodedBytes))
+			}
 			// Go back to whatever mode we had been in
 			shiftTable = latchTable
 		} else {
@@ -142,7 +194,7 @@ func (this *Decoder) getEncodedData(correctedBits []bool) (string, error) {
 			index += size
 			str, e := getCharacter(shiftTable, code)
 			if e != nil {
-				return string(result), e
+				return string(result), nil, e
 			}
 			if str == "FLG(n)" {
 				if endIndex-index < 3 {
@@ -151,17 +203,17 @@ func (this *Decoder) getEncodedData(correctedBits []bool) (string, error) {
 				n := readCode(correctedBits, index, 3)
 				index += 3
 				// flush bytes before changing character set
-				result, _, e = transform.Append(encoding.NewDecoder(), result, decodedBytes)
-				if e != nil {
-					return string(result), e
+				if e = flush(); e != nil {
+					return string(result), nil, e
 				}
-				decodedBytes = decodedBytes[:0]
 				switch n {
 				case 0:
 					result = append(result, 29) // translate FNC1 as ASCII 29
+					segments = common.DecodedSegment_Append(
+						segments, TablePUNCT.String(), eciValue, []byte{29}, len(result)-1, len(result))
 					break
 				case 7:
-					return string(result), gozxing.NewFormatException("FLG(7) is reserved and illegal")
+					return string(result), nil, gozxing.NewFormatException("FLG(7) is reserved and illegal")
 				default:
 					// ECI is decimal integer encoded as 1-6 codes in DIGIT mode
 					eci := 0
@@ -173,15 +225,16 @@ func (this *Decoder) getEncodedData(correctedBits []bool) (string, error) {
 						nextDigit := readCode(correctedBits, index, 4)
 						index += 4
 						if nextDigit < 2 || nextDigit > 11 {
-							return string(result), gozxing.NewFormatException("Not a decimal digit")
+							return string(result), nil, gozxing.NewFormatException("Not a decimal digit")
 						}
 						eci = eci*10 + (nextDigit - 2)
 					}
 					charsetECI, e := common.GetCharacterSetECIByValue(eci)
 					if e != nil {
-						return string(result), gozxing.WrapFormatException(e)
+						return string(result), nil, gozxing.WrapFormatException(e)
 					}
 					encoding = charsetECI.GetCharset()
+					eciValue = eci
 				}
 				// Go back to whatever mode we had been in
 				shiftTable = latchTable
@@ -198,18 +251,26 @@ func (this *Decoder) getEncodedData(correctedBits []bool) (string, error) {
 			} else {
 				// Though stored as a table of strings for convenience, codes actually represent 1 or 2 *bytes*.
 				b := []byte(str)
+				addPending(shiftTable, len(decodedBytes), len(decodedBytes)+len(b))
 				decodedBytes = append(decodedBytes, b...)
 				// Go back to whatever mode we had been in
 				shiftTable = latchTable
 			}
 		}
 	}
-	result, _, e := transform.Append(encoding.NewDecoder(), result, decodedBytes)
-	if e != nil {
+	if e := flush(); e != nil {
 		// can't happen
-		return string(result), gozxing.WrapFormatException(e)
+		return string(result), nil, gozxing.WrapFormatException(e)
 	}
-	return string(result), nil
+	return string(result), segments, nil
+}
+
+// decodedLength returns the length of the string decoded from the bytes.
+// An incomplete character at the end of the bytes is not counted.
+func decodedLength(enc encoding.Encoding, b []byte) int {
+	dst := make([]byte, 4*len(b)+4)
+	nDst, _, _ := enc.NewDecoder().Transform(dst, b, false)
+	return nDst
 }
 
 // getTable gets the table corresponding to the char passed
@@ -232,6 +293,24 @@ func getTable(t byte) Table {
 	return TableUPPER
 }
 
+func (t Table) String() string {
+	switch t {
+	case TableUPPER:
+		return "UPPER"
+	case TableLOWER:
+		return "LOWER"
+	case TableMIXED:
+		return "MIXED"
+	case TableDIGIT:
+		return "DIGIT"
+	case TablePUNCT:
+		return "PUNCT"
+	case TableBINARY:
+		return "BINARY"
+	}
+	return ""
+}
+
 // getCharacter Gets the character (or string) corresponding to the passed code in the given table
 //
 // @param table the table used
@@ -6,6 +6,7 @@ import (
 
 	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/aztec/detector"
+	"github.com/makiuchi-d/gozxing/common"
 	"github.com/makiuchi-d/gozxing/testutil"
 )
 
@@ -271,6 +272,76 @@ func TestDecoder_getEncodedData(t *testing.T) {
 	}
 }
 
+func TestDecoder_getEncodedSegments(t *testing.T) {
+	dec := NewDecoder()
+
+	bits := strToBools("" +
+		"00000" + "00000" + "000" + // CTRL_PS, FLG(n), 0
+		"00010" + "11100" + "11011" + "11110" + "1101" + "1110" + // 'A', CTRL_LL, 'z', CTLR_DL, '.' CTRL_UL
+		"00000" + "00000" + "010" + "0100" + "1000" + // CTRL_PS, FLG(n), 2, '2', '6' (charset = UTF-8)
+		"11111" + "00011" + // CTRL_BS, length=3
+		"11100101" + "10101111" + "10111111" + // "寿"
+		"")
+	r, segments, e := dec.getEncodedSegments(bits)
+	if e != nil {
+		t.Fatalf("getEncodedSegments error: %v", e)
+	}
+	if wants := "\035Az.寿"; r != wants {
+		t.Fatalf("result = %q, wants %q", r, wants)
+	}
+	wants := []*common.DecodedSegment{
+		common.NewDecodedSegment("PUNCT", -1, []byte{29}, 0, 1),
+		common.NewDecodedSegment("UPPER", -1, []byte("A"), 1, 2),
+		common.NewDecodedSegment("LOWER", -1, []byte("z"), 2, 3),
+		common.NewDecodedSegment("DIGIT", -1, []byte("."), 3, 4),
+		common.NewDecodedSegment("BINARY", 26, []byte("寿"), 4, 7),
+	}
+	if !reflect.DeepEqual(segments, wants) {
+		t.Fatalf("segments = %v, wants %v", segments, wants)
+	}
+
+	// a Shift_JIS character split into BINARY and MIXED
+	bits = strToBools("" +
+		"00000" + "00000" + "010" + "0100" + "0010" + // CTRL_PS, FLG(n), 2, '2', '0' (charset = Shift_JIS)
+		"11111" + "00001" + "10000010" + // CTRL_BS, length=1, 0x82
+		"11101" + "11000" + // CTRL_ML, '`'(0x60)
+		"")
+	r, segments, e = dec.getEncodedSegments(bits)
+	if e != nil {
+		t.Fatalf("getEncodedSegments error: %v", e)
+	}
+	if wants := "Ａ"; r != wants {
+		t.Fatalf("result = %q, wants %q", r, wants)
+	}
+	wants = []*common.DecodedSegment{
+		common.NewDecodedSegment("BINARY", 20, []byte{0x82}, 0, 0),
+		common.NewDecodedSegment("MIXED", 20, []byte{0x60}, 0, 3),
+	}
+	if !reflect.DeepEqual(segments, wants) {
+		t.Fatalf("segments = %v, wants %v", segments, wants)
+	}
+}
+
+func TestTable_String(t *testing.T) {
+	tests := []struct {
+		t     Table
+		wants string
+	}{
+		{TableUPPER, "UPPER"},
+		{TableLOWER, "LOWER"},
+		{TableMIXED, "MIXED"},
+		{TableDIGIT, "DIGIT"},
+		{TablePUNCT, "PUNCT"},
+		{TableBINARY, "BINARY"},
+		{Table(-1), ""},
+	}
+	for _, test := range tests {
+		if r := test.t.String(); r != test.wants {
+			t.Fatalf("Table(%d).String() = %q, wants %q", test.t, r, test.wants)
+		}
+	}
+}
+
 func TestGetTable(t *testing.T) {
 	tests := []struct {
 		t     byte
@@ -55,6 +55,27 @@ func (this *Detector) Detect(isMirror bool) (*AztecDetectorResult, error) {
 	// 1. Get the center of the aztec matrix
 	pCenter := this.getMatrixCenter()
 
+	return this.detect(pCenter, isMirror)
+}
+
+// DetectAt Detects an Aztec Code whose bull's eye is centered on the passed point,
+// instead of finding the center around the center of the image.
+//
+// @param centerX x coordinate of the center of the bull's eye
+// @param centerY y coordinate of the center of the bull's eye
+// @param isMirror if true, image is a mirror-image of original
+// @return {@link AztecDetectorResult} encapsulating results of detecting an Aztec Code
+// @throws NotFoundException if no Aztec Code can be found at the point
+//
+func (this *Detector) DetectAt(centerX, centerY int, isMirror bool) (*AztecDetectorResult, error) {
+	if !this.isValid(centerX, centerY) {
+		return nil, gozxing.NewNotFoundException("center (%v, %v) is out of the image", centerX, centerY)
+	}
+	return this.detect(newPoint(centerX, centerY), isMirror)
+}
+
+func (this *Detector) detect(pCenter Point, isMirror bool) (*AztecDetectorResult, error) {
+
 	// 2. Get the center points of the four diagonal points just outside the bull's eye
 	//  [topRight, bottomRight, bottomLeft, topLeft]
 	bullsEyeCorners, e := this.getBullsEyeCorners(pCenter)
@@ -168,6 +168,45 @@ func TestDetector_Detect(t *testing.T) {
 	}
 }
 
+func TestDetector_DetectAt(t *testing.T) {
+	img, _ := gozxing.ParseStringToBitMatrix(""+
+		"    ##    ##  ####        ##  \n"+
+		"  ######    ##  ######      ##\n"+
+		"    ####        ##  ##  ##    \n"+
+		"##########################    \n"+
+		"####  ##              ##      \n"+
+		"    ####  ##########  ##  ##  \n"+
+		"  ##  ##  ##      ##  ##      \n"+
+		"  ######  ##  ##  ##  ########\n"+
+		"  ######  ##      ##  ##      \n"+
+		"  ######  ##########  ####    \n"+
+		"    ####              ######  \n"+
+		"##    ####################  ##\n"+
+		"##        ##    ##  ##        \n"+
+		"####      ######  ##  ##    ##\n"+
+		"########    ####  ####  ##  ##\n",
+		"##", "  ")
+	det := NewDetector(testutil.ExpandBitMatrix(img, 3))
+
+	_, e := det.DetectAt(45, 22, false)
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("DetectAt must be NotFoundException, %T", e)
+	}
+
+	_, e = det.DetectAt(4, 4, false)
+	if e == nil {
+		t.Fatalf("DetectAt must be error")
+	}
+
+	r, e := det.DetectAt(22, 22, false)
+	if e != nil {
+		t.Fatalf("DetectAt error: %v", e)
+	}
+	if b := r.GetBits(); !reflect.DeepEqual(b, img) {
+		t.Fatalf("detected img:\n%v\nwants:\n%v", b, img)
+	}
+}
+
 func TestDetector_extractParameters(t *testing.T) {
 	img, _ := gozxing.NewBitMatrix(60, 60)
 	det := NewDetector(img)
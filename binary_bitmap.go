@@ -1,19 +1,25 @@
 package gozxing
 
 import (
+	"sync"
+
 	errors "golang.org/x/xerrors"
 )
 
+// BinaryBitmap is safe for concurrent use by multiple readers.
+// The black matrix is created once and shared by the readers, which must not modify it.
 type BinaryBitmap struct {
 	binarizer Binarizer
-	matrix    *BitMatrix
+
+	mutex  sync.Mutex // guards the binarizer and the cached matrix
+	matrix *BitMatrix
 }
 
 func NewBinaryBitmap(binarizer Binarizer) (*BinaryBitmap, error) {
 	if binarizer == nil {
 		return nil, errors.New("IllegalArgumentException: Binarizer must be non-null")
 	}
-	return &BinaryBitmap{binarizer, nil}, nil
+	return &BinaryBitmap{binarizer: binarizer}, nil
 }
 
 func (this *BinaryBitmap) GetWidth() int {
@@ -25,6 +31,9 @@ func (this *BinaryBitmap) GetHeight() int {
 }
 
 func (this *BinaryBitmap) GetBlackRow(y int, row *BitArray) (*BitArray, error) {
+	// The binarizer may reuse its internal buffers, so that it is called exclusively.
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
 	return this.binarizer.GetBlackRow(y, row)
 }
 
@@ -34,6 +43,8 @@ func (this *BinaryBitmap) GetBlackMatrix() (*BitMatrix, error) {
 	// 1. This work will never be done if the caller only installs 1D Reader objects, or if a
 	//    1D Reader finds a barcode before the 2D Readers run.
 	// 2. This work will only be done once even if the caller installs multiple 2D Readers.
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
 	if this.matrix == nil {
 		var e error
 		this.matrix, e = this.binarizer.GetBlackMatrix()
@@ -76,6 +87,12 @@ func (this *BinaryBitmap) RotateCounterClockwise45() (*BinaryBitmap, error) {
 	return NewBinaryBitmap(this.binarizer.CreateBinarizer(newSource))
 }
 
+// Invert Returns a new object with the inverted image data (light on dark to dark on light).
+func (this *BinaryBitmap) Invert() (*BinaryBitmap, error) {
+	newSource := this.binarizer.GetLuminanceSource().Invert()
+	return NewBinaryBitmap(this.binarizer.CreateBinarizer(newSource))
+}
+
 func (this *BinaryBitmap) String() string {
 	matrix, e := this.GetBlackMatrix()
 	if e != nil {
@@ -1,6 +1,7 @@
 package gozxing
 
 import (
+	"sync"
 	"testing"
 
 	errors "golang.org/x/xerrors"
@@ -199,3 +200,65 @@ func TestBinaryBitmap_Illegal(t *testing.T) {
 		t.Fatalf("string = \"%v\", expect \"%v\"", s, expect)
 	}
 }
+
+func TestBinaryBitmap_Invert(t *testing.T) {
+	bmp, _ := NewBinaryBitmap(&testBinarizer{newTestLuminanceSource(16)})
+	inverted, e := bmp.Invert()
+	if e != nil {
+		t.Fatalf("Invert returns error, %v", e)
+	}
+	if w, h := inverted.GetWidth(), inverted.GetHeight(); w != 16 || h != 16 {
+		t.Fatalf("inverted size = %v,%v, expect 16,16", w, h)
+	}
+	src := inverted.binarizer.GetLuminanceSource()
+	if _, ok := src.(*InvertedLuminanceSource); !ok {
+		t.Fatalf("inverted source type must be *InvertedLuminanceSource, %T", src)
+	}
+	matrix, _ := inverted.GetBlackMatrix()
+	for y := 0; y < matrix.GetHeight(); y++ {
+		for x := 0; x < matrix.GetWidth(); x++ {
+			if matrix.Get(x, y) != (x >= 8) {
+				t.Fatalf("inverted BlackMatrix.Get(%v,%v) = %v, expect %v", x, y, matrix.Get(x, y), (x >= 8))
+			}
+		}
+	}
+}
+
+type countingBinarizer struct {
+	testBinarizer
+	count int
+}
+
+func (this *countingBinarizer) GetBlackMatrix() (*BitMatrix, error) {
+	this.count++
+	return this.testBinarizer.GetBlackMatrix()
+}
+
+func TestBinaryBitmap_Concurrent(t *testing.T) {
+	binarizer := &countingBinarizer{testBinarizer{newTestLuminanceSource(16)}, 0}
+	bmp, _ := NewBinaryBitmap(binarizer)
+
+	const n = 8
+	matrices := make([]*BitMatrix, n)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			if _, e := bmp.GetBlackRow(i, NewBitArray(16)); e != nil {
+				t.Errorf("GetBlackRow(%v) returns error, %v", i, e)
+			}
+			matrices[i], _ = bmp.GetBlackMatrix()
+		}(i)
+	}
+	wg.Wait()
+
+	if binarizer.count != 1 {
+		t.Fatalf("binarizer.GetBlackMatrix called %v times, expect 1", binarizer.count)
+	}
+	for i, m := range matrices {
+		if m != matrices[0] {
+			t.Fatalf("GetBlackMatrix[%v] = %p, expect %p", i, m, matrices[0])
+		}
+	}
+}
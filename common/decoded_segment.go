@@ -0,0 +1,84 @@
+package common
+
+import (
+	"fmt"
+)
+
+// DecodedSegment A segment of the data decoded from a symbol, in the order it was encoded.
+//
+// The mode is the name of the encodation mode of the symbology (e.g. "NUMERIC", "C40_ENCODE", "UPPER"),
+// eci is the ECI value in effect for the segment (-1 if no ECI is in effect),
+// bytes are the decoded bytes of the segment before the character set conversion,
+// and [start, end) is the byte range of the segment in the decoded text.
+type DecodedSegment struct {
+	mode  string
+	eci   int
+	bytes []byte
+	start int
+	end   int
+}
+
+func NewDecodedSegment(mode string, eci int, bytes []byte, start, end int) *DecodedSegment {
+	return &DecodedSegment{
+		mode:  mode,
+		eci:   eci,
+		bytes: bytes,
+		start: start,
+		end:   end,
+	}
+}
+
+func (this *DecodedSegment) GetMode() string {
+	return this.mode
+}
+
+// GetECI returns the ECI value in effect for the segment, or -1
+func (this *DecodedSegment) GetECI() int {
+	return this.eci
+}
+
+func (this *DecodedSegment) GetBytes() []byte {
+	return this.bytes
+}
+
+// GetStart returns the start offset of the segment in the decoded text
+func (this *DecodedSegment) GetStart() int {
+	return this.start
+}
+
+// GetEnd returns the end offset (exclusive) of the segment in the decoded text
+func (this *DecodedSegment) GetEnd() int {
+	return this.end
+}
+
+func (this *DecodedSegment) String() string {
+	return fmt.Sprintf("%v(eci=%v)[%v:%v]%q", this.mode, this.eci, this.start, this.end, this.bytes)
+}
+
+// DecodedSegment_Append Appends the decoded bytes to the list of segments.
+//
+// The last segment is extended if it has the same mode and ECI and ends where the bytes start.
+// The bytes are copied.
+// This is for the parsers which decode the data character by character, such as Data Matrix and Aztec.
+//
+// @param segments the list of segments
+// @param mode the encodation mode of the bytes
+// @param eci the ECI value in effect, or -1
+// @param bytes the decoded bytes
+// @param start the start offset of the bytes in the decoded text
+// @param end the end offset of the bytes in the decoded text
+// @return the list of segments
+//
+func DecodedSegment_Append(segments []*DecodedSegment, mode string, eci int, bytes []byte, start, end int) []*DecodedSegment {
+	if n := len(segments); n > 0 {
+		last := segments[n-1]
+		if last.mode == mode && last.eci == eci && last.end == start {
+			last.bytes = append(last.bytes, bytes...)
+			last.end = end
+			return segments
+		}
+	}
+	b := make([]byte, len(bytes))
+	copy(b, bytes)
+	return append(segments, NewDecodedSegment(mode, eci, b, start, end))
+}
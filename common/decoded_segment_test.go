@@ -0,0 +1,48 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodedSegment(t *testing.T) {
+	s := NewDecodedSegment("BYTE", 26, []byte{0xe3, 0x81, 0x82}, 3, 6)
+	if r := s.GetMode(); r != "BYTE" {
+		t.Fatalf("GetMode() = %v, expect BYTE", r)
+	}
+	if r := s.GetECI(); r != 26 {
+		t.Fatalf("GetECI() = %v, expect 26", r)
+	}
+	if r, wants := s.GetBytes(), []byte{0xe3, 0x81, 0x82}; !reflect.DeepEqual(r, wants) {
+		t.Fatalf("GetBytes() = %v, expect %v", r, wants)
+	}
+	if r := s.GetStart(); r != 3 {
+		t.Fatalf("GetStart() = %v, expect 3", r)
+	}
+	if r := s.GetEnd(); r != 6 {
+		t.Fatalf("GetEnd() = %v, expect 6", r)
+	}
+	if r, wants := s.String(), `BYTE(eci=26)[3:6]"あ"`; r != wants {
+		t.Fatalf("String() = %v, expect %v", r, wants)
+	}
+}
+
+func TestDecodedSegment_Append(t *testing.T) {
+	buf := []byte("0123")
+	segments := DecodedSegment_Append(nil, "NUMERIC", -1, buf[:2], 0, 2)
+	buf[0] = 'x' // bytes must be copied
+	segments = DecodedSegment_Append(segments, "NUMERIC", -1, buf[2:], 2, 4)
+	segments = DecodedSegment_Append(segments, "NUMERIC", 3, []byte("5"), 4, 5)
+	segments = DecodedSegment_Append(segments, "BYTE", 3, []byte("a"), 5, 6)
+	segments = DecodedSegment_Append(segments, "BYTE", 3, []byte("b"), 7, 8)
+
+	wants := []*DecodedSegment{
+		NewDecodedSegment("NUMERIC", -1, []byte("0123"), 0, 4),
+		NewDecodedSegment("NUMERIC", 3, []byte("5"), 4, 5),
+		NewDecodedSegment("BYTE", 3, []byte("a"), 5, 6),
+		NewDecodedSegment("BYTE", 3, []byte("b"), 7, 8),
+	}
+	if !reflect.DeepEqual(segments, wants) {
+		t.Fatalf("segments = %v, expect %v", segments, wants)
+	}
+}
@@ -12,6 +12,9 @@ type DecoderResult struct {
 	structuredAppendParity         int
 	structuredAppendSequenceNumber int
 	symbologyModifier              int
+	fnc1Position                   int
+	applicationIndicator           int
+	segments                       []*DecodedSegment
 }
 
 func NewDecoderResult(rawBytes []byte, text string, byteSegments [][]byte, ecLevel string) *DecoderResult {
@@ -36,6 +39,7 @@ func NewDecoderResultWithParams(rawBytes []byte, text string, byteSegments [][]b
 		structuredAppendParity:         saParity,
 		structuredAppendSequenceNumber: saSequence,
 		symbologyModifier:              symbologyModifier,
+		applicationIndicator:           -1,
 	}
 }
 
@@ -102,3 +106,30 @@ func (this *DecoderResult) GetStructuredAppendSequenceNumber() int {
 func (this *DecoderResult) GetSymbologyModifier() int {
 	return this.symbologyModifier
 }
+
+// SetFNC1Position sets the position of FNC1 (1: first position, 2: second position)
+// and the application indicator which follows FNC1 in second position (or -1).
+func (this *DecoderResult) SetFNC1Position(position, applicationIndicator int) {
+	this.fnc1Position = position
+	this.applicationIndicator = applicationIndicator
+}
+
+// GetFNC1Position returns 1 for FNC1 in first position, 2 for second position, or 0 if FNC1 is not used.
+func (this *DecoderResult) GetFNC1Position() int {
+	return this.fnc1Position
+}
+
+// GetApplicationIndicator returns the application indicator of FNC1 in second position, or -1.
+func (this *DecoderResult) GetApplicationIndicator() int {
+	return this.applicationIndicator
+}
+
+// SetSegments sets the list of the decoded segments in the order they were encoded.
+func (this *DecoderResult) SetSegments(segments []*DecodedSegment) {
+	this.segments = segments
+}
+
+// GetSegments returns the list of the decoded segments, or nil if not available.
+func (this *DecoderResult) GetSegments() []*DecodedSegment {
+	return this.segments
+}
@@ -99,3 +99,33 @@ func TestNewDecoderResultWithSA(t *testing.T) {
 		t.Fatalf("New WithSA GetStructuredAppendParity() = %v, expect %v", r, saParity)
 	}
 }
+
+func TestDecoderResult_FNC1Position(t *testing.T) {
+	dr := NewDecoderResult([]byte{}, "", [][]byte{}, "L")
+	if r := dr.GetFNC1Position(); r != 0 {
+		t.Fatalf("GetFNC1Position() = %v, expect 0", r)
+	}
+	if r := dr.GetApplicationIndicator(); r != -1 {
+		t.Fatalf("GetApplicationIndicator() = %v, expect -1", r)
+	}
+
+	dr.SetFNC1Position(2, 37)
+	if r := dr.GetFNC1Position(); r != 2 {
+		t.Fatalf("GetFNC1Position() = %v, expect 2", r)
+	}
+	if r := dr.GetApplicationIndicator(); r != 37 {
+		t.Fatalf("GetApplicationIndicator() = %v, expect 37", r)
+	}
+}
+
+func TestDecoderResult_Segments(t *testing.T) {
+	dr := NewDecoderResult([]byte{}, "", [][]byte{}, "L")
+	if r := dr.GetSegments(); r != nil {
+		t.Fatalf("GetSegments() = %v, expect nil", r)
+	}
+	segments := []*DecodedSegment{NewDecodedSegment("BYTE", 26, []byte("a"), 0, 1)}
+	dr.SetSegments(segments)
+	if r := dr.GetSegments(); !reflect.DeepEqual(r, segments) {
+		t.Fatalf("GetSegments() = %v, expect %v", r, segments)
+	}
+}
@@ -0,0 +1,181 @@
+package common
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+)
+
+// ECIEncoderSet Set of encoders for a given input string
+//
+// Invariants:
+//   - The list contains only encoders from CharacterSetECI.
+//   - The list contains at least one encoder for every character in the input.
+//   - The first encoder in the list is always the ISO-8859-1 encoder even if no character
+//     in the input can be encoded by it.
+//   - If the input contains a character that is not in ISO-8859-1 then the last two entries
+//     in the list will be the UTF-8 encoder and the UTF-16BE encoder.
+type ECIEncoderSet struct {
+	encoders             []*CharacterSetECI
+	priorityEncoderIndex int
+}
+
+// eciEncoderSetCandidates list of encoders that potentially encode characters not in ISO-8859-1 in one byte.
+var eciEncoderSetCandidates = []*CharacterSetECI{
+	CharacterSetECI_Cp437,
+	CharacterSetECI_ISO8859_2,
+	CharacterSetECI_ISO8859_3,
+	CharacterSetECI_ISO8859_4,
+	CharacterSetECI_ISO8859_5,
+	CharacterSetECI_ISO8859_7,
+	CharacterSetECI_ISO8859_9,
+	CharacterSetECI_ISO8859_13,
+	CharacterSetECI_ISO8859_15,
+	CharacterSetECI_ISO8859_16,
+	CharacterSetECI_Cp1250,
+	CharacterSetECI_Cp1251,
+	CharacterSetECI_Cp1252,
+	CharacterSetECI_Cp1256,
+	CharacterSetECI_SJIS,
+}
+
+// NewECIEncoderSet Constructs an encoder set
+//
+// @param str the string that needs to be encoded
+// @param priorityCharset The preferred character set or nil.
+// @param fnc1 fnc1 denotes the character in the input that represents the FNC1 character or -1 for a non-GS1 bar code.
+// When specified, it is considered an error to pass it as argument to the methods canEncode() or encode().
+//
+func NewECIEncoderSet(str string, priorityCharset *CharacterSetECI, fnc1 int) *ECIEncoderSet {
+	return newECIEncoderSet(str, priorityCharset, fnc1, false)
+}
+
+// NewECIEncoderSetWithUnicode Constructs an encoder set as NewECIEncoderSet,
+// but the UTF-8 and UTF-16BE encoders are also in the set if the string contains any non-ASCII character.
+// It is for the symbology whose default character set is UTF-8, where the characters are encoded without ECI.
+//
+// @param str the string that needs to be encoded
+// @param priorityCharset The preferred character set or nil.
+// @param fnc1 fnc1 denotes the character in the input that represents the FNC1 character or -1 for a non-GS1 bar code.
+//
+func NewECIEncoderSetWithUnicode(str string, priorityCharset *CharacterSetECI, fnc1 int) *ECIEncoderSet {
+	needUnicodeEncoder := false
+	for _, c := range str {
+		if c >= 0x80 && int(c) != fnc1 {
+			needUnicodeEncoder = true
+			break
+		}
+	}
+	return newECIEncoderSet(str, priorityCharset, fnc1, needUnicodeEncoder)
+}
+
+func newECIEncoderSet(str string, priorityCharset *CharacterSetECI, fnc1 int, needUnicodeEncoder bool) *ECIEncoderSet {
+	neededEncoders := []*CharacterSetECI{CharacterSetECI_ISO8859_1}
+	needUnicodeEncoder = needUnicodeEncoder || (priorityCharset != nil && isUnicodeCharset(priorityCharset))
+
+	// Walk over the input string and see if all characters can be encoded with the list of encoders
+	for _, c := range str {
+		canEncode := false
+		for _, encoder := range neededEncoders {
+			if int(c) == fnc1 || charsetCanEncode(encoder.GetCharset(), c) {
+				canEncode = true
+				break
+			}
+		}
+		if !canEncode {
+			// for the character at position i we don't yet have an encoder in the list
+			for _, encoder := range eciEncoderSetCandidates {
+				if charsetCanEncode(encoder.GetCharset(), c) {
+					// Good, we found an encoder that can encode the character. We add him to the list and continue scanning
+					// the input
+					neededEncoders = append(neededEncoders, encoder)
+					canEncode = true
+					break
+				}
+			}
+		}
+		if !canEncode {
+			// The character is not encodeable by any of the single byte encoders so we remember that we will need a
+			// Unicode encoder.
+			needUnicodeEncoder = true
+		}
+	}
+
+	encoders := make([]*CharacterSetECI, 0, len(neededEncoders)+2)
+	if len(neededEncoders) == 1 && !needUnicodeEncoder {
+		// the entire input can be encoded by the ISO-8859-1 encoder
+		encoders = append(encoders, neededEncoders[0])
+	} else {
+		// we need more than one single byte encoder or we need a Unicode encoder.
+		// In this case we append a UTF-8 and UTF-16 encoder to the list
+		encoders = append(encoders, neededEncoders...)
+		encoders = append(encoders, CharacterSetECI_UTF8, CharacterSetECI_UnicodeBigUnmarked)
+	}
+
+	// Compute priorityEncoderIndex by looking up priorityCharset in encoders
+	priorityEncoderIndexValue := -1
+	if priorityCharset != nil {
+		for i, encoder := range encoders {
+			if encoder == priorityCharset {
+				priorityEncoderIndexValue = i
+				break
+			}
+		}
+	}
+	// invariants
+	if encoders[0] != CharacterSetECI_ISO8859_1 {
+		panic("IllegalStateException: ISO-8859-1 encoder must be the first")
+	}
+
+	return &ECIEncoderSet{
+		encoders:             encoders,
+		priorityEncoderIndex: priorityEncoderIndexValue,
+	}
+}
+
+func isUnicodeCharset(charset *CharacterSetECI) bool {
+	return charset == CharacterSetECI_UTF8 || charset == CharacterSetECI_UnicodeBigUnmarked
+}
+
+func charsetCanEncode(charset encoding.Encoding, c rune) bool {
+	if c == utf8.RuneError {
+		return false
+	}
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], c)
+	_, e := charset.NewEncoder().Bytes(buf[:n])
+	return e == nil
+}
+
+func (this *ECIEncoderSet) Length() int {
+	return len(this.encoders)
+}
+
+func (this *ECIEncoderSet) GetCharsetName(index int) string {
+	return this.encoders[index].Name()
+}
+
+func (this *ECIEncoderSet) GetCharset(index int) *CharacterSetECI {
+	return this.encoders[index]
+}
+
+func (this *ECIEncoderSet) GetECIValue(encoderIndex int) int {
+	return this.encoders[encoderIndex].GetValue()
+}
+
+// GetPriorityEncoderIndex returns -1 if no priority charset was defined
+func (this *ECIEncoderSet) GetPriorityEncoderIndex() int {
+	return this.priorityEncoderIndex
+}
+
+func (this *ECIEncoderSet) CanEncode(c rune, encoderIndex int) bool {
+	return charsetCanEncode(this.encoders[encoderIndex].GetCharset(), c)
+}
+
+func (this *ECIEncoderSet) EncodeRune(c rune, encoderIndex int) ([]byte, error) {
+	return this.Encode(string(c), encoderIndex)
+}
+
+func (this *ECIEncoderSet) Encode(s string, encoderIndex int) ([]byte, error) {
+	return this.encoders[encoderIndex].GetCharset().NewEncoder().Bytes([]byte(s))
+}
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testECIEncoderSetCharsets(t testing.TB, set *ECIEncoderSet, expects []*CharacterSetECI) {
+	t.Helper()
+	if r := set.Length(); r != len(expects) {
+		t.Fatalf("Length = %v, expect %v", r, len(expects))
+	}
+	for i, expect := range expects {
+		if r := set.GetCharset(i); r != expect {
+			t.Fatalf("GetCharset(%v) = %v, expect %v", i, r.Name(), expect.Name())
+		}
+		if r := set.GetCharsetName(i); r != expect.Name() {
+			t.Fatalf("GetCharsetName(%v) = %v, expect %v", i, r, expect.Name())
+		}
+		if r := set.GetECIValue(i); r != expect.GetValue() {
+			t.Fatalf("GetECIValue(%v) = %v, expect %v", i, r, expect.GetValue())
+		}
+	}
+}
+
+func TestNewECIEncoderSet(t *testing.T) {
+	set := NewECIEncoderSet("abcÀ", nil, -1)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{CharacterSetECI_ISO8859_1})
+	if r := set.GetPriorityEncoderIndex(); r != -1 {
+		t.Fatalf("GetPriorityEncoderIndex = %v, expect -1", r)
+	}
+
+	set = NewECIEncoderSet("ŐŐŜ", nil, -1)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{
+		CharacterSetECI_ISO8859_1, CharacterSetECI_ISO8859_2, CharacterSetECI_ISO8859_3,
+		CharacterSetECI_UTF8, CharacterSetECI_UnicodeBigUnmarked,
+	})
+
+	set = NewECIEncoderSet("日本", nil, -1)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{
+		CharacterSetECI_ISO8859_1, CharacterSetECI_SJIS, CharacterSetECI_UTF8, CharacterSetECI_UnicodeBigUnmarked,
+	})
+
+	set = NewECIEncoderSet("🍣", nil, -1)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{
+		CharacterSetECI_ISO8859_1, CharacterSetECI_UTF8, CharacterSetECI_UnicodeBigUnmarked,
+	})
+
+	set = NewECIEncoderSet("abc", CharacterSetECI_UTF8, -1)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{
+		CharacterSetECI_ISO8859_1, CharacterSetECI_UTF8, CharacterSetECI_UnicodeBigUnmarked,
+	})
+	if r := set.GetPriorityEncoderIndex(); r != 1 {
+		t.Fatalf("GetPriorityEncoderIndex = %v, expect 1", r)
+	}
+
+	set = NewECIEncoderSet("abc", CharacterSetECI_SJIS, -1)
+	if r := set.GetPriorityEncoderIndex(); r != -1 {
+		t.Fatalf("GetPriorityEncoderIndex = %v, expect -1", r)
+	}
+
+	// FNC1 character does not require any encoder
+	set = NewECIEncoderSet("abcĀ", nil, 0x100)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{CharacterSetECI_ISO8859_1})
+}
+
+func TestNewECIEncoderSetWithUnicode(t *testing.T) {
+	set := NewECIEncoderSetWithUnicode("abc", nil, -1)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{CharacterSetECI_ISO8859_1})
+
+	set = NewECIEncoderSetWithUnicode("abcÀ", nil, -1)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{
+		CharacterSetECI_ISO8859_1, CharacterSetECI_UTF8, CharacterSetECI_UnicodeBigUnmarked,
+	})
+
+	set = NewECIEncoderSetWithUnicode("ŐŐŜ", nil, -1)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{
+		CharacterSetECI_ISO8859_1, CharacterSetECI_ISO8859_2, CharacterSetECI_ISO8859_3,
+		CharacterSetECI_UTF8, CharacterSetECI_UnicodeBigUnmarked,
+	})
+
+	set = NewECIEncoderSetWithUnicode("abcÀ", CharacterSetECI_ISO8859_1, -1)
+	if r := set.GetPriorityEncoderIndex(); r != 0 {
+		t.Fatalf("GetPriorityEncoderIndex = %v, expect 0", r)
+	}
+
+	// FNC1 character does not require any encoder
+	set = NewECIEncoderSetWithUnicode("abcĀ", nil, 0x100)
+	testECIEncoderSetCharsets(t, set, []*CharacterSetECI{CharacterSetECI_ISO8859_1})
+}
+
+func TestECIEncoderSet_Encode(t *testing.T) {
+	set := NewECIEncoderSet("aŐ", nil, -1)
+
+	if !set.CanEncode('a', 0) {
+		t.Fatalf("CanEncode('a', 0) must be true")
+	}
+	if set.CanEncode('Ő', 0) {
+		t.Fatalf("CanEncode('Ő', 0) must be false")
+	}
+	if !set.CanEncode('Ő', 1) {
+		t.Fatalf("CanEncode('Ő', 1) must be true")
+	}
+
+	b, e := set.EncodeRune('Ő', 1)
+	if e != nil {
+		t.Fatalf("EncodeRune returns error: %v", e)
+	}
+	if expect := []byte{0xd5}; !reflect.DeepEqual(b, expect) {
+		t.Fatalf("EncodeRune = %v, expect %v", b, expect)
+	}
+
+	b, e = set.Encode("aŐ", 3)
+	if e != nil {
+		t.Fatalf("Encode returns error: %v", e)
+	}
+	if expect := []byte{0x00, 0x61, 0x01, 0x50}; !reflect.DeepEqual(b, expect) {
+		t.Fatalf("Encode = %v, expect %v", b, expect)
+	}
+
+	_, e = set.Encode("aŐ", 0)
+	if e == nil {
+		t.Fatalf("Encode must be error")
+	}
+}
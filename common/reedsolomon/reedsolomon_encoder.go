@@ -1,11 +1,16 @@
 package reedsolomon
 
 import (
+	"sync"
+
 	errors "golang.org/x/xerrors"
 )
 
+// ReedSolomonEncoder is safe for concurrent use by multiple goroutines.
 type ReedSolomonEncoder struct {
-	field            *GenericGF
+	field *GenericGF
+
+	mutex            sync.Mutex // guards cachedGenerators
 	cachedGenerators []*GenericGFPoly
 }
 
@@ -18,6 +23,8 @@ func NewReedSolomonEncoder(field *GenericGF) *ReedSolomonEncoder {
 }
 
 func (this *ReedSolomonEncoder) buildGenerator(degree int) *GenericGFPoly {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
 	size := len(this.cachedGenerators)
 	if degree >= size {
 		lastGenerator := this.cachedGenerators[size-1]
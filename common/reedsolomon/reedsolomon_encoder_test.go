@@ -2,6 +2,7 @@ package reedsolomon
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -67,3 +68,36 @@ func TestReedSolomonEncoder_Encode(t *testing.T) {
 		t.Fatalf("Encode result %v, expect %v", toEncode, expect)
 	}
 }
+
+func TestReedSolomonEncoder_Concurrent(t *testing.T) {
+	field := GenericGF_QR_CODE_FIELD_256
+	data := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+	const n = 30
+	expects := make([][]int, n)
+	for ecBytes := 1; ecBytes < n; ecBytes++ {
+		expects[ecBytes] = make([]int, len(data)+ecBytes)
+		copy(expects[ecBytes], data)
+		_ = NewReedSolomonEncoder(field).Encode(expects[ecBytes], ecBytes)
+	}
+
+	// the generators are built by the goroutines at the same time
+	enc := NewReedSolomonEncoder(field)
+	var wg sync.WaitGroup
+	for ecBytes := n - 1; ecBytes > 0; ecBytes-- {
+		wg.Add(1)
+		go func(ecBytes int) {
+			defer wg.Done()
+			toEncode := make([]int, len(data)+ecBytes)
+			copy(toEncode, data)
+			if e := enc.Encode(toEncode, ecBytes); e != nil {
+				t.Errorf("Encode(%v) returns error, %v", ecBytes, e)
+				return
+			}
+			if !reflect.DeepEqual(toEncode, expects[ecBytes]) {
+				t.Errorf("Encode(%v) result %v, expect %v", ecBytes, toEncode, expects[ecBytes])
+			}
+		}(ecBytes)
+	}
+	wg.Wait()
+}
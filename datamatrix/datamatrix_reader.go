@@ -23,18 +23,28 @@ func NewDataMatrixReader() *DataMatrixReader {
 	}
 }
 
+func (r *DataMatrixReader) GetDecoder() *decoder.Decoder {
+	return r.decoder
+}
+
 func (r *DataMatrixReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
 	return r.Decode(image, nil)
 }
 
 // Decode Locates and decodes a Data Matrix code in an image.
 //
+// If the ALSO_INVERTED hint is set, the inverted image is also tried.
+//
 // @return a String representing the content encoded by the Data Matrix code
 // @throws NotFoundException if a Data Matrix code cannot be found
 // @throws FormatException if a Data Matrix code cannot be decoded
 // @throws ChecksumException if error correction fails
 //
 func (r *DataMatrixReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return gozxing.Reader_DecodeAlsoInverted(image, hints, r.decode)
+}
+
+func (r *DataMatrixReader) decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
 	var decoderResult *common.DecoderResult
 	var points []gozxing.ResultPoint
 	if _, ok := hints[gozxing.DecodeHintType_PURE_BARCODE]; ok {
@@ -70,6 +80,16 @@ func (r *DataMatrixReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing
 		}
 		points = detectorResult.GetPoints()
 	}
+	return DataMatrixReader_CreateResult(decoderResult, points), nil
+}
+
+// DataMatrixReader_CreateResult Creates the result of the decoded Data Matrix code with its metadata.
+//
+// @param decoderResult the result of the decoder
+// @param points the points of the code in the image
+// @return the result
+//
+func DataMatrixReader_CreateResult(decoderResult *common.DecoderResult, points []gozxing.ResultPoint) *gozxing.Result {
 	result := gozxing.NewResult(decoderResult.GetText(), decoderResult.GetRawBytes(), points,
 		gozxing.BarcodeFormat_DATA_MATRIX)
 	byteSegments := decoderResult.GetByteSegments()
@@ -81,7 +101,16 @@ func (r *DataMatrixReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing
 		result.PutMetadata(gozxing.ResultMetadataType_ERROR_CORRECTION_LEVEL, ecLevel)
 	}
 	result.PutMetadata(gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER, "]d"+strconv.Itoa(decoderResult.GetSymbologyModifier()))
-	return result, nil
+	if segments := decoderResult.GetSegments(); len(segments) > 0 {
+		result.PutMetadata(gozxing.ResultMetadataType_DECODED_SEGMENTS, segments)
+	}
+	if version, ok := decoderResult.GetOther().(*decoder.Version); ok {
+		result.PutMetadata(gozxing.ResultMetadataType_SYMBOL_VERSION, version.GetVersionNumber())
+		result.PutMetadata(gozxing.ResultMetadataType_SYMBOL_ROWS, version.GetSymbolSizeRows())
+		result.PutMetadata(gozxing.ResultMetadataType_SYMBOL_COLUMNS, version.GetSymbolSizeColumns())
+		result.PutMetadata(gozxing.ResultMetadataType_DMRE, version.IsDMRE())
+	}
+	return result
 }
 
 func (r *DataMatrixReader) Reset() {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
 	"github.com/makiuchi-d/gozxing/testutil"
 )
 
@@ -244,6 +245,9 @@ func TestDataMatrixReader_Decode(t *testing.T) {
 			"testdata/0123456789.png", "0123456789", nil,
 			map[gozxing.ResultMetadataType]interface{}{
 				gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER: "]d1",
+				gozxing.ResultMetadataType_DECODED_SEGMENTS: []*common.DecodedSegment{
+					common.NewDecodedSegment("BASE256_ENCODE", -1, []byte("0123456789"), 0, 10),
+				},
 			},
 		},
 		{"testdata/C40.png", "Testing C40", pure, nil},
@@ -255,14 +259,30 @@ func TestDataMatrixReader_Decode(t *testing.T) {
 		{"testdata/HelloWorld_Text_L_Kaywa_3_error_byte.png", "Hello World", pure, nil},
 		{"testdata/HelloWorld_Text_L_Kaywa_4_error_byte.png", "Hello World", pure, nil},
 		{"testdata/X12.png", "X12X12X12X12", pure, nil},
-		{"testdata/abcd-18x8.png", "abcde", pure, nil},
+		{
+			"testdata/abcd-18x8.png", "abcde", pure,
+			map[gozxing.ResultMetadataType]interface{}{
+				gozxing.ResultMetadataType_SYMBOL_VERSION: 25,
+				gozxing.ResultMetadataType_SYMBOL_ROWS:    8,
+				gozxing.ResultMetadataType_SYMBOL_COLUMNS: 18,
+				gozxing.ResultMetadataType_DMRE:           false,
+			},
+		},
 		{"testdata/abcd-26x12.png", "abcdefghijklm", pure, nil},
 		{"testdata/abcd-32x8.png", "abcdef", pure, nil},
 		{"testdata/abcd-36x12.png", "abcdefghijklmnopq", pure, nil},
 		{"testdata/abcd-36x16.png", "abcdefghijklmnopqrstuvwxyz", pure, nil},
 		{"testdata/abcd-48x16.png", "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVW", pure, nil},
 		{"testdata/abcd-52x52-IDAutomation.png", "abcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcd", pure, nil},
-		{"testdata/abcd-52x52.png", "abcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcd", pure, nil},
+		{
+			"testdata/abcd-52x52.png", "abcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcd", pure,
+			map[gozxing.ResultMetadataType]interface{}{
+				gozxing.ResultMetadataType_SYMBOL_VERSION: 15,
+				gozxing.ResultMetadataType_SYMBOL_ROWS:    52,
+				gozxing.ResultMetadataType_SYMBOL_COLUMNS: 52,
+				gozxing.ResultMetadataType_DMRE:           false,
+			},
+		},
 		{"testdata/abcdefg-64x64.png", "" +
 			"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890!@#$%^&*(),./\\" +
 			"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890!@#$%^&*(),./\\" +
@@ -295,7 +315,15 @@ func TestDataMatrixReader_Decode(t *testing.T) {
 		{"testdata/3/abcd-36x20.png", "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijkl", nil, nil},
 		{"testdata/3/abcd-40x26.png", "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxy", pure, nil},
 		{"testdata/3/abcd-44x20.png", "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcd", pure, nil},
-		{"testdata/3/abcd-48x8.png", "abcdefghijklmnopqrstuvwxy", nil, nil},
+		{
+			"testdata/3/abcd-48x8.png", "abcdefghijklmnopqrstuvwxy", nil,
+			map[gozxing.ResultMetadataType]interface{}{
+				gozxing.ResultMetadataType_SYMBOL_VERSION: 31,
+				gozxing.ResultMetadataType_SYMBOL_ROWS:    8,
+				gozxing.ResultMetadataType_SYMBOL_COLUMNS: 48,
+				gozxing.ResultMetadataType_DMRE:           true,
+			},
+		},
 		{"testdata/3/abcd-48x22.png", "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzab", nil, nil},
 		{"testdata/3/abcd-48x24.png", "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmn", nil, nil},
 		{"testdata/3/abcd-48x26.png", "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabc", nil, nil},
@@ -315,3 +343,34 @@ func TestDataMatrixReader_Decode(t *testing.T) {
 		testutil.TestFile(t, reader, test.file, test.wants, format, test.hints, test.metadata)
 	}
 }
+
+func TestDataMatrixReader_DecodeAlsoInverted(t *testing.T) {
+	file := "testdata/GUID.png"
+	bmp, _ := testutil.NewBinaryBitmapFromFile(file).Invert()
+	reader := NewDataMatrixReader()
+
+	if _, e := reader.Decode(bmp, nil); e == nil {
+		t.Fatalf("Decode(inverted %s) without ALSO_INVERTED must be error", file)
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	result, e := reader.Decode(bmp, hints)
+	if e != nil {
+		t.Fatalf("Decode(inverted %s) returns error, %v", file, e)
+	}
+	if txt, expect := result.GetText(), "10f27ce-acb7-4e4e-a7ae-a0b98da6ed4a"; txt != expect {
+		t.Fatalf("Decode(inverted %s) = \"%v\", expect \"%v\"", file, txt, expect)
+	}
+	if inv := result.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; inv != true {
+		t.Fatalf("Decode(inverted %s) INVERTED = %v, expect true", file, inv)
+	}
+}
+
+func TestDataMatrixReader_Concurrent(t *testing.T) {
+	testutil.TestConcurrentDecode(t, NewDataMatrixReader(),
+		[]string{"testdata/0123456789.png", "testdata/GUID.png"},
+		[]string{"0123456789", "10f27ce-acb7-4e4e-a7ae-a0b98da6ed4a"},
+		nil)
+}
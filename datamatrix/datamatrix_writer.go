@@ -1,7 +1,12 @@
 package datamatrix
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
 	"github.com/makiuchi-d/gozxing/datamatrix/encoder"
 	qrencoder "github.com/makiuchi-d/gozxing/qrcode/encoder"
 )
@@ -39,6 +44,10 @@ func (this *DataMatrixWriter) Encode(contents string, format gozxing.BarcodeForm
 	shape := encoder.SymbolShapeHint_FORCE_NONE
 	var minSize *gozxing.Dimension
 	var maxSize *gozxing.Dimension
+	var charset *common.CharacterSetECI
+	gs1 := false
+	compact := false
+	forcedEncodation := -1
 	if hints != nil {
 		if val, ok := hints[gozxing.EncodeHintType_DATA_MATRIX_SHAPE]; ok {
 			if requestedShape, ok := val.(encoder.SymbolShapeHint); ok {
@@ -55,10 +64,59 @@ func (this *DataMatrixWriter) Encode(contents string, format gozxing.BarcodeForm
 				maxSize = requestedMaxSize
 			}
 		}
+		// The character set other than ISO-8859-1 is designated by ECI.
+		// Note that DataMatrixReader only detects ECI (the symbology identifier "]d4") and decodes
+		// its assignment number as ASCII data, so the text is not converted from the character set.
+		if val, ok := hints[gozxing.EncodeHintType_CHARACTER_SET]; ok {
+			eci, ok := common.GetCharacterSetECIByName(fmt.Sprintf("%v", val))
+			if !ok {
+				return nil, gozxing.NewWriterException(
+					"IllegalArgumentException: Unsupported character set: %v", val)
+			}
+			charset = eci
+		}
+		if val, ok := hints[gozxing.EncodeHintType_GS1_FORMAT]; ok {
+			switch v := val.(type) {
+			case bool:
+				gs1 = v
+			case string:
+				gs1, _ = strconv.ParseBool(v)
+			}
+		}
+		if val, ok := hints[gozxing.EncodeHintType_DATA_MATRIX_COMPACT]; ok {
+			switch v := val.(type) {
+			case bool:
+				compact = v
+			case string:
+				compact, _ = strconv.ParseBool(v)
+			}
+		}
+		if val, ok := hints[gozxing.EncodeHintType_DATA_MATRIX_FORCE_ENCODATION]; ok {
+			var e error
+			forcedEncodation, e = parseEncodation(val)
+			if e != nil {
+				return nil, e
+			}
+		}
 	}
 
 	//1. step: Data encodation
-	encoded, e := encoder.EncodeHighLevel(contents, shape, minSize, maxSize)
+	var encoded []byte
+	var e error
+	if compact {
+		if forcedEncodation >= 0 {
+			return nil, gozxing.NewWriterException(
+				"IllegalArgumentException: DATA_MATRIX_COMPACT and DATA_MATRIX_FORCE_ENCODATION are exclusive")
+		}
+		fnc1 := -1
+		if gs1 {
+			fnc1 = 0x1d
+		}
+		encoded, e = encoder.MinimalEncoder_EncodeHighLevel(contents, charset, fnc1, shape, minSize, maxSize)
+	} else {
+		encoded, e = encoder.EncodeHighLevelWithParams(
+			contents, shape, minSize, maxSize, charset, gs1, forcedEncodation)
+	}
 	if e != nil {
 		return nil, e
 	}
@@ -77,6 +135,37 @@ func (this *DataMatrixWriter) Encode(contents string, format gozxing.BarcodeForm
 	return encodeLowLevel(placement, symbolInfo, width, height), nil
 }
 
+// parseEncodation Parse the forced encodation hint value.
+//
+// @param val int value of encoder.HighLevelEncoder_*_ENCODATION or its name ("C40", "X12", etc.)
+// @return the encodation mode
+//
+func parseEncodation(val interface{}) (int, error) {
+	switch v := val.(type) {
+	case int:
+		if v >= encoder.HighLevelEncoder_ASCII_ENCODATION && v <= encoder.HighLevelEncoder_BASE256_ENCODATION {
+			return v, nil
+		}
+	case string:
+		switch strings.ToUpper(v) {
+		case "ASCII":
+			return encoder.HighLevelEncoder_ASCII_ENCODATION, nil
+		case "C40":
+			return encoder.HighLevelEncoder_C40_ENCODATION, nil
+		case "TEXT":
+			return encoder.HighLevelEncoder_TEXT_ENCODATION, nil
+		case "X12":
+			return encoder.HighLevelEncoder_X12_ENCODATION, nil
+		case "EDIFACT":
+			return encoder.HighLevelEncoder_EDIFACT_ENCODATION, nil
+		case "BASE256":
+			return encoder.HighLevelEncoder_BASE256_ENCODATION, nil
+		}
+	}
+	return -1, gozxing.NewWriterException(
+		"IllegalArgumentException: Unsupported encodation: %v", val)
+}
+
 // encodeLowLevel Encode the given symbol info to a bit matrix.
 //
 // @param placement  The DataMatrix placement.
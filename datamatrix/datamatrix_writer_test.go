@@ -1,9 +1,14 @@
 package datamatrix
 
 import (
+	"bytes"
+	"math/rand"
 	"testing"
 
+	"golang.org/x/text/encoding/charmap"
+
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
 	"github.com/makiuchi-d/gozxing/datamatrix/encoder"
 	qrencoder "github.com/makiuchi-d/gozxing/qrcode/encoder"
 	"github.com/makiuchi-d/gozxing/testutil"
@@ -209,3 +214,303 @@ func TestDataMatrixWriter_Encode(t *testing.T) {
 		t.Fatalf("result = \"%v\", expect \"%v\"", txt, contents)
 	}
 }
+
+func TestParseEncodation(t *testing.T) {
+	tests := []struct {
+		val    interface{}
+		expect int
+	}{
+		{encoder.HighLevelEncoder_ASCII_ENCODATION, encoder.HighLevelEncoder_ASCII_ENCODATION},
+		{encoder.HighLevelEncoder_BASE256_ENCODATION, encoder.HighLevelEncoder_BASE256_ENCODATION},
+		{"ASCII", encoder.HighLevelEncoder_ASCII_ENCODATION},
+		{"C40", encoder.HighLevelEncoder_C40_ENCODATION},
+		{"text", encoder.HighLevelEncoder_TEXT_ENCODATION},
+		{"X12", encoder.HighLevelEncoder_X12_ENCODATION},
+		{"EDIFACT", encoder.HighLevelEncoder_EDIFACT_ENCODATION},
+		{"Base256", encoder.HighLevelEncoder_BASE256_ENCODATION},
+	}
+	for _, test := range tests {
+		r, e := parseEncodation(test.val)
+		if e != nil {
+			t.Fatalf("parseEncodation(%v) returns error: %v", test.val, e)
+		}
+		if r != test.expect {
+			t.Fatalf("parseEncodation(%v) = %v, expect %v", test.val, r, test.expect)
+		}
+	}
+
+	for _, val := range []interface{}{-1, 6, "PDF", 1.0} {
+		if _, e := parseEncodation(val); e == nil {
+			t.Fatalf("parseEncodation(%v) must be error", val)
+		}
+	}
+}
+
+func testEncodeDecode(t testing.TB, contents string, hints map[gozxing.EncodeHintType]interface{}) *gozxing.Result {
+	t.Helper()
+	writer := NewDataMatrixWriter()
+	b, e := writer.Encode(contents, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e != nil {
+		t.Fatalf("Encode(%q) returns error: %v", contents, e)
+	}
+	bmp := testutil.NewBinaryBitmapFromBitMatrix(b)
+	result, e := NewDataMatrixReader().Decode(bmp, map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_PURE_BARCODE: true,
+	})
+	if e != nil {
+		t.Fatalf("Decode(%q) returns error: %v", contents, e)
+	}
+	return result
+}
+
+func TestDataMatrixWriter_EncodeWithHints(t *testing.T) {
+	writer := NewDataMatrixWriter()
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_CHARACTER_SET: "unknown",
+	}
+	_, e := writer.Encode("abc", gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e == nil {
+		t.Fatalf("Encode must be error")
+	}
+
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_DATA_MATRIX_FORCE_ENCODATION: "unknown",
+	}
+	_, e = writer.Encode("abc", gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e == nil {
+		t.Fatalf("Encode must be error")
+	}
+
+	// GS1
+	contents := "01034531200000111719112510ABCD1234\x1d2110"
+	for _, gs1 := range []interface{}{true, "true"} {
+		hints = map[gozxing.EncodeHintType]interface{}{
+			gozxing.EncodeHintType_GS1_FORMAT: gs1,
+		}
+		result := testEncodeDecode(t, contents, hints)
+		// leading FNC1 is decoded as GS
+		if txt := result.GetText(); txt != "\x1d"+contents {
+			t.Fatalf("result = %q, expect %q", txt, "\x1d"+contents)
+		}
+		meta := result.GetResultMetadata()
+		if id := meta[gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER]; id != "]d2" {
+			t.Fatalf("symbology identifier = %v, expect ]d2", id)
+		}
+	}
+
+	// forced encodation
+	contents = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	for _, forced := range []interface{}{"ASCII", "C40", "TEXT", "X12", "EDIFACT", "BASE256"} {
+		hints = map[gozxing.EncodeHintType]interface{}{
+			gozxing.EncodeHintType_DATA_MATRIX_FORCE_ENCODATION: forced,
+			gozxing.EncodeHintType_GS1_FORMAT:                   false,
+		}
+		result := testEncodeDecode(t, contents, hints)
+		if txt := result.GetText(); txt != contents {
+			t.Fatalf("result(%v) = %q, expect %q", forced, txt, contents)
+		}
+	}
+
+	// charset
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_CHARACTER_SET: "ISO-8859-7",
+	}
+	b, e := writer.Encode("Ελληνικά", gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e != nil {
+		t.Fatalf("Encode returns error: %v", e)
+	}
+	if w, h := b.GetWidth(), b.GetHeight(); w != 16 || h != 16 {
+		t.Fatalf("Encode size = %vx%v, expect 16x16", w, h)
+	}
+	// the reader only detects ECI, and the text is not converted from the character set
+	result := testEncodeDecode(t, "Ελληνικά", hints)
+	if id := result.GetResultMetadata()[gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER]; id != "]d4" {
+		t.Fatalf("symbology identifier = %v, expect ]d4", id)
+	}
+	payload, _ := charmap.ISO8859_7.NewEncoder().Bytes([]byte("Ελληνικά"))
+	if b := testDecodedBytes(result); !bytes.HasSuffix(b, payload) {
+		t.Fatalf("decoded bytes = %v, expect to end with %v", b, payload)
+	}
+}
+
+// testDecodedBytes returns the bytes of the decoded segments,
+// which are not converted by any character set unlike the text.
+func testDecodedBytes(result *gozxing.Result) []byte {
+	segments, _ := result.GetResultMetadata()[gozxing.ResultMetadataType_DECODED_SEGMENTS].([]*common.DecodedSegment)
+	b := make([]byte, 0)
+	for _, segment := range segments {
+		b = append(b, segment.GetBytes()...)
+	}
+	return b
+}
+
+func TestDataMatrixWriter_EncodeForcedRoundTrip(t *testing.T) {
+	tests := []struct {
+		contents string
+		forced   string
+		gs1      bool
+	}{
+		{"\"\ré", "TEXT", false},
+		{"é#*é", "C40", true},
+		{"é#*é", "TEXT", true},
+		{"C>\r\rBé", "C40", true},
+		{"C>\r\rBé", "TEXT", true},
+		{" !>*!>\"é", "C40", true},
+		{"B\"0*é", "TEXT", false},
+		{"ÿ ß\"\r\x1dé{z Ba9ÿ", "C40", false},
+		{"B >é", "X12", true},
+		{"9z>0é#9# \u0080\x1d", "EDIFACT", false},
+		{"ABCDEF12", "BASE256", false},
+	}
+	for _, test := range tests {
+		testEncodeDecodeBytes(t, test.contents, test.forced, test.gs1)
+	}
+
+	// random contents with the non-ASCII, CR and GS characters
+	chars := []rune("AB09az !>*\"#\r\x1déßÿ~`{ÁÉ\u00a0\u0080")
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		contents := make([]rune, 1+r.Intn(30))
+		for j := range contents {
+			contents[j] = chars[r.Intn(len(chars))]
+		}
+		for _, forced := range []string{"ASCII", "C40", "TEXT", "X12", "EDIFACT", "BASE256"} {
+			testEncodeDecodeBytes(t, string(contents), forced, false)
+			testEncodeDecodeBytes(t, string(contents), forced, true)
+		}
+	}
+}
+
+func testEncodeDecodeBytes(t testing.TB, contents, forced string, gs1 bool) {
+	t.Helper()
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_DATA_MATRIX_FORCE_ENCODATION: forced,
+		gozxing.EncodeHintType_GS1_FORMAT:                   gs1,
+	}
+	result := testEncodeDecode(t, contents, hints)
+	expect, _ := charmap.ISO8859_1.NewEncoder().Bytes([]byte(contents))
+	if gs1 {
+		// leading FNC1 is decoded as GS
+		expect = append([]byte{0x1d}, expect...)
+	}
+	if b := testDecodedBytes(result); !bytes.Equal(b, expect) {
+		t.Fatalf("decoded bytes(%q, %v, gs1=%v) = %q, expect %q", contents, forced, gs1, b, expect)
+	}
+}
+
+func TestDataMatrixWriter_EncodeCompact(t *testing.T) {
+	writer := NewDataMatrixWriter()
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_DATA_MATRIX_COMPACT:          true,
+		gozxing.EncodeHintType_DATA_MATRIX_FORCE_ENCODATION: "C40",
+	}
+	_, e := writer.Encode("abc", gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e == nil {
+		t.Fatalf("Encode must be error")
+	}
+
+	contents := "àáâãäåæçèéêëìíîïabcdefghijklmn"
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_DATA_MATRIX_SHAPE: encoder.SymbolShapeHint_FORCE_SQUARE,
+	}
+	b, e := writer.Encode(contents, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e != nil {
+		t.Fatalf("Encode returns error: %v", e)
+	}
+	if w := b.GetWidth(); w != 24 {
+		t.Fatalf("Encode width = %v, expect 24", w)
+	}
+	hints[gozxing.EncodeHintType_DATA_MATRIX_COMPACT] = "true"
+	b, e = writer.Encode(contents, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e != nil {
+		t.Fatalf("Encode returns error: %v", e)
+	}
+	if w := b.GetWidth(); w != 22 {
+		t.Fatalf("Encode compact width = %v, expect 22", w)
+	}
+
+	contents = "ABC>*\r0123456789XYZabcdefgh...."
+	result := testEncodeDecode(t, contents, hints)
+	if txt := result.GetText(); txt != contents {
+		t.Fatalf("result = %q, expect %q", txt, contents)
+	}
+
+	// GS1
+	contents = "01034531200000111719112510ABCD1234\x1d2110"
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_DATA_MATRIX_COMPACT: true,
+		gozxing.EncodeHintType_GS1_FORMAT:          true,
+	}
+	result = testEncodeDecode(t, contents, hints)
+	if txt := result.GetText(); txt != "\x1d"+contents {
+		t.Fatalf("result = %q, expect %q", txt, "\x1d"+contents)
+	}
+}
+
+func TestDataMatrixWriter_EncodeDMRE(t *testing.T) {
+	writer := NewDataMatrixWriter()
+	contents := "ABCDEFGHIJKLMNOP" // 16 codewords
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_DATA_MATRIX_SHAPE: encoder.SymbolShapeHint_FORCE_RECTANGLE,
+	}
+	b, e := writer.Encode(contents, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e != nil {
+		t.Fatalf("Encode returns error: %v", e)
+	}
+	if w, h := b.GetWidth(), b.GetHeight(); w != 26 || h != 12 {
+		t.Fatalf("Encode size = %vx%v, expect 26x12", w, h)
+	}
+
+	hints[gozxing.EncodeHintType_DATA_MATRIX_SHAPE] = encoder.SymbolShapeHint_FORCE_RECTANGLE_DMRE
+	maxSize, _ := gozxing.NewDimension(144, 8)
+	hints[gozxing.EncodeHintType_MAX_SIZE] = maxSize
+	b, e = writer.Encode(contents, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e != nil {
+		t.Fatalf("Encode returns error: %v", e)
+	}
+	if w, h := b.GetWidth(), b.GetHeight(); w != 48 || h != 8 {
+		t.Fatalf("Encode size = %vx%v, expect 48x8", w, h)
+	}
+
+	sizes := [][]int{
+		{48, 8}, {64, 8}, {80, 8}, {96, 8}, {120, 8}, {144, 8}, {64, 12}, {88, 12}, {64, 16},
+		{36, 20}, {44, 20}, {64, 20}, {48, 22}, {48, 24}, {64, 24}, {40, 26}, {48, 26}, {64, 26},
+	}
+	for _, size := range sizes {
+		dim, _ := gozxing.NewDimension(size[0], size[1])
+		for _, shape := range []encoder.SymbolShapeHint{
+			encoder.SymbolShapeHint_FORCE_NONE_DMRE, encoder.SymbolShapeHint_FORCE_RECTANGLE_DMRE} {
+			hints := map[gozxing.EncodeHintType]interface{}{
+				gozxing.EncodeHintType_DATA_MATRIX_SHAPE: shape,
+				gozxing.EncodeHintType_MIN_SIZE:          dim,
+				gozxing.EncodeHintType_MAX_SIZE:          dim,
+			}
+			b, e := writer.Encode(contents, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+			if e != nil {
+				t.Fatalf("Encode(%vx%v) returns error: %v", size[0], size[1], e)
+			}
+			if w, h := b.GetWidth(), b.GetHeight(); w != size[0] || h != size[1] {
+				t.Fatalf("Encode size = %vx%v, expect %vx%v", w, h, size[0], size[1])
+			}
+			result := testEncodeDecode(t, contents, hints)
+			if txt := result.GetText(); txt != contents {
+				t.Fatalf("result(%vx%v) = %q, expect %q", size[0], size[1], txt, contents)
+			}
+		}
+	}
+
+	// DMRE is not used with the classic shape hints
+	dim, _ := gozxing.NewDimension(48, 8)
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_DATA_MATRIX_SHAPE: encoder.SymbolShapeHint_FORCE_RECTANGLE,
+		gozxing.EncodeHintType_MIN_SIZE:          dim,
+		gozxing.EncodeHintType_MAX_SIZE:          dim,
+	}
+	_, e = writer.Encode(contents, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+	if e == nil {
+		t.Fatalf("Encode must be error")
+	}
+}
@@ -395,8 +395,8 @@ func (p *BitMatrixParser) readCorner4(numRows, numColumns int) byte {
 // @return BitMatrix that has the alignment patterns removed
 //
 func extractDataRegion(version *Version, bitMatrix *gozxing.BitMatrix) (*gozxing.BitMatrix, error) {
-	symbolSizeRows := version.getSymbolSizeRows()
-	symbolSizeColumns := version.getSymbolSizeColumns()
+	symbolSizeRows := version.GetSymbolSizeRows()
+	symbolSizeColumns := version.GetSymbolSizeColumns()
 
 	if bitMatrix.GetHeight() != symbolSizeRows {
 		return nil, gozxing.NewFormatException(
@@ -93,7 +93,7 @@ func TestReadVersion(t *testing.T) {
 	if e != nil {
 		t.Fatalf("readVersion returns error, %v", e)
 	}
-	if n := v.getVersionNumber(); n != 3 {
+	if n := v.GetVersionNumber(); n != 3 {
 		t.Fatalf("readVersion verNum = %v, expect 3", n)
 	}
 }
@@ -171,7 +171,7 @@ func TestReadCorner1(t *testing.T) {
 	// datamatrix ver 3
 	dm, _ := gozxing.NewBitMatrix(14, 14)
 	parser, _ := NewBitMatrixParser(dm)
-	if n := parser.GetVersion().getVersionNumber(); n != 3 {
+	if n := parser.GetVersion().GetVersionNumber(); n != 3 {
 		t.Fatalf("DataMatrix version = %v, expect 3", n)
 	}
 	parser.mappingBitMatrix.Set(10, 0)
@@ -192,7 +192,7 @@ func TestReadCorner2(t *testing.T) {
 	// datamatrix ver 4
 	dm, _ := gozxing.NewBitMatrix(16, 16)
 	parser, _ := NewBitMatrixParser(dm)
-	if n := parser.GetVersion().getVersionNumber(); n != 4 {
+	if n := parser.GetVersion().GetVersionNumber(); n != 4 {
 		t.Fatalf("DataMatrix version = %v, expect 4", n)
 	}
 	parser.mappingBitMatrix.Set(10, 0)
@@ -213,7 +213,7 @@ func TestReadCorner3(t *testing.T) {
 	// datamatrix ver 25
 	dm, _ := gozxing.NewBitMatrix(18, 8)
 	parser, _ := NewBitMatrixParser(dm)
-	if n := parser.GetVersion().getVersionNumber(); n != 25 {
+	if n := parser.GetVersion().GetVersionNumber(); n != 25 {
 		t.Fatalf("DataMatrix version = %v, expect 25", n)
 	}
 	parser.mappingBitMatrix.Set(13, 0)
@@ -234,7 +234,7 @@ func TestReadCorner4(t *testing.T) {
 	// datamatrix ver 26
 	dm, _ := gozxing.NewBitMatrix(32, 8)
 	parser, _ := NewBitMatrixParser(dm)
-	if n := parser.GetVersion().getVersionNumber(); n != 26 {
+	if n := parser.GetVersion().GetVersionNumber(); n != 26 {
 		t.Fatalf("DataMatrix version = %v, expect 26", n)
 	}
 	parser.mappingBitMatrix.Set(26, 0)
@@ -65,7 +65,7 @@ func DataBlocks_getDataBlocks(rawCodewords []byte, version *Version) ([]DataBloc
 	}
 
 	// Fill out the last data block in the longer ones
-	specialVersion := version.getVersionNumber() == 24
+	specialVersion := version.GetVersionNumber() == 24
 	numLongerBlocks := numResultBlocks
 	if specialVersion {
 		numLongerBlocks = 8
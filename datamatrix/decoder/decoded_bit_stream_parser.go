@@ -77,6 +77,7 @@ func DecodedBitStreamParser_decode(bytes []byte) (*common.DecoderResult, error)
 	result := make([]byte, 0, 100)
 	resultTrailer := make([]byte, 0)
 	byteSegments := make([][]byte, 0, 1)
+	var segments []*common.DecodedSegment
 	mode := Mode_ASCII_ENCODE
 	// Could look directly at 'bytes', if we're sure of not having to account for multi byte values
 	fnc1Positions := intSet{}
@@ -85,6 +86,9 @@ func DecodedBitStreamParser_decode(bytes []byte) (*common.DecoderResult, error)
 
 	for mode != Mode_PDA_ENCODE && bits.Available() > 0 {
 		var e error
+		start := len(result)
+		var raw []byte
+		segmentMode := mode
 		if mode == Mode_ASCII_ENCODE {
 			mode, result, resultTrailer, e = decodeAsciiSegment(bits, result, resultTrailer, fnc1Positions)
 		} else {
@@ -99,6 +103,9 @@ func DecodedBitStreamParser_decode(bytes []byte) (*common.DecoderResult, error)
 				result = decodeEdifactSegment(bits, result)
 			case Mode_BASE256_ENCODE:
 				result, byteSegments, e = decodeBase256Segment(bits, result, byteSegments)
+				if e == nil {
+					raw = byteSegments[len(byteSegments)-1]
+				}
 			case Mode_ECI_ENCODE:
 				isECIencoded = true // ECI detection only, atm continue decoding as ASCII
 			default:
@@ -109,6 +116,13 @@ func DecodedBitStreamParser_decode(bytes []byte) (*common.DecoderResult, error)
 		if e != nil {
 			return nil, e
 		}
+		if len(result) > start {
+			if raw == nil {
+				raw = result[start:]
+			}
+			// ECI is only detected and its value is not decoded, so no ECI value is given to the segment
+			segments = common.DecodedSegment_Append(segments, segmentMode.String(), -1, raw, start, len(result))
+		}
 	}
 	if len(resultTrailer) > 0 {
 		result = append(result, resultTrailer...)
@@ -137,7 +151,9 @@ func DecodedBitStreamParser_decode(bytes []byte) (*common.DecoderResult, error)
 		}
 	}
 
-	return common.NewDecoderResultWithSymbologyModifier(bytes, string(result), byteSegments, "", symbologyModifier), nil
+	decoderResult := common.NewDecoderResultWithSymbologyModifier(bytes, string(result), byteSegments, "", symbologyModifier)
+	decoderResult.SetSegments(segments)
+	return decoderResult, nil
 }
 
 // decodeAsciiSegment See ISO 16022:2006, 5.2.3 and Annex C, Table C.2
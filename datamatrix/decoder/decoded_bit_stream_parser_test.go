@@ -701,3 +701,40 @@ func TestMode_String(t *testing.T) {
 		t.Fatalf("unknown mode string = \"%v\"", s)
 	}
 }
+
+func TestDecodedBitStreamParser_decodeSegments(t *testing.T) {
+	// C40, Text segments
+	bytes := []byte{230, 87, 169, 254, 239, 87, 169}
+	r, e := DecodedBitStreamParser_decode(bytes)
+	if e != nil {
+		t.Fatalf("DecodedBitStreamParser_decode(%v) returns error, %v", bytes, e)
+	}
+	wants := []*common.DecodedSegment{
+		common.NewDecodedSegment("C40_ENCODE", -1, []byte("A!"), 0, 2),
+		common.NewDecodedSegment("TEXT_ENCODE", -1, []byte("a!"), 2, 4),
+	}
+	if segments := r.GetSegments(); !reflect.DeepEqual(segments, wants) {
+		t.Fatalf("DecodedBitStreamParser_decode(%v) segments = %v, expect %v", bytes, segments, wants)
+	}
+
+	// ANSIX12, EDIFACT, BASE256, ASCII, PAD
+	bytes = []byte{
+		238, 7, 48,
+		254, 240, 0xa9, 0x57, 0xc0,
+		231, 219, 208, 233, 253,
+		34, 129, 34, 34,
+	}
+	r, e = DecodedBitStreamParser_decode(bytes)
+	if e != nil {
+		t.Fatalf("DecodedBitStreamParser_decode(%v) returns error, %v", bytes, e)
+	}
+	wants = []*common.DecodedSegment{
+		common.NewDecodedSegment("ANSIX12_ENCODE", -1, []byte("*1Z"), 0, 3),
+		common.NewDecodedSegment("EDIFACT_ENCODE", -1, []byte("*U"), 3, 5),
+		common.NewDecodedSegment("BASE256_ENCODE", -1, []byte{0x62, 0xe5, 0x64}, 5, 9),
+		common.NewDecodedSegment("ASCII_ENCODE", -1, []byte("!"), 9, 10),
+	}
+	if segments := r.GetSegments(); !reflect.DeepEqual(segments, wants) {
+		t.Fatalf("DecodedBitStreamParser_decode(%v) segments = %v, expect %v", bytes, segments, wants)
+	}
+}
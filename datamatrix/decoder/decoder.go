@@ -23,7 +23,7 @@ func NewDecoder() *Decoder {
 // "true" is taken to mean a black module.
 //
 // @param image booleans representing white/black Data Matrix Code modules
-// @return text and bytes encoded within the Data Matrix Code
+// @return text and bytes encoded within the Data Matrix Code, with the Version as the other
 // @throws FormatException if the Data Matrix Code cannot be decoded
 // @throws ChecksumException if error correction fails
 //
@@ -39,7 +39,7 @@ func (d *Decoder) DecodeBoolMap(image [][]bool) (*common.DecoderResult, error) {
 // A 1 or "true" is taken to mean a black module.
 //
 // @param bits booleans representing white/black Data Matrix Code modules
-// @return text and bytes encoded within the Data Matrix Code
+// @return text and bytes encoded within the Data Matrix Code, with the Version as the other
 // @throws FormatException if the Data Matrix Code cannot be decoded
 // @throws ChecksumException if error correction fails
 //
@@ -84,7 +84,12 @@ func (d *Decoder) Decode(bits *gozxing.BitMatrix) (*common.DecoderResult, error)
 	}
 
 	// Decode the contents of that stream of bytes
-	return DecodedBitStreamParser_decode(resultBytes)
+	result, e := DecodedBitStreamParser_decode(resultBytes)
+	if e != nil {
+		return nil, e
+	}
+	result.SetOther(version)
+	return result, nil
 }
 
 // correctErrors Given data and error-correction codewords received, possibly corrupted by errors,
@@ -50,6 +50,13 @@ func TestDecode(t *testing.T) {
 	if s := r.GetText(); s != expect {
 		t.Fatalf("Decode text = \"%v\", expect \"%v\"", s, expect)
 	}
+	v, ok := r.GetOther().(*Version)
+	if !ok {
+		t.Fatalf("Decode result other must be Version, %T", r.GetOther())
+	}
+	if rows, cols := v.GetSymbolSizeRows(), v.GetSymbolSizeColumns(); rows != bits.GetHeight() || cols != bits.GetWidth() {
+		t.Fatalf("Decode version size = %vx%v, expect %vx%v", rows, cols, bits.GetHeight(), bits.GetWidth())
+	}
 
 	// error collection
 	bits.SetRegion(3, 3, 5, 5)
@@ -42,18 +42,23 @@ func NewVersion(
 	return this
 }
 
-func (v *Version) getVersionNumber() int {
+func (v *Version) GetVersionNumber() int {
 	return v.versionNumber
 }
 
-func (v *Version) getSymbolSizeRows() int {
+func (v *Version) GetSymbolSizeRows() int {
 	return v.symbolSizeRows
 }
 
-func (v *Version) getSymbolSizeColumns() int {
+func (v *Version) GetSymbolSizeColumns() int {
 	return v.symbolSizeColumns
 }
 
+// IsDMRE returns true if the version is one of the rectangular extension (DMRE) sizes
+func (v *Version) IsDMRE() bool {
+	return v.versionNumber > 30
+}
+
 func (v *Version) getDataRegionSizeRows() int {
 	return v.dataRegionSizeRows
 }
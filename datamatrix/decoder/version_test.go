@@ -17,13 +17,13 @@ func testVersion(t testing.TB, row, col int,
 		t.Fatalf("getVersionForDimensions(%v, %v) returns error, %v", row, col, e)
 	}
 
-	if r := v.getVersionNumber(); r != verNum {
+	if r := v.GetVersionNumber(); r != verNum {
 		t.Fatalf("versions(%v,%v) versionNumber = %v, expect %v", row, col, r, verNum)
 	}
-	if r := v.getSymbolSizeRows(); r != symbolRow {
+	if r := v.GetSymbolSizeRows(); r != symbolRow {
 		t.Fatalf("versions(%v,%v) symbolSizeRow = %v, expect %v", row, col, r, symbolRow)
 	}
-	if r := v.getSymbolSizeColumns(); r != symbolCol {
+	if r := v.GetSymbolSizeColumns(); r != symbolCol {
 		t.Fatalf("versions(%v,%v) symbolSizeColumns = %v, expect %v", row, col, r, symbolCol)
 	}
 	if r := v.getDataRegionSizeRows(); r != dataRegionRow {
@@ -72,4 +72,13 @@ func TestVersion(t *testing.T) {
 
 	// version 30
 	testVersion(t, 16, 48, 30, 16, 48, 14, 22, 77, 28, []ECB{{1, 49}}, "30")
+
+	v, _ := getVersionForDimensions(16, 48)
+	if v.IsDMRE() {
+		t.Fatalf("version 30 must not be DMRE")
+	}
+	v, _ = getVersionForDimensions(8, 48)
+	if !v.IsDMRE() {
+		t.Fatalf("version 31 must be DMRE")
+	}
 }
@@ -25,7 +25,12 @@ func (this ASCIIEncoder) encode(context *EncoderContext) error {
 		context.pos += 2
 	} else {
 		c := context.GetCurrentChar()
-		newMode := HighLevelEncoder_lookAheadTest(context.GetMessage(), context.pos, this.getEncodingMode())
+		if context.isFNC1(c) {
+			context.WriteCodeword(HighLevelEncoder_FUNC1)
+			context.pos++
+			return nil
+		}
+		newMode := context.lookAheadTest(this.getEncodingMode())
 		if newMode != this.getEncodingMode() {
 			switch newMode {
 			case HighLevelEncoder_BASE256_ENCODATION:
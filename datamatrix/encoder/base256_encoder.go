@@ -16,21 +16,26 @@ func (this Base256Encoder) getEncodingMode() int {
 
 func (this Base256Encoder) encode(context *EncoderContext) error {
 	buffer := make([]byte, 0)
-	buffer = append(buffer, []byte{0, 0}...) //Initialize length field
+	buffer = append(buffer, 0) //Initialize length field
 	for context.HasMoreCharacters() {
 		c := context.GetCurrentChar()
+		if context.isFNC1(c) {
+			// FNC1 is not available in Base 256 encodation
+			context.SignalEncoderChange(HighLevelEncoder_ASCII_ENCODATION)
+			break
+		}
 		buffer = append(buffer, c)
 
 		context.pos++
 
-		newMode := HighLevelEncoder_lookAheadTest(context.GetMessage(), context.pos, this.getEncodingMode())
+		newMode := context.lookAheadTest(this.getEncodingMode())
 		if newMode != this.getEncodingMode() {
 			// Return to ASCII encodation, which will actually handle latch to new mode
 			context.SignalEncoderChange(HighLevelEncoder_ASCII_ENCODATION)
 			break
 		}
 	}
-	dataCount := len(buffer) - 2
+	dataCount := len(buffer) - 1
 	lengthFieldSize := 1
 	currentSize := context.GetCodewordCount() + dataCount + lengthFieldSize
 	e := context.UpdateSymbolInfoByLength(currentSize)
@@ -40,10 +45,11 @@ func (this Base256Encoder) encode(context *EncoderContext) error {
 	mustPad := (context.GetSymbolInfo().GetDataCapacity() - currentSize) > 0
 	if context.HasMoreCharacters() || mustPad {
 		if dataCount <= 249 {
-			buffer = buffer[1:]
 			buffer[0] = byte(dataCount)
 		} else if dataCount <= 1555 {
 			buffer[0] = byte((dataCount / 250) + 249)
+			buffer = append(buffer, 0)
+			copy(buffer[2:], buffer[1:])
 			buffer[1] = byte(dataCount % 250)
 		} else {
 			return gozxing.NewWriterException(
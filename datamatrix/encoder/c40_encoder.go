@@ -28,32 +28,34 @@ func (this *C40Encoder) encode(context *EncoderContext) error {
 		context.pos++
 
 		var lastCharSize int
-		lastCharSize, buffer = this.encodeChar(c, buffer)
+		lastCharSize, buffer = this.encodeContextChar(context, c, buffer)
 
-		unwritten := (len(buffer) / 3) * 2
-
-		curCodewordCount := context.GetCodewordCount() + unwritten
-		e := context.UpdateSymbolInfoByLength(curCodewordCount)
+		available, e := c40Available(context, buffer)
 		if e != nil {
-			return gozxing.WrapWriterException(e)
+			return e
 		}
-		available := context.GetSymbolInfo().GetDataCapacity() - curCodewordCount
 
 		if !context.HasMoreCharacters() {
 			//Avoid having a single C40 value in the last triplet
 			removed := make([]byte, 0)
 			if (len(buffer)%3) == 2 && available != 2 {
 				lastCharSize, buffer, removed = this.backtrackOneCharacter(context, buffer, removed, lastCharSize)
+				if available, e = c40Available(context, buffer); e != nil {
+					return e
+				}
 			}
-			for (len(buffer)%3) == 1 && (lastCharSize > 3 || available != 1) {
+			for (len(buffer)%3) == 1 && (lastCharSize > 2 || available != 1) {
 				lastCharSize, buffer, removed = this.backtrackOneCharacter(context, buffer, removed, lastCharSize)
+				if available, e = c40Available(context, buffer); e != nil {
+					return e
+				}
 			}
 			break
 		}
 
 		count := len(buffer)
 		if (count % 3) == 0 {
-			newMode := HighLevelEncoder_lookAheadTest(context.GetMessage(), context.pos, this.getEncodingMode())
+			newMode := context.lookAheadTest(this.getEncodingMode())
 			if newMode != this.getEncodingMode() {
 				// Return to ASCII encodation, which will actually handle latch to new mode
 				context.SignalEncoderChange(HighLevelEncoder_ASCII_ENCODATION)
@@ -72,11 +74,38 @@ func (this *C40Encoder) backtrackOneCharacter(context *EncoderContext,
 	buffer = buffer[:count-lastCharSize]
 	context.pos--
 	c := context.GetCurrentChar()
-	lastCharSize, removed = this.encodeChar(c, removed)
+	_, removed = this.encodeContextChar(context, c, removed)
 	context.ResetSymbolInfo() //Deal with possible reduction in symbol size
+
+	// the size of the character at the end of the buffer now, which is backtracked next
+	lastCharSize = 0
+	if len(buffer) > 0 {
+		lastCharSize, _ = this.encodeContextChar(context, context.GetMessage()[context.pos-1], nil)
+	}
 	return lastCharSize, buffer, removed
 }
 
+// encodeContextChar encodes the character, or the FNC1 if the character is designated in the context.
+func (this *C40Encoder) encodeContextChar(context *EncoderContext, c byte, sb []byte) (int, []byte) {
+	if context.isFNC1(c) {
+		sb = append(sb, 1, 27) //Shift 2 Set, FNC1
+		return 2, sb
+	}
+	return this.encodeChar(c, sb)
+}
+
+// c40Available returns the number of the codewords left in the symbol after the buffer is written.
+func c40Available(context *EncoderContext, buffer []byte) (int, error) {
+	unwritten := (len(buffer) / 3) * 2
+
+	curCodewordCount := context.GetCodewordCount() + unwritten
+	e := context.UpdateSymbolInfoByLength(curCodewordCount)
+	if e != nil {
+		return 0, gozxing.WrapWriterException(e)
+	}
+	return context.GetSymbolInfo().GetDataCapacity() - curCodewordCount, nil
+}
+
 func c40WriteNextTriplet(context *EncoderContext, buffer []byte) []byte {
 	context.WriteCodewords(c40EncodeToCodewords(buffer))
 	return buffer[3:]
@@ -88,15 +117,12 @@ func c40WriteNextTriplet(context *EncoderContext, buffer []byte) []byte {
 // @param buffer  the buffer with the remaining encoded characters
 //
 func c40HandleEOD(context *EncoderContext, buffer []byte) error {
-	unwritten := (len(buffer) / 3) * 2
 	rest := len(buffer) % 3
 
-	curCodewordCount := context.GetCodewordCount() + unwritten
-	e := context.UpdateSymbolInfoByLength(curCodewordCount)
+	available, e := c40Available(context, buffer)
 	if e != nil {
-		return gozxing.WrapWriterException(e)
+		return e
 	}
-	available := context.GetSymbolInfo().GetDataCapacity() - curCodewordCount
 
 	if rest == 2 {
 		buffer = append(buffer, 0) //Shift 1
@@ -123,6 +123,10 @@ func (this *DefaultPlacement) module(row, col, pos, bit int) {
 		col += this.numcols
 		row += 4 - ((this.numcols + 4) % 8)
 	}
+	if row >= this.numrows {
+		// this can happen with DMRE symbols
+		row -= this.numrows
+	}
 	// Note the conversion:
 	v := this.codewords[pos]
 	v &= 1 << uint(8-bit)
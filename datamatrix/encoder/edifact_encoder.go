@@ -32,7 +32,7 @@ func (this EdifactEncoder) encode(context *EncoderContext) error {
 			context.WriteCodewords(codewords)
 			buffer = buffer[4:]
 
-			newMode := HighLevelEncoder_lookAheadTest(context.GetMessage(), context.pos, this.getEncodingMode())
+			newMode := context.lookAheadTest(this.getEncodingMode())
 			if newMode != this.getEncodingMode() {
 				// Return to ASCII encodation, which will actually handle latch to new mode
 				context.SignalEncoderChange(HighLevelEncoder_ASCII_ENCODATION)
@@ -64,7 +64,7 @@ func edifactHandleEOD(context *EncoderContext, buffer []byte) error {
 		}
 
 		available := context.GetSymbolInfo().GetDataCapacity() - context.GetCodewordCount()
-		remaining := context.GetRemainingCharacters()
+		remaining := context.getRemainingASCIICodewordCount()
 		// The following two lines are a hack inspired by the 'fix' from https://sourceforge.net/p/barcode4j/svn/221/
 		if remaining > available {
 			e := context.UpdateSymbolInfoByLength(context.GetCodewordCount() + 1)
@@ -1,29 +1,38 @@
 package encoder
 
 import (
+	textencoding "golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
 
 	"github.com/makiuchi-d/gozxing"
 )
 
 type EncoderContext struct {
-	msg         []byte
-	shape       SymbolShapeHint
-	minSize     *gozxing.Dimension
-	maxSize     *gozxing.Dimension
-	codewords   []byte
-	pos         int
-	newEncoding int
-	symbolInfo  *SymbolInfo
-	skipAtEnd   int
+	msg              []byte
+	shape            SymbolShapeHint
+	minSize          *gozxing.Dimension
+	maxSize          *gozxing.Dimension
+	codewords        []byte
+	pos              int
+	newEncoding      int
+	symbolInfo       *SymbolInfo
+	skipAtEnd        int
+	fnc1             int
+	forcedEncodation int
+	forcedLatchPos   int
 }
 
 func NewEncoderContext(msg string) (*EncoderContext, error) {
+	return NewEncoderContextWithCharset(msg, charmap.ISO8859_1)
+}
+
+// NewEncoderContextWithCharset creates the context with the message encoded in the given charset.
+func NewEncoderContextWithCharset(msg string, charset textencoding.Encoding) (*EncoderContext, error) {
 	//From this point on Strings are not Unicode anymore!
-	msgBinary, e := charmap.ISO8859_1.NewEncoder().Bytes([]byte(msg))
+	msgBinary, e := charset.NewEncoder().Bytes([]byte(msg))
 	if e != nil {
 		return nil, gozxing.NewWriterException(
-			"Message contains characters outside ISO-8859-1 encoding. %v", e)
+			"Message contains characters outside %v encoding. %v", charset, e)
 	}
 	sb := make([]byte, 0, len(msgBinary))
 	for i, c := 0, len(msgBinary); i < c; i++ {
@@ -31,10 +40,13 @@ func NewEncoderContext(msg string) (*EncoderContext, error) {
 		sb = append(sb, ch)
 	}
 	return &EncoderContext{
-		msg:         sb, //Not Unicode here!
-		shape:       SymbolShapeHint_FORCE_NONE,
-		codewords:   make([]byte, 0, len(sb)),
-		newEncoding: -1,
+		msg:              sb, //Not Unicode here!
+		shape:            SymbolShapeHint_FORCE_NONE,
+		codewords:        make([]byte, 0, len(sb)),
+		newEncoding:      -1,
+		fnc1:             -1,
+		forcedEncodation: -1,
+		forcedLatchPos:   -1,
 	}, nil
 }
 
@@ -47,6 +59,18 @@ func (this *EncoderContext) SetSizeConstraints(minSize, maxSize *gozxing.Dimensi
 	this.maxSize = maxSize
 }
 
+// SetFNC1Character sets the character to be encoded as FNC1 (e.g. GS for GS1 format),
+// or -1 for none.
+func (this *EncoderContext) SetFNC1Character(fnc1 int) {
+	this.fnc1 = fnc1
+}
+
+// SetForcedEncodation sets the encodation to be used as long as it can encode the message,
+// or -1 to select the encodation by the look-ahead test.
+func (this *EncoderContext) SetForcedEncodation(encodation int) {
+	this.forcedEncodation = encodation
+}
+
 func (this *EncoderContext) GetMessage() []byte {
 	return this.msg
 }
@@ -63,6 +87,10 @@ func (this *EncoderContext) GetCurrent() byte {
 	return this.msg[this.pos]
 }
 
+func (this *EncoderContext) isFNC1(c byte) bool {
+	return this.fnc1 >= 0 && int(c) == this.fnc1
+}
+
 func (this *EncoderContext) GetCodewords() []byte {
 	return this.codewords
 }
@@ -103,6 +131,19 @@ func (this *EncoderContext) GetRemainingCharacters() int {
 	return this.getTotalMessageCharCount() - this.pos
 }
 
+// getRemainingASCIICodewordCount returns the number of the codewords to encode the remaining characters
+// in ASCII encodation, where the extended ASCII characters take 2 codewords with Upper Shift.
+func (this *EncoderContext) getRemainingASCIICodewordCount() int {
+	count := 0
+	for i := this.pos; i < this.getTotalMessageCharCount(); i++ {
+		count++
+		if HighLevelEncoder_isExtendedASCII(this.msg[i]) {
+			count++
+		}
+	}
+	return count
+}
+
 func (this *EncoderContext) GetSymbolInfo() *SymbolInfo {
 	return this.symbolInfo
 }
@@ -122,3 +163,53 @@ func (this *EncoderContext) UpdateSymbolInfoByLength(len int) error {
 func (this *EncoderContext) ResetSymbolInfo() {
 	this.symbolInfo = nil
 }
+
+// lookAheadTest determines the encodation for the characters from the current position.
+// The forced encodation is kept as long as it can encode the following characters.
+func (this *EncoderContext) lookAheadTest(currentMode int) int {
+	if this.forcedEncodation < 0 {
+		return HighLevelEncoder_lookAheadTest(this.msg, this.pos, currentMode)
+	}
+	if !this.HasMoreCharacters() {
+		return currentMode
+	}
+
+	// number of characters encoded before the next mode check
+	n := 1
+	switch this.forcedEncodation {
+	case HighLevelEncoder_X12_ENCODATION:
+		n = 3
+	case HighLevelEncoder_EDIFACT_ENCODATION:
+		n = 4
+	}
+	if currentMode == HighLevelEncoder_ASCII_ENCODATION {
+		// Don't latch for the last few characters which would be returned to ASCII at the end of data,
+		// nor latch again at the position where the previous latch made no progress.
+		if this.GetRemainingCharacters() < 3 || this.pos == this.forcedLatchPos {
+			return HighLevelEncoder_ASCII_ENCODATION
+		}
+	}
+	for i := this.pos; i < this.pos+n && i < this.getTotalMessageCharCount(); i++ {
+		if !this.canEncodeForced(this.msg[i]) {
+			return HighLevelEncoder_ASCII_ENCODATION
+		}
+	}
+	if currentMode == HighLevelEncoder_ASCII_ENCODATION {
+		this.forcedLatchPos = this.pos
+	}
+	return this.forcedEncodation
+}
+
+func (this *EncoderContext) canEncodeForced(c byte) bool {
+	switch this.forcedEncodation {
+	case HighLevelEncoder_C40_ENCODATION, HighLevelEncoder_TEXT_ENCODATION:
+		return true
+	case HighLevelEncoder_X12_ENCODATION:
+		return isNativeX12(c) && !this.isFNC1(c)
+	case HighLevelEncoder_EDIFACT_ENCODATION:
+		return isNativeEDIFACT(c) && !this.isFNC1(c)
+	case HighLevelEncoder_BASE256_ENCODATION:
+		return !this.isFNC1(c)
+	}
+	return false
+}
@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"golang.org/x/text/encoding/charmap"
+
 	"github.com/makiuchi-d/gozxing"
 )
 
@@ -99,6 +101,22 @@ func TestEncoderContext_CharCount(t *testing.T) {
 	}
 }
 
+func TestEncoderContext_getRemainingASCIICodewordCount(t *testing.T) {
+	ctx, _ := NewEncoderContext("abéÿc")
+	if r := ctx.getRemainingASCIICodewordCount(); r != 7 {
+		t.Fatalf("getRemainingASCIICodewordCount = %v, expect 7", r)
+	}
+	ctx.SetSkipAtEnd(1)
+	ctx.pos = 2
+	if r := ctx.getRemainingASCIICodewordCount(); r != 4 {
+		t.Fatalf("getRemainingASCIICodewordCount = %v, expect 4", r)
+	}
+	ctx.pos = 4
+	if r := ctx.getRemainingASCIICodewordCount(); r != 0 {
+		t.Fatalf("getRemainingASCIICodewordCount = %v, expect 0", r)
+	}
+}
+
 func TestEncoderContext_SymbolInfo(t *testing.T) {
 	ctx, _ := NewEncoderContext("abcdefg")
 	ctx.WriteCodewords([]byte("abcdefg"))
@@ -155,3 +173,78 @@ func TestEncoderContext_SymbolInfo(t *testing.T) {
 		t.Fatalf("UpdateSymbolInfoByLength(1559) must be error")
 	}
 }
+
+func TestNewEncoderContextWithCharset(t *testing.T) {
+	_, e := NewEncoderContextWithCharset("日本", charmap.ISO8859_7)
+	if e == nil {
+		t.Fatalf("NewEncoderContextWithCharset must be error")
+	}
+
+	ctx, e := NewEncoderContextWithCharset("Ωμ", charmap.ISO8859_7)
+	if e != nil {
+		t.Fatalf("NewEncoderContextWithCharset returns error: %v", e)
+	}
+	expectmsg := []byte{0xd9, 0xec}
+	if msg := ctx.GetMessage(); !reflect.DeepEqual(msg, expectmsg) {
+		t.Fatalf("NewEncoderContextWithCharset msg = %v, expect %v", msg, expectmsg)
+	}
+}
+
+func TestEncoderContext_isFNC1(t *testing.T) {
+	ctx, _ := NewEncoderContext("a\x1db")
+	if ctx.isFNC1(0x1d) {
+		t.Fatalf("isFNC1(0x1d) must be false")
+	}
+	ctx.SetFNC1Character(0x1d)
+	if !ctx.isFNC1(0x1d) {
+		t.Fatalf("isFNC1(0x1d) must be true")
+	}
+	if ctx.isFNC1('a') {
+		t.Fatalf("isFNC1('a') must be false")
+	}
+}
+
+func TestEncoderContext_lookAheadTest(t *testing.T) {
+	ctx, _ := NewEncoderContext("ABC>ab\x1dABCD")
+	ctx.SetFNC1Character(0x1d)
+
+	expect := HighLevelEncoder_lookAheadTest(ctx.GetMessage(), 0, HighLevelEncoder_ASCII_ENCODATION)
+	if r := ctx.lookAheadTest(HighLevelEncoder_ASCII_ENCODATION); r != expect {
+		t.Fatalf("lookAheadTest = %v, expect %v", r, expect)
+	}
+
+	tests := []struct {
+		forced, pos, current, expect int
+	}{
+		{HighLevelEncoder_C40_ENCODATION, 0, HighLevelEncoder_ASCII_ENCODATION, HighLevelEncoder_C40_ENCODATION},
+		{HighLevelEncoder_TEXT_ENCODATION, 6, HighLevelEncoder_TEXT_ENCODATION, HighLevelEncoder_TEXT_ENCODATION},
+		{HighLevelEncoder_X12_ENCODATION, 0, HighLevelEncoder_ASCII_ENCODATION, HighLevelEncoder_X12_ENCODATION},
+		{HighLevelEncoder_X12_ENCODATION, 2, HighLevelEncoder_ASCII_ENCODATION, HighLevelEncoder_ASCII_ENCODATION},
+		{HighLevelEncoder_EDIFACT_ENCODATION, 0, HighLevelEncoder_ASCII_ENCODATION, HighLevelEncoder_EDIFACT_ENCODATION},
+		{HighLevelEncoder_EDIFACT_ENCODATION, 3, HighLevelEncoder_ASCII_ENCODATION, HighLevelEncoder_ASCII_ENCODATION},
+		{HighLevelEncoder_BASE256_ENCODATION, 5, HighLevelEncoder_BASE256_ENCODATION, HighLevelEncoder_BASE256_ENCODATION},
+		{HighLevelEncoder_BASE256_ENCODATION, 6, HighLevelEncoder_BASE256_ENCODATION, HighLevelEncoder_ASCII_ENCODATION},
+		{HighLevelEncoder_ASCII_ENCODATION, 0, HighLevelEncoder_ASCII_ENCODATION, HighLevelEncoder_ASCII_ENCODATION},
+		// last few characters
+		{HighLevelEncoder_C40_ENCODATION, 9, HighLevelEncoder_ASCII_ENCODATION, HighLevelEncoder_ASCII_ENCODATION},
+		{HighLevelEncoder_C40_ENCODATION, 9, HighLevelEncoder_C40_ENCODATION, HighLevelEncoder_C40_ENCODATION},
+		{HighLevelEncoder_C40_ENCODATION, 11, HighLevelEncoder_C40_ENCODATION, HighLevelEncoder_C40_ENCODATION},
+	}
+	for _, test := range tests {
+		ctx.SetForcedEncodation(test.forced)
+		ctx.forcedLatchPos = -1
+		ctx.pos = test.pos
+		if r := ctx.lookAheadTest(test.current); r != test.expect {
+			t.Fatalf("lookAheadTest(forced=%v, pos=%v, current=%v) = %v, expect %v",
+				test.forced, test.pos, test.current, r, test.expect)
+		}
+	}
+
+	// no progress since the last latch
+	ctx.SetForcedEncodation(HighLevelEncoder_C40_ENCODATION)
+	ctx.pos = 0
+	ctx.lookAheadTest(HighLevelEncoder_ASCII_ENCODATION)
+	if r := ctx.lookAheadTest(HighLevelEncoder_ASCII_ENCODATION); r != HighLevelEncoder_ASCII_ENCODATION {
+		t.Fatalf("lookAheadTest = %v, expect %v", r, HighLevelEncoder_ASCII_ENCODATION)
+	}
+}
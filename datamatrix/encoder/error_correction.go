@@ -8,7 +8,7 @@ var (
 	// Lookup table which factors to use for which number of error correction codewords.
 	// See FACTORS.
 	factorSets = []int{
-		5, 7, 10, 11, 12, 14, 18, 20, 24, 28, 36, 42, 48, 56, 62, 68,
+		5, 7, 10, 11, 12, 14, 15, 18, 20, 22, 24, 27, 28, 32, 34, 36, 38, 41, 42, 46, 48, 50, 56, 62, 68,
 	}
 
 	// Precomputed polynomial factors for ECC 200.
@@ -19,21 +19,44 @@ var (
 		{175, 138, 205, 12, 194, 168, 39, 245, 60, 97, 120},
 		{41, 153, 158, 91, 61, 42, 142, 213, 97, 178, 100, 242},
 		{156, 97, 192, 252, 95, 9, 157, 119, 138, 45, 18, 186, 83, 185},
+		{116, 88, 33, 148, 201, 88, 153, 176, 234, 145, 35, 159, 139, 223, 93},
 		{83, 195, 100, 39, 188, 75, 66, 61, 241, 213, 109, 129, 94, 254, 225, 48, 90, 188},
 		{15, 195, 244, 9, 233, 71, 168, 2, 188, 160, 153, 145, 253, 79, 108, 82, 27, 174, 186, 172},
+		{75, 249, 76, 201, 95, 241, 5, 123, 71, 94, 27, 132, 1, 152, 38, 10,
+			125, 217, 209, 3, 188, 236},
 		{52, 190, 88, 205, 109, 39, 176, 21, 155, 197, 251, 223, 155, 21, 5, 172,
 			254, 124, 12, 181, 184, 96, 50, 193},
+		{215, 183, 130, 96, 182, 147, 97, 74, 143, 205, 47, 229, 29, 123, 55, 65,
+			244, 246, 210, 108, 72, 134, 233, 246, 161, 180, 232},
 		{211, 231, 43, 97, 71, 96, 103, 174, 37, 151, 170, 53, 75, 34, 249, 121,
 			17, 138, 110, 213, 141, 136, 120, 151, 233, 168, 93, 255},
+		{227, 184, 159, 130, 228, 199, 107, 95, 236, 21, 61, 58, 209, 120, 2, 38,
+			211, 202, 103, 19, 104, 145, 2, 249, 174, 144, 95, 55, 234, 163, 129, 104},
+		{190, 197, 231, 218, 210, 172, 71, 234, 203, 95, 42, 103, 237, 116, 90, 95,
+			143, 6, 189, 114, 107, 62, 154, 62, 152, 237, 138, 89, 14, 67, 189, 179,
+			4, 139},
 		{245, 127, 242, 218, 130, 250, 162, 181, 102, 120, 84, 179, 220, 251, 80, 182,
 			229, 18, 2, 4, 68, 33, 101, 137, 95, 119, 115, 44, 175, 184, 59, 25,
 			225, 98, 81, 112},
+		{109, 30, 101, 141, 239, 222, 166, 90, 102, 168, 237, 74, 233, 143, 123, 203,
+			41, 172, 103, 139, 211, 55, 183, 83, 215, 122, 223, 170, 134, 80, 128, 220,
+			169, 166, 241, 165, 181, 235},
+		{35, 21, 123, 106, 43, 2, 142, 6, 222, 194, 48, 53, 99, 216, 193, 172,
+			136, 101, 120, 156, 250, 137, 144, 84, 165, 90, 211, 208, 132, 133, 15, 224,
+			244, 79, 71, 81, 38, 68, 249, 220, 149},
 		{77, 193, 137, 31, 19, 38, 22, 153, 247, 105, 122, 2, 245, 133, 242, 8,
 			175, 95, 100, 9, 167, 105, 214, 111, 57, 121, 21, 1, 253, 57, 54, 101,
 			248, 202, 69, 50, 150, 177, 226, 5, 9, 5},
+		{195, 29, 197, 200, 113, 135, 114, 178, 160, 232, 186, 35, 129, 111, 51, 47,
+			61, 11, 29, 135, 171, 83, 221, 33, 206, 130, 127, 47, 77, 21, 43, 72,
+			138, 173, 118, 144, 98, 40, 178, 141, 62, 114, 235, 74, 62, 78},
 		{245, 132, 172, 223, 96, 32, 117, 22, 238, 133, 238, 231, 205, 188, 237, 87,
 			191, 106, 16, 147, 118, 23, 37, 90, 170, 205, 131, 88, 120, 100, 66, 138,
 			186, 240, 82, 44, 176, 87, 187, 147, 160, 175, 69, 213, 92, 253, 225, 19},
+		{1, 166, 254, 165, 43, 167, 102, 150, 123, 245, 74, 222, 223, 36, 230, 112,
+			16, 9, 142, 14, 241, 251, 114, 65, 156, 205, 14, 80, 76, 243, 241, 8,
+			185, 25, 28, 112, 231, 79, 153, 16, 74, 156, 183, 212, 218, 167, 91, 162,
+			54, 74},
 		{175, 9, 223, 238, 12, 17, 220, 208, 100, 29, 175, 170, 230, 192, 215, 235,
 			150, 159, 36, 223, 38, 200, 132, 54, 228, 146, 218, 234, 117, 203, 29, 232,
 			144, 238, 22, 150, 201, 117, 62, 207, 164, 13, 137, 245, 127, 67, 247, 28,
@@ -3,6 +3,8 @@ package encoder
 import (
 	"reflect"
 	"testing"
+
+	"github.com/makiuchi-d/gozxing/common/reedsolomon"
 )
 
 func TestCreateECCBlock(t *testing.T) {
@@ -21,6 +23,27 @@ func TestCreateECCBlock(t *testing.T) {
 	}
 }
 
+func TestCreateECCBlock_AllFactorSets(t *testing.T) {
+	field := reedsolomon.GenericGF_DATA_MATRIX_FIELD_256
+	data := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 100, 200, 255}
+	for _, n := range factorSets {
+		ecc, e := createECCBlock(data, n)
+		if e != nil {
+			t.Fatalf("createECCBlock(%v) returns error: %v", n, e)
+		}
+		codewords := make([]int, 0, len(data)+n)
+		for _, b := range append(append([]byte{}, data...), ecc...) {
+			codewords = append(codewords, int(b))
+		}
+		poly, _ := reedsolomon.NewGenericGFPoly(field, codewords)
+		for i := 0; i < n; i++ {
+			if s := poly.EvaluateAt(field.Exp(i + field.GetGeneratorBase())); s != 0 {
+				t.Fatalf("createECCBlock(%v) syndrome[%v] = %v, expect 0", n, i, s)
+			}
+		}
+	}
+}
+
 func TestEncodeECC200(t *testing.T) {
 	_, e := ErrorCorrection_EncodeECC200([]byte{}, symbols[0])
 	if e == nil {
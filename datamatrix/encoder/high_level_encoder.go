@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
 )
 
 // DataMatrix ECC 200 data encoder following the algorithm described in ISO/IEC 16022:200(E) in annex S.
@@ -20,7 +21,7 @@ const (
 	HighLevelEncoder_LATCH_TO_BASE256 = 231
 
 	// FNC1 Codeword
-	// HighLevelEncoder_FUNC1 = 232
+	HighLevelEncoder_FUNC1 = 232
 
 	// Structured Append Codeword
 	// HighLevelEncoder_STRUCTURED_APPEND = 233
@@ -47,7 +48,7 @@ const (
 	HighLevelEncoder_LATCH_TO_EDIFACT = 240
 
 	// ECI character (Extended Channel Interpretation)
-	// HighLevelEncoder_ECI = 241
+	HighLevelEncoder_ECI = 241
 
 	// Unlatch from C40 encodation
 	HighLevelEncoder_C40_UNLATCH = 254
@@ -92,19 +93,54 @@ func randomize253State(codewordPosition int) byte {
 // @return the encoded message (the char values range from 0 to 255)
 //
 func EncodeHighLevel(msg string, shape SymbolShapeHint, minSize, maxSize *gozxing.Dimension) ([]byte, error) {
+	return EncodeHighLevelWithParams(msg, shape, minSize, maxSize, nil, false, -1)
+}
+
+// EncodeHighLevelWithParams Performs message encoding of a DataMatrix message
+// with the character set, GS1 format and forced encodation.
+//
+// @param msg              the message
+// @param shape            requested shape.
+// @param minSize          the minimum symbol size constraint or nil for no constraint
+// @param maxSize          the maximum symbol size constraint or nil for no constraint
+// @param charset          the character set of the message (ECI), or nil for the default ISO-8859-1
+// @param gs1              if true, a leading FNC1 is emitted and GS characters are encoded as FNC1
+// @param forcedEncodation the encodation (HighLevelEncoder_*_ENCODATION) kept as long as possible, or -1
+// @return the encoded message (the char values range from 0 to 255)
+//
+func EncodeHighLevelWithParams(msg string, shape SymbolShapeHint, minSize, maxSize *gozxing.Dimension,
+	charset *common.CharacterSetECI, gs1 bool, forcedEncodation int) ([]byte, error) {
+
 	//the codewords 0..255 are encoded as Unicode characters
 	encoders := []Encoder{
 		NewASCIIEncoder(), NewC40Encoder(), NewTextEncoder(),
 		NewX12Encoder(), NewEdifactEncoder(), NewBase256Encoder(),
 	}
 
-	context, e := NewEncoderContext(msg)
+	if forcedEncodation >= len(encoders) {
+		return nil, gozxing.NewWriterException(
+			"IllegalArgumentException: Illegal encodation: %v", forcedEncodation)
+	}
+
+	var context *EncoderContext
+	var e error
+	if charset != nil {
+		context, e = NewEncoderContextWithCharset(msg, charset.GetCharset())
+	} else {
+		context, e = NewEncoderContext(msg)
+	}
 	if e != nil {
 		return nil, e
 	}
 	context.SetSymbolShape(shape)
 	context.SetSizeConstraints(minSize, maxSize)
+	context.SetForcedEncodation(forcedEncodation)
 
+	if gs1 {
+		// GS1 formatted data starts with FNC1, and uses GS as the separator
+		context.WriteCodeword(HighLevelEncoder_FUNC1)
+		context.SetFNC1Character(0x1d)
+	}
 	if strings.HasPrefix(msg, HighLevelEncoder_MACRO_05_HEADER) &&
 		strings.HasSuffix(msg, HighLevelEncoder_MACRO_TRAILER) {
 		context.WriteCodeword(HighLevelEncoder_MACRO_05)
@@ -117,9 +153,15 @@ func EncodeHighLevel(msg string, shape SymbolShapeHint, minSize, maxSize *gozxin
 		context.pos += len(HighLevelEncoder_MACRO_06_HEADER)
 	}
 
+	if charset != nil && charset != common.CharacterSetECI_ISO8859_1 {
+		appendECI(context, charset.GetValue())
+	}
+
 	encodingMode := HighLevelEncoder_ASCII_ENCODATION //Default mode
 	for context.HasMoreCharacters() {
-		encoders[encodingMode].encode(context)
+		if e = encoders[encodingMode].encode(context); e != nil {
+			return nil, e
+		}
 		if context.GetNewEncoding() >= 0 {
 			encodingMode = context.GetNewEncoding()
 			context.ResetEncoderSignal()
@@ -151,6 +193,21 @@ func EncodeHighLevel(msg string, shape SymbolShapeHint, minSize, maxSize *gozxin
 	return context.GetCodewords(), nil
 }
 
+// appendECI writes the ECI character and the assignment number. See ISO 16022:2006, 5.4.1
+func appendECI(context *EncoderContext, value int) {
+	context.WriteCodeword(HighLevelEncoder_ECI)
+	if value < 127 {
+		context.WriteCodeword(byte(value + 1))
+	} else if value < 16383 {
+		context.WriteCodeword(byte((value-127)/254 + 128))
+		context.WriteCodeword(byte((value-127)%254 + 1))
+	} else {
+		context.WriteCodeword(byte((value-16383)/64516 + 192))
+		context.WriteCodeword(byte(((value-16383)/254)%254 + 1))
+		context.WriteCodeword(byte((value-16383)%254 + 1))
+	}
+}
+
 func HighLevelEncoder_lookAheadTest(msg []byte, startpos, currentMode int) int {
 	if startpos >= len(msg) {
 		return currentMode
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
 )
 
 func TestRandomize253State(t *testing.T) {
@@ -340,3 +341,85 @@ func TestEncodeHighLevel(t *testing.T) {
 		t.Fatalf("EncodeHighLevel = %v, expect %v", b, expect)
 	}
 }
+
+func TestAppendECI(t *testing.T) {
+	tests := []struct {
+		value  int
+		expect []byte
+	}{
+		{26, []byte{241, 27}},
+		{126, []byte{241, 127}},
+		{127, []byte{241, 128, 1}},
+		{16382, []byte{241, 191, 254}},
+		{16383, []byte{241, 192, 1, 1}},
+		{999999, []byte{241, 207, 63, 129}},
+	}
+	for _, test := range tests {
+		ctx, _ := NewEncoderContext("")
+		appendECI(ctx, test.value)
+		if r := ctx.GetCodewords(); !reflect.DeepEqual(r, test.expect) {
+			t.Fatalf("appendECI(%v) = %v, expect %v", test.value, r, test.expect)
+		}
+	}
+}
+
+func TestEncodeHighLevelWithParams(t *testing.T) {
+	shape := SymbolShapeHint_FORCE_NONE
+
+	_, e := EncodeHighLevelWithParams("abc", shape, nil, nil, nil, false, 6)
+	if e == nil {
+		t.Fatalf("EncodeHighLevelWithParams must be error")
+	}
+
+	_, e = EncodeHighLevelWithParams("日本語", shape, nil, nil, common.CharacterSetECI_ISO8859_7, false, -1)
+	if e == nil {
+		t.Fatalf("EncodeHighLevelWithParams must be error")
+	}
+
+	tests := []struct {
+		msg     string
+		charset *common.CharacterSetECI
+		gs1     bool
+		forced  int
+		expect  []byte
+	}{
+		// GS1
+		{"01034531200000111719112510ABCD1234\x1d2110", nil, true, -1, []byte{
+			232, 131, 133, 175, 161, 150, 130, 130, 141, 147, 149, 141, 155, 140, 66, 67, 68, 69, 142, 164,
+			232, 151, 140, 129, 59, 209, 104, 254, 150, 45}},
+		{"ABC\x1dDEF", nil, true, HighLevelEncoder_C40_ENCODATION, []byte{232, 230, 89, 233, 10, 138, 115, 121}},
+		{"abc\x1ddef", nil, true, HighLevelEncoder_BASE256_ENCODATION, []byte{
+			232, 231, 196, 184, 78, 229, 232, 231, 67, 60, 211, 106}},
+		// ECI
+		{"Ελληνικά", common.CharacterSetECI_ISO8859_7, false, -1, []byte{
+			241, 10, 231, 87, 177, 109, 3, 148, 48, 193, 88, 224}},
+		{"abc", common.CharacterSetECI_ISO8859_1, false, -1, []byte{98, 99, 100}},
+		// forced encodation
+		{"AIMAIMAIM", nil, false, HighLevelEncoder_C40_ENCODATION, []byte{230, 91, 11, 91, 11, 91, 11, 254}},
+		{"AIMAIMAIMA", nil, false, HighLevelEncoder_C40_ENCODATION, []byte{230, 91, 11, 91, 11, 91, 11, 66}},
+		{"aimaimaima", nil, false, HighLevelEncoder_TEXT_ENCODATION, []byte{239, 91, 11, 91, 11, 91, 11, 98}},
+		{"ABC>ab", nil, false, HighLevelEncoder_X12_ENCODATION, []byte{238, 89, 233, 254, 63, 98, 99, 129}},
+		{"ABCDEF", nil, false, HighLevelEncoder_EDIFACT_ENCODATION, []byte{240, 4, 32, 196, 20, 103, 192, 129}},
+		{"abcd", nil, false, HighLevelEncoder_BASE256_ENCODATION, []byte{231, 48, 34, 185, 79, 230, 129, 56}},
+		// the length field is 0 when the data ends at the capacity
+		{"abc", nil, false, HighLevelEncoder_BASE256_ENCODATION, []byte{231, 44, 34, 185, 79}},
+		{"ABCDEF", nil, false, HighLevelEncoder_BASE256_ENCODATION, []byte{231, 44, 2, 153, 47, 198, 93, 243}},
+		{"1234", nil, false, HighLevelEncoder_ASCII_ENCODATION, []byte{142, 164, 129}},
+		{"12ABCDEF", nil, false, HighLevelEncoder_C40_ENCODATION, []byte{142, 230, 89, 233, 109, 36, 254, 129}},
+		// backtrack of the characters with Upper Shift at the end
+		{"\"\ré", nil, false, HighLevelEncoder_TEXT_ENCODATION, []byte{239, 6, 105, 254, 14, 235, 106, 129}},
+		{" !>*!>\"é", nil, true, HighLevelEncoder_C40_ENCODATION, []byte{
+			232, 230, 18, 233, 9, 58, 56, 105, 9, 57, 254, 35, 235, 106, 129, 237}},
+		// unlatch before the character with Upper Shift
+		{"B >é", nil, true, HighLevelEncoder_X12_ENCODATION, []byte{232, 238, 94, 59, 254, 235, 106, 129}},
+	}
+	for _, test := range tests {
+		b, e := EncodeHighLevelWithParams(test.msg, shape, nil, nil, test.charset, test.gs1, test.forced)
+		if e != nil {
+			t.Fatalf("EncodeHighLevelWithParams(%q) returns error: %v", test.msg, e)
+		}
+		if !reflect.DeepEqual(b, test.expect) {
+			t.Fatalf("EncodeHighLevelWithParams(%q) = %v, expect %v", test.msg, b, test.expect)
+		}
+	}
+}
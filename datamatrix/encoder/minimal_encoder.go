@@ -0,0 +1,859 @@
+package encoder
+
+import (
+	"math"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
+)
+
+// Encoder that encodes minimally
+//
+// Algorithm:
+//
+// Uses Dijkstra to produce mathematically minimal encodings that are in some cases smaller than the results produced
+// by the algorithm described in annex S in the specification ISO/IEC 16022:200(E). The biggest improvment of this
+// algorithm over that one is the case when the algorithm enters the most inefficient mode, the B256 mode. The
+// algorithm from the specification algorithm will exit this mode only if it encounters digits so that arbitrarily
+// inefficient results can be produced if the postfix contains no digits.
+//
+// Multi ECI support and ECI switching:
+//
+// The input is encoded in the single character set given by the caller (or ISO-8859-1 by default),
+// and an ECI is emitted in front of the data if the character set is not ISO-8859-1.
+// If no character set is given and the message contains characters outside ISO-8859-1, UTF-8 is used.
+
+var c40Shift2Chars = []byte{
+	'!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', ',', '-', '.',
+	'/', ':', ';', '<', '=', '>', '?', '@', '[', '\\', ']', '^', '_',
+}
+
+func minimalIsExtendedASCII(ch byte, fnc1 int) bool {
+	return int(ch) != fnc1 && ch >= 128
+}
+
+func isInC40Shift1Set(ch byte) bool {
+	return ch <= 31
+}
+
+func isInC40Shift2Set(ch byte, fnc1 int) bool {
+	for _, c40Shift2Char := range c40Shift2Chars {
+		if c40Shift2Char == ch {
+			return true
+		}
+	}
+	return int(ch) == fnc1
+}
+
+func isInTextShift1Set(ch byte) bool {
+	return isInC40Shift1Set(ch)
+}
+
+func isInTextShift2Set(ch byte, fnc1 int) bool {
+	return isInC40Shift2Set(ch, fnc1)
+}
+
+// MinimalEncoder_EncodeHighLevel Performs message encoding of a DataMatrix message
+// producing the minimal number of codewords.
+//
+// @param msg     the message
+// @param charset the character set of the message (ECI), or nil for the default
+// @param fnc1    the character to be encoded as FNC1 (e.g. GS for GS1 format), or -1 for none (a leading FNC1 is also emitted if not -1)
+// @param shape   requested shape.
+// @param minSize the minimum symbol size constraint or nil for no constraint
+// @param maxSize the maximum symbol size constraint or nil for no constraint
+// @return the encoded message (the char values range from 0 to 255)
+//
+func MinimalEncoder_EncodeHighLevel(msg string, charset *common.CharacterSetECI, fnc1 int,
+	shape SymbolShapeHint, minSize, maxSize *gozxing.Dimension) ([]byte, error) {
+
+	macroId := 0
+	if strings.HasPrefix(msg, HighLevelEncoder_MACRO_05_HEADER) &&
+		strings.HasSuffix(msg, HighLevelEncoder_MACRO_TRAILER) {
+		macroId = 5
+		msg = msg[len(HighLevelEncoder_MACRO_05_HEADER) : len(msg)-len(HighLevelEncoder_MACRO_TRAILER)]
+	} else if strings.HasPrefix(msg, HighLevelEncoder_MACRO_06_HEADER) &&
+		strings.HasSuffix(msg, HighLevelEncoder_MACRO_TRAILER) {
+		macroId = 6
+		msg = msg[len(HighLevelEncoder_MACRO_06_HEADER) : len(msg)-len(HighLevelEncoder_MACRO_TRAILER)]
+	}
+
+	var bytes []byte
+	var e error
+	if charset == nil {
+		bytes, e = charmap.ISO8859_1.NewEncoder().Bytes([]byte(msg))
+		if e != nil {
+			charset = common.CharacterSetECI_UTF8
+		}
+	}
+	if charset != nil {
+		bytes, e = charset.GetCharset().NewEncoder().Bytes([]byte(msg))
+		if e != nil {
+			return nil, gozxing.NewWriterException(
+				"Message contains characters outside %v encoding. %v", charset.Name(), e)
+		}
+	}
+
+	input := &minimalInput{
+		bytes:   bytes,
+		fnc1:    fnc1,
+		shape:   shape,
+		minSize: minSize,
+		maxSize: maxSize,
+		macroId: macroId,
+		eci:     -1,
+	}
+	if charset != nil && charset != common.CharacterSetECI_ISO8859_1 {
+		input.eci = charset.GetValue()
+	}
+
+	solution, e := encodeMinimally(input)
+	if e != nil {
+		return nil, e
+	}
+	return newMinimalResult(input, solution)
+}
+
+func addEdge(edges [][]*minimalEdge, edge *minimalEdge) {
+	vertexIndex := edge.fromPosition + edge.characterLength
+	endMode := edge.getEndMode()
+	if edges[vertexIndex][endMode] == nil ||
+		edges[vertexIndex][endMode].cachedTotalSize > edge.cachedTotalSize {
+		edges[vertexIndex][endMode] = edge
+	}
+}
+
+// getNumberOfC40Words returns the number of words in which the string starting at from can be encoded
+// in c40 or text mode, and the number of characters encoded.
+// The number of characters encoded is also minimal in the sense that the algorithm stops as soon
+// as a character encoding fills a C40 word competely (three C40 values). An exception is at the
+// end of the string where two C40 values are allowed (according to the spec the third c40 value
+// is filled  with 0 (Shift 1) in this case).
+func getNumberOfC40Words(input *minimalInput, from int, c40 bool) (int, int) {
+	thirdsCount := 0
+	for i := from; i < input.length(); i++ {
+		ci := input.charAt(i)
+		if c40 && isNativeC40(ci) || !c40 && isNativeText(ci) {
+			thirdsCount++ //native
+		} else if !minimalIsExtendedASCII(ci, input.fnc1) {
+			thirdsCount += 2 //shift
+		} else {
+			asciiValue := ci - 128
+			if c40 && isNativeC40(asciiValue) || !c40 && isNativeText(asciiValue) {
+				thirdsCount += 3 // shift, Upper shift
+			} else {
+				thirdsCount += 4 // shift, Upper shift, shift
+			}
+		}
+
+		if thirdsCount%3 == 0 || ((thirdsCount-2)%3 == 0 && i+1 == input.length()) {
+			return (thirdsCount + 2) / 3, i - from + 1
+		}
+	}
+	return 0, 0
+}
+
+func addEdges(input *minimalInput, edges [][]*minimalEdge, from int, previous *minimalEdge) {
+	ch := input.charAt(from)
+	if previous == nil || previous.getEndMode() != HighLevelEncoder_EDIFACT_ENCODATION {
+		//not possible to unlatch a full EDF edge to something else
+		if HighLevelEncoder_isDigit(ch) && input.haveNCharacters(from, 2) &&
+			HighLevelEncoder_isDigit(input.charAt(from+1)) {
+			// two digits ASCII encoded
+			addEdge(edges, newMinimalEdge(input, HighLevelEncoder_ASCII_ENCODATION, from, 2, previous))
+		} else {
+			// one ASCII encoded character or an extended character via Upper Shift
+			addEdge(edges, newMinimalEdge(input, HighLevelEncoder_ASCII_ENCODATION, from, 1, previous))
+		}
+
+		for _, mode := range []int{HighLevelEncoder_C40_ENCODATION, HighLevelEncoder_TEXT_ENCODATION} {
+			if words, characterLength := getNumberOfC40Words(
+				input, from, mode == HighLevelEncoder_C40_ENCODATION); words > 0 {
+				addEdge(edges, newMinimalEdge(input, mode, from, characterLength, previous))
+			}
+		}
+
+		if input.haveNCharacters(from, 3) &&
+			isNativeX12(input.charAt(from)) &&
+			isNativeX12(input.charAt(from+1)) &&
+			isNativeX12(input.charAt(from+2)) {
+			addEdge(edges, newMinimalEdge(input, HighLevelEncoder_X12_ENCODATION, from, 3, previous))
+		}
+
+		if !input.isFNC1(from) {
+			addEdge(edges, newMinimalEdge(input, HighLevelEncoder_BASE256_ENCODATION, from, 1, previous))
+		}
+	}
+
+	// We create 4 EDF edges,  with 1, 2 3 or 4 characters length. The fourth normally doesn't have a latch to ASCII
+	// unless it is 2 characters away from the end of the input.
+	i := 0
+	for ; i < 3; i++ {
+		pos := from + i
+		if input.haveNCharacters(pos, 1) && isNativeEDIFACT(input.charAt(pos)) && !input.isFNC1(pos) {
+			addEdge(edges, newMinimalEdge(input, HighLevelEncoder_EDIFACT_ENCODATION, from, i+1, previous))
+		} else {
+			break
+		}
+	}
+	if i == 3 && input.haveNCharacters(from, 4) &&
+		isNativeEDIFACT(input.charAt(from+3)) && !input.isFNC1(from+3) {
+		addEdge(edges, newMinimalEdge(input, HighLevelEncoder_EDIFACT_ENCODATION, from, 4, previous))
+	}
+}
+
+// encodeMinimally computes the minimal encoding by Dijkstra.
+//
+// The acyclic graph is modeled as follows:
+// A vertex represents a combination of a position in the input and an encoding mode where position 0
+// denotes the position left of the first character, 1 the position left of the second character and so on.
+// Likewise the end vertices are located after the last character at position input.length().
+// For any position there might be up to six vertices, one for each of the encoding types ASCII, C40, TEXT, X12,
+// EDF and B256.
+//
+// An edge leading to such a vertex encodes one or more of the characters left of the position that the vertex
+// represents. It encodes the characters in the encoding mode of the vertex that it ends on. In other words,
+// all edges leading to a particular vertex encode the same characters (the length of the suffix can vary)
+// using the same encoding mode.
+//
+// The algorithm processes the vertices in order of their position. For every vertex at position i it keeps only
+// the shortest of the edges ending on it, and computes all possible outgoing edges for the vertices at the
+// position. At the end, it chooses the edge with the smallest size from any of the edges leading to vertices
+// at the position input.length().
+func encodeMinimally(input *minimalInput) (*minimalEdge, error) {
+	inputLength := input.length()
+
+	// Array that represents vertices. There is a vertex for every character and mode.
+	// The last dimension in the array below encodes the 6 modes ASCII, C40, TEXT, X12, EDF and B256
+	edges := make([][]*minimalEdge, inputLength+1)
+	for i := range edges {
+		edges[i] = make([]*minimalEdge, 6)
+	}
+	if inputLength == 0 {
+		return nil, nil
+	}
+	addEdges(input, edges, 0, nil)
+
+	for i := 1; i <= inputLength; i++ {
+		for j := 0; j < 6; j++ {
+			if edges[i][j] != nil && i < inputLength {
+				addEdges(input, edges, i, edges[i][j])
+			}
+		}
+		//optimize memory by removing edges that have been passed.
+		for j := 0; j < 6; j++ {
+			edges[i-1][j] = nil
+		}
+	}
+
+	minimalJ := -1
+	minimalSize := math.MaxInt32
+	for j := 0; j < 6; j++ {
+		if edge := edges[inputLength][j]; edge != nil {
+			size := edge.cachedTotalSize
+			if j >= HighLevelEncoder_C40_ENCODATION && j <= HighLevelEncoder_X12_ENCODATION {
+				// C40, TEXT and X12 need an extra unlatch at the end
+				size++
+			}
+			if size < minimalSize {
+				minimalSize = size
+				minimalJ = j
+			}
+		}
+	}
+
+	if minimalJ < 0 {
+		return nil, gozxing.NewWriterException(
+			"IllegalStateException: Internal error: failed to encode \"%v\"", string(input.bytes))
+	}
+	return edges[inputLength][minimalJ], nil
+}
+
+type minimalInput struct {
+	bytes   []byte
+	fnc1    int
+	eci     int // -1 for none
+	shape   SymbolShapeHint
+	minSize *gozxing.Dimension
+	maxSize *gozxing.Dimension
+	macroId int
+}
+
+func (this *minimalInput) length() int {
+	return len(this.bytes)
+}
+
+func (this *minimalInput) charAt(index int) byte {
+	return this.bytes[index]
+}
+
+func (this *minimalInput) haveNCharacters(index, n int) bool {
+	return index+n-1 < len(this.bytes)
+}
+
+func (this *minimalInput) isFNC1(index int) bool {
+	return this.fnc1 >= 0 && int(this.bytes[index]) == this.fnc1
+}
+
+// getPrefixLength returns the number of codewords written in front of the data (FNC1, Macro and ECI).
+func (this *minimalInput) getPrefixLength() int {
+	n := 0
+	if this.fnc1 >= 0 {
+		n++
+	}
+	if this.macroId != 0 {
+		n++
+	}
+	if this.eci >= 0 {
+		ctx, _ := NewEncoderContext("")
+		appendECI(ctx, this.eci)
+		n += ctx.GetCodewordCount()
+	}
+	return n
+}
+
+type minimalEdge struct {
+	input           *minimalInput
+	mode            int //the mode at the start of this edge.
+	fromPosition    int
+	characterLength int
+	previous        *minimalEdge
+	cachedTotalSize int
+}
+
+func newMinimalEdge(input *minimalInput, mode, fromPosition, characterLength int, previous *minimalEdge) *minimalEdge {
+	this := &minimalEdge{
+		input:           input,
+		mode:            mode,
+		fromPosition:    fromPosition,
+		characterLength: characterLength,
+		previous:        previous,
+	}
+
+	size := 0
+	if previous != nil {
+		size = previous.cachedTotalSize
+	} else {
+		size = input.getPrefixLength()
+	}
+
+	previousMode := this.getPreviousMode()
+
+	// Switching modes
+	// ASCII -> C40: latch 230
+	// ASCII -> TEXT: latch 239
+	// ASCII -> X12: latch 238
+	// ASCII -> EDF: latch 240
+	// ASCII -> B256: latch 231
+	// C40 -> ASCII: word(c1,c2,c3), 254
+	// TEXT -> ASCII: word(c1,c2,c3), 254
+	// X12 -> ASCII: word(c1,c2,c3), 254
+	// EDIFACT -> ASCII: Unlatch character,0,0,0 or c1,Unlatch character,0,0 or c1,c2,Unlatch character,0 or
+	// c1,c2,c3,Unlatch character
+	// B256 -> ASCII: without latch after n bytes
+	switch mode {
+	case HighLevelEncoder_ASCII_ENCODATION:
+		size++
+		if minimalIsExtendedASCII(input.charAt(fromPosition), input.fnc1) {
+			size++
+		}
+		if previousMode == HighLevelEncoder_C40_ENCODATION ||
+			previousMode == HighLevelEncoder_TEXT_ENCODATION ||
+			previousMode == HighLevelEncoder_X12_ENCODATION {
+			size++ // unatch 254 to ASCII
+		}
+	case HighLevelEncoder_BASE256_ENCODATION:
+		size++
+		if previousMode != HighLevelEncoder_BASE256_ENCODATION {
+			size++ //byte count
+		} else if this.getB256Size() == 250 {
+			size++ //extra byte count
+		}
+		if previousMode == HighLevelEncoder_ASCII_ENCODATION {
+			size++ //latch to B256
+		} else if previousMode == HighLevelEncoder_C40_ENCODATION ||
+			previousMode == HighLevelEncoder_TEXT_ENCODATION ||
+			previousMode == HighLevelEncoder_X12_ENCODATION {
+			size += 2 //unlatch to ASCII, latch to B256
+		}
+	case HighLevelEncoder_C40_ENCODATION, HighLevelEncoder_TEXT_ENCODATION, HighLevelEncoder_X12_ENCODATION:
+		if mode == HighLevelEncoder_X12_ENCODATION {
+			size += 2
+		} else {
+			words, _ := getNumberOfC40Words(input, fromPosition, mode == HighLevelEncoder_C40_ENCODATION)
+			size += words * 2
+		}
+
+		if previousMode == HighLevelEncoder_ASCII_ENCODATION || previousMode == HighLevelEncoder_BASE256_ENCODATION {
+			size++ //additional byte for latch from ASCII to this mode
+		} else if previousMode != mode && (previousMode == HighLevelEncoder_C40_ENCODATION ||
+			previousMode == HighLevelEncoder_TEXT_ENCODATION ||
+			previousMode == HighLevelEncoder_X12_ENCODATION) {
+			size += 2 //unlatch 254 to ASCII followed by latch to this mode
+		}
+	case HighLevelEncoder_EDIFACT_ENCODATION:
+		size += 3
+		if previousMode == HighLevelEncoder_ASCII_ENCODATION || previousMode == HighLevelEncoder_BASE256_ENCODATION {
+			size++ //additional byte for latch from ASCII to this mode
+		} else if previousMode == HighLevelEncoder_C40_ENCODATION ||
+			previousMode == HighLevelEncoder_TEXT_ENCODATION ||
+			previousMode == HighLevelEncoder_X12_ENCODATION {
+			size += 2 //unlatch 254 to ASCII followed by latch to this mode
+		}
+	}
+	this.cachedTotalSize = size
+	return this
+}
+
+// getB256Size does not count beyond 250
+func (this *minimalEdge) getB256Size() int {
+	cnt := 0
+	current := this
+	for current != nil && current.mode == HighLevelEncoder_BASE256_ENCODATION && cnt <= 250 {
+		cnt++
+		current = current.previous
+	}
+	return cnt
+}
+
+func (this *minimalEdge) getPreviousStartMode() int {
+	if this.previous == nil {
+		return HighLevelEncoder_ASCII_ENCODATION
+	}
+	return this.previous.mode
+}
+
+func (this *minimalEdge) getPreviousMode() int {
+	if this.previous == nil {
+		return HighLevelEncoder_ASCII_ENCODATION
+	}
+	return this.previous.getEndMode()
+}
+
+// getEndMode Returns ASCII encodation in case that:
+//  - Mode is EDIFACT and characterLength is less than 4 or the remaining characters can be encoded in at most 2
+//    ASCII bytes.
+//  - Mode is C40, TEXT or X12 and the remaining characters can be encoded in at most 1 ASCII byte.
+// Returns mode in all other cases.
+func (this *minimalEdge) getEndMode() int {
+	mode := this.mode
+	if mode == HighLevelEncoder_EDIFACT_ENCODATION {
+		if this.characterLength < 4 {
+			return HighLevelEncoder_ASCII_ENCODATION
+		}
+		lastASCII := this.getLastASCII() // see 5.2.8.2 EDIFACT encodation Rules
+		if lastASCII > 0 && this.getCodewordsRemaining(this.cachedTotalSize+lastASCII) <= 2-lastASCII {
+			return HighLevelEncoder_ASCII_ENCODATION
+		}
+	}
+	if mode == HighLevelEncoder_C40_ENCODATION ||
+		mode == HighLevelEncoder_TEXT_ENCODATION ||
+		mode == HighLevelEncoder_X12_ENCODATION {
+
+		// see 5.2.5.2 C40 encodation rules and 5.2.7.2 ANSI X12 encodation rules
+		if this.fromPosition+this.characterLength >= this.input.length() &&
+			this.getCodewordsRemaining(this.cachedTotalSize) == 0 {
+			return HighLevelEncoder_ASCII_ENCODATION
+		}
+		lastASCII := this.getLastASCII()
+		if lastASCII == 1 && this.getCodewordsRemaining(this.cachedTotalSize+1) == 0 {
+			return HighLevelEncoder_ASCII_ENCODATION
+		}
+	}
+	return mode
+}
+
+// getLastASCII Peeks ahead and returns 1 if the postfix consists of exactly two digits,
+// 2 if the postfix consists of exactly two consecutive digits and a non extended character or of 4 digits.
+// Returns 0 in any other case
+func (this *minimalEdge) getLastASCII() int {
+	input := this.input
+	length := input.length()
+	from := this.fromPosition + this.characterLength
+	if length-from > 4 || from >= length {
+		return 0
+	}
+	if length-from == 1 {
+		if minimalIsExtendedASCII(input.charAt(from), input.fnc1) {
+			return 0
+		}
+		return 1
+	}
+	if length-from == 2 {
+		if minimalIsExtendedASCII(input.charAt(from), input.fnc1) ||
+			minimalIsExtendedASCII(input.charAt(from+1), input.fnc1) {
+			return 0
+		}
+		if HighLevelEncoder_isDigit(input.charAt(from)) && HighLevelEncoder_isDigit(input.charAt(from+1)) {
+			return 1
+		}
+		return 2
+	}
+	if length-from == 3 {
+		if HighLevelEncoder_isDigit(input.charAt(from)) && HighLevelEncoder_isDigit(input.charAt(from+1)) &&
+			!minimalIsExtendedASCII(input.charAt(from+2), input.fnc1) {
+			return 2
+		}
+		if HighLevelEncoder_isDigit(input.charAt(from+1)) && HighLevelEncoder_isDigit(input.charAt(from+2)) &&
+			!minimalIsExtendedASCII(input.charAt(from), input.fnc1) {
+			return 2
+		}
+		return 0
+	}
+	if HighLevelEncoder_isDigit(input.charAt(from)) && HighLevelEncoder_isDigit(input.charAt(from+1)) &&
+		HighLevelEncoder_isDigit(input.charAt(from+2)) && HighLevelEncoder_isDigit(input.charAt(from+3)) {
+		return 2
+	}
+	return 0
+}
+
+// getMinSymbolSize Returns the capacity in codewords of the smallest symbol that has enough capacity
+// to fit the given minimal number of codewords.
+func (this *minimalEdge) getMinSymbolSize(minimum int) int {
+	input := this.input
+	symbolInfo, _ := SymbolInfo_Lookup(minimum, input.shape, input.minSize, input.maxSize, false)
+	if symbolInfo == nil {
+		return minimum
+	}
+	return symbolInfo.GetDataCapacity()
+}
+
+// getCodewordsRemaining Returns the remaining capacity in codewords of the smallest symbol that has enough
+// capacity to fit the given minimal number of codewords.
+func (this *minimalEdge) getCodewordsRemaining(minimum int) int {
+	return this.getMinSymbolSize(minimum) - minimum
+}
+
+func setC40Word(bytes []byte, offset int, c1, c2, c3 byte) {
+	val16 := (1600 * int(c1)) + (40 * int(c2)) + int(c3) + 1
+	bytes[offset] = byte(val16 / 256)
+	bytes[offset+1] = byte(val16 % 256)
+}
+
+func getX12Value(c byte) byte {
+	switch {
+	case c == 13:
+		return 0
+	case c == 42:
+		return 1
+	case c == 62:
+		return 2
+	case c == 32:
+		return 3
+	case c >= 48 && c <= 57:
+		return c - 44
+	case c >= 65 && c <= 90:
+		return c - 51
+	}
+	return c
+}
+
+func (this *minimalEdge) getX12Words() []byte {
+	input := this.input
+	result := make([]byte, this.characterLength/3*2)
+	for i := 0; i < len(result); i += 2 {
+		setC40Word(result, i,
+			getX12Value(input.charAt(this.fromPosition+i/2*3)),
+			getX12Value(input.charAt(this.fromPosition+i/2*3+1)),
+			getX12Value(input.charAt(this.fromPosition+i/2*3+2)))
+	}
+	return result
+}
+
+func getShiftValue(c byte, c40 bool, fnc1 int) byte {
+	if int(c) == fnc1 {
+		return 1
+	}
+	if c40 && isInC40Shift1Set(c) || !c40 && isInTextShift1Set(c) {
+		return 0
+	}
+	if c40 && isInC40Shift2Set(c, fnc1) || !c40 && isInTextShift2Set(c, fnc1) {
+		return 1
+	}
+	return 2
+}
+
+func getC40Value(c40 bool, setIndex byte, c byte, fnc1 int) byte {
+	if int(c) == fnc1 {
+		return 27
+	}
+	if c40 {
+		switch {
+		case c <= 31:
+			return c
+		case c == 32:
+			return 3
+		case c <= 47:
+			return c - 33
+		case c <= 57:
+			return c - 44
+		case c <= 64:
+			return c - 43
+		case c <= 90:
+			return c - 51
+		case c <= 95:
+			return c - 69
+		case c <= 127:
+			return c - 96
+		}
+		return c
+	}
+	switch {
+	case c == 0:
+		return 0
+	case setIndex == 0 && c <= 3:
+		return c - 1 //is this a bug in the spec?
+	case setIndex == 1 && c <= 31:
+		return c
+	case c == 32:
+		return 3
+	case c >= 33 && c <= 47:
+		return c - 33
+	case c >= 48 && c <= 57:
+		return c - 44
+	case c >= 58 && c <= 64:
+		return c - 43
+	case c >= 65 && c <= 90:
+		return c - 64
+	case c >= 91 && c <= 95:
+		return c - 69
+	case c == 96:
+		return 0
+	case c >= 97 && c <= 122:
+		return c - 83
+	case c >= 123 && c <= 127:
+		return c - 96
+	}
+	return c
+}
+
+func (this *minimalEdge) getC40Words(c40 bool, fnc1 int) []byte {
+	c40Values := make([]byte, 0, this.characterLength*2)
+	for i := 0; i < this.characterLength; i++ {
+		ci := this.input.charAt(this.fromPosition + i)
+		if c40 && isNativeC40(ci) || !c40 && isNativeText(ci) {
+			c40Values = append(c40Values, getC40Value(c40, 0, ci, fnc1))
+		} else if !minimalIsExtendedASCII(ci, fnc1) {
+			shiftValue := getShiftValue(ci, c40, fnc1)
+			c40Values = append(c40Values, shiftValue) //Shift[123]
+			c40Values = append(c40Values, getC40Value(c40, shiftValue, ci, fnc1))
+		} else {
+			asciiValue := ci - 128
+			if c40 && isNativeC40(asciiValue) || !c40 && isNativeText(asciiValue) {
+				c40Values = append(c40Values, 1)  //Shift 2
+				c40Values = append(c40Values, 30) //Upper Shift
+				c40Values = append(c40Values, getC40Value(c40, 0, asciiValue, fnc1))
+			} else {
+				c40Values = append(c40Values, 1)  //Shift 2
+				c40Values = append(c40Values, 30) //Upper Shift
+				shiftValue := getShiftValue(asciiValue, c40, fnc1)
+				c40Values = append(c40Values, shiftValue) // Shift[123]
+				c40Values = append(c40Values, getC40Value(c40, shiftValue, asciiValue, fnc1))
+			}
+		}
+	}
+
+	if (len(c40Values) % 3) != 0 {
+		c40Values = append(c40Values, 0) // pad with 0 (Shift 1)
+	}
+
+	result := make([]byte, len(c40Values)/3*2)
+	byteIndex := 0
+	for i := 0; i < len(c40Values); i += 3 {
+		setC40Word(result, byteIndex, c40Values[i], c40Values[i+1], c40Values[i+2])
+		byteIndex += 2
+	}
+	return result
+}
+
+func (this *minimalEdge) getEDFBytes() []byte {
+	input := this.input
+	numberOfThirds := (this.characterLength + 3) / 4
+	result := make([]byte, numberOfThirds*3)
+	pos := this.fromPosition
+	endPos := this.fromPosition + this.characterLength - 1
+	if endPos > input.length()-1 {
+		endPos = input.length() - 1
+	}
+	for i := 0; i < numberOfThirds; i += 3 {
+		edfValues := make([]int, 4)
+		for j := 0; j < 4; j++ {
+			if pos <= endPos {
+				edfValues[j] = int(input.charAt(pos) & 0x3f)
+				pos++
+			} else if pos == endPos+1 {
+				edfValues[j] = 0x1f
+			} else {
+				edfValues[j] = 0
+			}
+		}
+		val24 := edfValues[0] << 18
+		val24 |= edfValues[1] << 12
+		val24 |= edfValues[2] << 6
+		val24 |= edfValues[3]
+		result[i] = byte((val24 >> 16) & 0xff)
+		result[i+1] = byte((val24 >> 8) & 0xff)
+		result[i+2] = byte(val24 & 0xff)
+	}
+	return result
+}
+
+func (this *minimalEdge) getLatchBytes() []byte {
+	switch this.getPreviousMode() {
+	case HighLevelEncoder_ASCII_ENCODATION, HighLevelEncoder_BASE256_ENCODATION:
+		//after B256 ends (via length) we are back to ASCII
+		switch this.mode {
+		case HighLevelEncoder_BASE256_ENCODATION:
+			return []byte{HighLevelEncoder_LATCH_TO_BASE256}
+		case HighLevelEncoder_C40_ENCODATION:
+			return []byte{HighLevelEncoder_LATCH_TO_C40}
+		case HighLevelEncoder_TEXT_ENCODATION:
+			return []byte{HighLevelEncoder_LATCH_TO_TEXT}
+		case HighLevelEncoder_X12_ENCODATION:
+			return []byte{HighLevelEncoder_LATCH_TO_ANSIX12}
+		case HighLevelEncoder_EDIFACT_ENCODATION:
+			return []byte{HighLevelEncoder_LATCH_TO_EDIFACT}
+		}
+	case HighLevelEncoder_C40_ENCODATION, HighLevelEncoder_TEXT_ENCODATION, HighLevelEncoder_X12_ENCODATION:
+		if this.mode != this.getPreviousMode() {
+			switch this.mode {
+			case HighLevelEncoder_ASCII_ENCODATION:
+				return []byte{HighLevelEncoder_C40_UNLATCH}
+			case HighLevelEncoder_BASE256_ENCODATION:
+				return []byte{HighLevelEncoder_C40_UNLATCH, HighLevelEncoder_LATCH_TO_BASE256}
+			case HighLevelEncoder_C40_ENCODATION:
+				return []byte{HighLevelEncoder_C40_UNLATCH, HighLevelEncoder_LATCH_TO_C40}
+			case HighLevelEncoder_TEXT_ENCODATION:
+				return []byte{HighLevelEncoder_C40_UNLATCH, HighLevelEncoder_LATCH_TO_TEXT}
+			case HighLevelEncoder_X12_ENCODATION:
+				return []byte{HighLevelEncoder_C40_UNLATCH, HighLevelEncoder_LATCH_TO_ANSIX12}
+			case HighLevelEncoder_EDIFACT_ENCODATION:
+				return []byte{HighLevelEncoder_C40_UNLATCH, HighLevelEncoder_LATCH_TO_EDIFACT}
+			}
+		}
+	case HighLevelEncoder_EDIFACT_ENCODATION:
+		//The rightmost EDIFACT edge always contains an unlatch character
+	}
+	return []byte{}
+}
+
+// getDataBytes Important: The function does not return the length bytes (one or two) in case of B256 encoding
+func (this *minimalEdge) getDataBytes() []byte {
+	input := this.input
+	switch this.mode {
+	case HighLevelEncoder_ASCII_ENCODATION:
+		ch := input.charAt(this.fromPosition)
+		if minimalIsExtendedASCII(ch, input.fnc1) {
+			return []byte{HighLevelEncoder_UPPER_SHIFT, ch - 127}
+		} else if this.characterLength == 2 {
+			return []byte{(ch-'0')*10 + input.charAt(this.fromPosition+1) - '0' + 130}
+		} else if input.isFNC1(this.fromPosition) {
+			return []byte{HighLevelEncoder_FUNC1}
+		} else {
+			return []byte{ch + 1}
+		}
+	case HighLevelEncoder_BASE256_ENCODATION:
+		return []byte{input.charAt(this.fromPosition)}
+	case HighLevelEncoder_C40_ENCODATION:
+		return this.getC40Words(true, input.fnc1)
+	case HighLevelEncoder_TEXT_ENCODATION:
+		return this.getC40Words(false, input.fnc1)
+	case HighLevelEncoder_X12_ENCODATION:
+		return this.getX12Words()
+	case HighLevelEncoder_EDIFACT_ENCODATION:
+		return this.getEDFBytes()
+	}
+	return []byte{}
+}
+
+// newMinimalResult builds the codewords from the solution, and pads them to the symbol capacity.
+func newMinimalResult(input *minimalInput, solution *minimalEdge) ([]byte, error) {
+	length := 0
+	bytes := make([]byte, 0)
+	randomizePostfixLength := make([]int, 0)
+	randomizeLengths := make([]int, 0)
+	if solution != nil && (solution.mode == HighLevelEncoder_C40_ENCODATION ||
+		solution.mode == HighLevelEncoder_TEXT_ENCODATION ||
+		solution.mode == HighLevelEncoder_X12_ENCODATION) &&
+		solution.getEndMode() != HighLevelEncoder_ASCII_ENCODATION {
+		bytes = prepend([]byte{HighLevelEncoder_C40_UNLATCH}, bytes)
+		length++
+	}
+	if solution != nil && solution.getEndMode() == HighLevelEncoder_EDIFACT_ENCODATION &&
+		solution.getCodewordsRemaining(solution.cachedTotalSize) > 2 {
+		// EDIFACT needs an unlatch unless the rest of the symbol is two or less codewords
+		bytes = prepend([]byte{0x1f << 2}, bytes)
+		length++
+	}
+	current := solution
+	for current != nil {
+		dataBytes := current.getDataBytes()
+		bytes = prepend(dataBytes, bytes)
+		length += len(dataBytes)
+
+		if current.previous == nil || current.getPreviousStartMode() != current.mode {
+			if current.mode == HighLevelEncoder_BASE256_ENCODATION {
+				if length <= 249 {
+					bytes = prepend([]byte{byte(length)}, bytes)
+					length++
+				} else {
+					bytes = prepend([]byte{byte(length/250 + 249), byte(length % 250)}, bytes)
+					length += 2
+				}
+				randomizePostfixLength = append(randomizePostfixLength, len(bytes))
+				randomizeLengths = append(randomizeLengths, length)
+			}
+			bytes = prepend(current.getLatchBytes(), bytes)
+			length = 0
+		}
+
+		current = current.previous
+	}
+
+	if input.eci >= 0 {
+		ctx, _ := NewEncoderContext("")
+		appendECI(ctx, input.eci)
+		bytes = prepend(ctx.GetCodewords(), bytes)
+	}
+	if input.macroId == 5 {
+		bytes = prepend([]byte{HighLevelEncoder_MACRO_05}, bytes)
+	} else if input.macroId == 6 {
+		bytes = prepend([]byte{HighLevelEncoder_MACRO_06}, bytes)
+	}
+	if input.fnc1 >= 0 {
+		bytes = prepend([]byte{HighLevelEncoder_FUNC1}, bytes)
+	}
+
+	for i := 0; i < len(randomizePostfixLength); i++ {
+		applyRandomPattern(bytes, len(bytes)-randomizePostfixLength[i], randomizeLengths[i])
+	}
+
+	//add padding
+	symbolInfo, e := SymbolInfo_Lookup(len(bytes), input.shape, input.minSize, input.maxSize, true)
+	if e != nil {
+		return nil, gozxing.WrapWriterException(e)
+	}
+	capacity := symbolInfo.GetDataCapacity()
+	if len(bytes) < capacity {
+		bytes = append(bytes, HighLevelEncoder_PAD)
+	}
+	for len(bytes) < capacity {
+		bytes = append(bytes, randomize253State(len(bytes)+1))
+	}
+	return bytes, nil
+}
+
+func prepend(bytes, into []byte) []byte {
+	return append(append(make([]byte, 0, len(bytes)+len(into)), bytes...), into...)
+}
+
+func applyRandomPattern(bytes []byte, startPosition, length int) {
+	for i := 0; i < length; i++ {
+		//See "B.1 253-state algorithm
+		padCodewordPosition := startPosition + i
+		bytes[padCodewordPosition] = base256Randomize255State(bytes[padCodewordPosition], padCodewordPosition+1)
+	}
+}
@@ -0,0 +1,218 @@
+package encoder
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
+)
+
+func TestMinimalIsExtendedASCII(t *testing.T) {
+	if minimalIsExtendedASCII(127, -1) {
+		t.Fatalf("minimalIsExtendedASCII(127) must false")
+	}
+	if !minimalIsExtendedASCII(128, -1) {
+		t.Fatalf("minimalIsExtendedASCII(128) must true")
+	}
+	if minimalIsExtendedASCII(128, 128) {
+		t.Fatalf("minimalIsExtendedASCII(128, fnc1=128) must false")
+	}
+}
+
+func TestIsInC40Shift2Set(t *testing.T) {
+	if !isInC40Shift2Set('!', -1) {
+		t.Fatalf("isInC40Shift2Set('!') must true")
+	}
+	if isInC40Shift2Set('A', -1) {
+		t.Fatalf("isInC40Shift2Set('A') must false")
+	}
+	if isInC40Shift2Set(0x1d, -1) {
+		t.Fatalf("isInC40Shift2Set(GS) must false")
+	}
+	if !isInTextShift2Set(0x1d, 0x1d) {
+		t.Fatalf("isInTextShift2Set(GS, fnc1=GS) must true")
+	}
+}
+
+func TestGetShiftValue(t *testing.T) {
+	tests := []struct {
+		c      byte
+		c40    bool
+		fnc1   int
+		expect byte
+	}{
+		{0x1d, true, -1, 0},
+		{0x1d, true, 0x1d, 1},
+		{'.', true, -1, 1},
+		{'a', true, -1, 2},
+		{'A', false, -1, 2},
+		{'~', false, -1, 2},
+	}
+	for _, test := range tests {
+		if r := getShiftValue(test.c, test.c40, test.fnc1); r != test.expect {
+			t.Fatalf("getShiftValue(%q, %v, %v) = %v, expect %v", test.c, test.c40, test.fnc1, r, test.expect)
+		}
+	}
+}
+
+func TestGetC40Value(t *testing.T) {
+	tests := []struct {
+		c40      bool
+		setIndex byte
+		c        byte
+		expect   byte
+	}{
+		{true, 0, ' ', 3},
+		{true, 0, '0', 4},
+		{true, 0, 'A', 14},
+		{true, 0, 0x1d, 29},
+		{true, 0, '.', 13},
+		{true, 0, 'a', 1},
+		{false, 0, 'a', 14},
+		{false, 0, 'A', 1},
+		{false, 0, '`', 0},
+		{false, 1, 0x1d, 29},
+		{false, 0, 0x01, 0},
+	}
+	for _, test := range tests {
+		if r := getC40Value(test.c40, test.setIndex, test.c, -1); r != test.expect {
+			t.Fatalf("getC40Value(%v, %v, %q) = %v, expect %v", test.c40, test.setIndex, test.c, r, test.expect)
+		}
+	}
+	if r := getC40Value(true, 1, 0x1d, 0x1d); r != 27 {
+		t.Fatalf("getC40Value(FNC1) = %v, expect 27", r)
+	}
+}
+
+func TestGetX12Value(t *testing.T) {
+	tests := []struct {
+		c      byte
+		expect byte
+	}{
+		{'\r', 0}, {'*', 1}, {'>', 2}, {' ', 3}, {'0', 4}, {'9', 13}, {'A', 14}, {'Z', 39},
+	}
+	for _, test := range tests {
+		if r := getX12Value(test.c); r != test.expect {
+			t.Fatalf("getX12Value(%q) = %v, expect %v", test.c, r, test.expect)
+		}
+	}
+}
+
+func TestGetNumberOfC40Words(t *testing.T) {
+	tests := []struct {
+		msg        string
+		c40        bool
+		words      int
+		charLength int
+	}{
+		{"ABCD", true, 1, 3},
+		{"AB", true, 1, 2},
+		{"A", true, 0, 0},
+		{"a", true, 1, 1},
+		{"abc", false, 1, 3},
+		{"\xe1\xe1", true, 3, 2},
+		{"\xc1\xc1\xc1", true, 1, 1},
+	}
+	for _, test := range tests {
+		input := &minimalInput{bytes: []byte(test.msg), fnc1: -1}
+		words, charLength := getNumberOfC40Words(input, 0, test.c40)
+		if words != test.words || charLength != test.charLength {
+			t.Fatalf("getNumberOfC40Words(%q, %v) = %v, %v, expect %v, %v",
+				test.msg, test.c40, words, charLength, test.words, test.charLength)
+		}
+	}
+}
+
+func TestMinimalInput_getPrefixLength(t *testing.T) {
+	input := &minimalInput{eci: -1, fnc1: -1}
+	if r := input.getPrefixLength(); r != 0 {
+		t.Fatalf("getPrefixLength = %v, expect 0", r)
+	}
+	input = &minimalInput{eci: 26, fnc1: 0x1d, macroId: 5}
+	if r := input.getPrefixLength(); r != 4 {
+		t.Fatalf("getPrefixLength = %v, expect 4", r)
+	}
+	input = &minimalInput{eci: 999999, fnc1: -1}
+	if r := input.getPrefixLength(); r != 4 {
+		t.Fatalf("getPrefixLength = %v, expect 4", r)
+	}
+}
+
+func TestMinimalEncoder_EncodeHighLevel_Fail(t *testing.T) {
+	shape := SymbolShapeHint_FORCE_NONE
+
+	_, e := MinimalEncoder_EncodeHighLevel("日本語", common.CharacterSetECI_ISO8859_7, -1, shape, nil, nil)
+	if e == nil {
+		t.Fatalf("MinimalEncoder_EncodeHighLevel must be error")
+	}
+
+	maxSize, _ := gozxing.NewDimension(10, 10)
+	_, e = MinimalEncoder_EncodeHighLevel("ABCDEFGHIJKLMN", nil, -1, shape, nil, maxSize)
+	if e == nil {
+		t.Fatalf("MinimalEncoder_EncodeHighLevel must be error")
+	}
+}
+
+func TestMinimalEncoder_EncodeHighLevel(t *testing.T) {
+	shape := SymbolShapeHint_FORCE_NONE
+
+	tests := []struct {
+		msg     string
+		charset *common.CharacterSetECI
+		fnc1    int
+		expect  []byte
+	}{
+		{"ABC123", nil, -1, []byte{230, 89, 233, 32, 56}},
+		{"AIMAIMAIM", nil, -1, []byte{66, 230, 141, 159, 141, 159, 141, 145}},
+		{"abcdefghijklm", nil, -1, []byte{98, 239, 96, 82, 115, 141, 134, 200, 154, 3}},
+		{"ABC>*\rABC>*\r", nil, -1, []byte{66, 67, 238, 100, 82, 2, 64, 100, 82, 14}},
+		{"....>>>>....", nil, -1, []byte{240, 186, 235, 174, 251, 239, 190, 186, 235, 174}},
+		{"àáâãäåæç", nil, -1, []byte{231, 52, 161, 56, 206, 101, 252, 146, 41, 191}},
+		// macro
+		{"[)>\x1e05\x1dABC\x1e\x04", nil, -1, []byte{236, 66, 67, 68, 129}},
+		// GS1
+		{"01034531200000111719112510ABCD1234\x1d2110", nil, 0x1d, []byte{
+			232, 131, 133, 175, 161, 150, 130, 130, 141, 147, 149, 141, 155, 140, 66, 67, 68, 69, 142, 164,
+			232, 151, 140, 129, 59, 209, 104, 254, 150, 45}},
+		// ECI
+		{"Ελληνικά", common.CharacterSetECI_ISO8859_7, -1, []byte{
+			241, 10, 231, 95, 177, 109, 3, 148, 48, 193, 88, 224}},
+		// UTF-8 fallback
+		{"日本", nil, -1, []byte{241, 27, 231, 93, 210, 25, 189, 147, 223, 132}},
+	}
+	for _, test := range tests {
+		b, e := MinimalEncoder_EncodeHighLevel(test.msg, test.charset, test.fnc1, shape, nil, nil)
+		if e != nil {
+			t.Fatalf("MinimalEncoder_EncodeHighLevel(%q) returns error: %v", test.msg, e)
+		}
+		if !reflect.DeepEqual(b, test.expect) {
+			t.Fatalf("MinimalEncoder_EncodeHighLevel(%q) = %v, expect %v", test.msg, b, test.expect)
+		}
+	}
+}
+
+func TestMinimalEncoder_EncodeHighLevel_NotLongerThanGreedy(t *testing.T) {
+	shape := SymbolShapeHint_FORCE_NONE
+	msgs := []string{
+		"ABC123",
+		"Hello, World!",
+		"123456789012345678901234567890",
+		"àáâãäåæçABCDEFGHIJ",
+		"ABC>*\r0123456789XYZ",
+		"aimaimaimaimaimaim",
+	}
+	for _, msg := range msgs {
+		minimal, e := MinimalEncoder_EncodeHighLevel(msg, nil, -1, shape, nil, nil)
+		if e != nil {
+			t.Fatalf("MinimalEncoder_EncodeHighLevel(%q) returns error: %v", msg, e)
+		}
+		greedy, e := EncodeHighLevel(msg, shape, nil, nil)
+		if e != nil {
+			t.Fatalf("EncodeHighLevel(%q) returns error: %v", msg, e)
+		}
+		if len(minimal) > len(greedy) {
+			t.Fatalf("MinimalEncoder_EncodeHighLevel(%q) length = %v, greedy = %v", msg, len(minimal), len(greedy))
+		}
+	}
+}
@@ -44,8 +44,33 @@ var symbols = []*SymbolInfo{
 	NewDataMatrixSymbolInfo144(),
 }
 
+// Symbol info table for DMRE (ISO/IEC 21471:2020 Table 7).
+// These are used only when the shape hint allows DMRE.
+
+var dmreSymbols = []*SymbolInfo{
+	NewSymbolInfoDMRE(18, 15, 22, 6, 2),   // 8x48
+	NewSymbolInfoDMRE(24, 18, 14, 6, 4),   // 8x64
+	NewSymbolInfoDMRE(32, 22, 18, 6, 4),   // 8x80
+	NewSymbolInfoDMRE(38, 28, 22, 6, 4),   // 8x96
+	NewSymbolInfoDMRE(43, 27, 14, 10, 4),  // 12x64
+	NewSymbolInfoDMRE(44, 28, 16, 18, 2),  // 20x36
+	NewSymbolInfoDMRE(49, 32, 18, 6, 6),   // 8x120
+	NewSymbolInfoDMRE(56, 34, 20, 18, 2),  // 20x44
+	NewSymbolInfoDMRE(62, 36, 14, 14, 4),  // 16x64
+	NewSymbolInfoDMRE(63, 36, 22, 6, 6),   // 8x144
+	NewSymbolInfoDMRE(64, 36, 20, 10, 4),  // 12x88
+	NewSymbolInfoDMRE(70, 38, 18, 24, 2),  // 26x40
+	NewSymbolInfoDMRE(72, 38, 22, 20, 2),  // 22x48
+	NewSymbolInfoDMRE(80, 41, 22, 22, 2),  // 24x48
+	NewSymbolInfoDMRE(84, 42, 14, 18, 4),  // 20x64
+	NewSymbolInfoDMRE(90, 42, 22, 24, 2),  // 26x48
+	NewSymbolInfoDMRE(108, 46, 14, 22, 4), // 24x64
+	NewSymbolInfoDMRE(118, 50, 14, 24, 4), // 26x64
+}
+
 type SymbolInfo struct {
 	rectangular    bool
+	dmre           bool
 	dataCapacity   int
 	errorCodewords int
 	matrixWidth    int
@@ -81,6 +106,18 @@ func NewSymbolInfoRS(rectangular bool, dataCapacity, errorCodewords,
 	}
 }
 
+// NewSymbolInfoDMRE Creates a DMRE (Data Matrix Rectangular Extension) symbol info.
+// DMRE symbols have a single row of dataRegions data regions and a single Reed-Solomon block.
+func NewSymbolInfoDMRE(dataCapacity, errorCodewords, matrixWidth, matrixHeight, dataRegions int) *SymbolInfo {
+	si := NewSymbolInfo(true, dataCapacity, errorCodewords, matrixWidth, matrixHeight, dataRegions)
+	si.dmre = true
+	return si
+}
+
+func (this *SymbolInfo) IsDMRE() bool {
+	return this.dmre
+}
+
 // public static SymbolInfo lookup(int dataCodewords)
 // public static SymbolInfo lookup(int dataCodewords, SymbolShapeHint shape)
 // public static SymbolInfo lookup(int dataCodewords, boolean allowRectangular, boolean fail)
@@ -89,11 +126,33 @@ func NewSymbolInfoRS(rectangular bool, dataCapacity, errorCodewords,
 func SymbolInfo_Lookup(dataCodewords int, shape SymbolShapeHint,
 	minSize, maxSize *gozxing.Dimension, fail bool) (*SymbolInfo, error) {
 
-	for _, symbol := range symbols {
-		if shape == SymbolShapeHint_FORCE_SQUARE && symbol.rectangular {
+	found := lookupSymbol(symbols, dataCodewords, shape, minSize, maxSize)
+	if shape.allowsDMRE() {
+		// prefer the classic symbol when the capacities are the same
+		dmre := lookupSymbol(dmreSymbols, dataCodewords, shape, minSize, maxSize)
+		if dmre != nil && (found == nil || dmre.dataCapacity < found.dataCapacity) {
+			found = dmre
+		}
+	}
+	if found != nil {
+		return found, nil
+	}
+	if fail {
+		return nil, gozxing.NewWriterException("IllegalArgumentException: "+
+			"Can't find a symbol arrangement that matches the message. Data codewords: %d",
+			dataCodewords)
+	}
+	return nil, nil
+}
+
+func lookupSymbol(table []*SymbolInfo, dataCodewords int, shape SymbolShapeHint,
+	minSize, maxSize *gozxing.Dimension) *SymbolInfo {
+
+	for _, symbol := range table {
+		if symbol.rectangular && !shape.allowsRectangle() {
 			continue
 		}
-		if shape == SymbolShapeHint_FORCE_RECTANGLE && !symbol.rectangular {
+		if !symbol.rectangular && !shape.allowsSquare() {
 			continue
 		}
 		if minSize != nil &&
@@ -107,18 +166,16 @@ func SymbolInfo_Lookup(dataCodewords int, shape SymbolShapeHint,
 			continue
 		}
 		if dataCodewords <= symbol.dataCapacity {
-			return symbol, nil
+			return symbol
 		}
 	}
-	if fail {
-		return nil, gozxing.NewWriterException("IllegalArgumentException: "+
-			"Can't find a symbol arrangement that matches the message. Data codewords: %d",
-			dataCodewords)
-	}
-	return nil, nil
+	return nil
 }
 
 func (this *SymbolInfo) getHorizontalDataRegions() int {
+	if this.dmre {
+		return this.dataRegions
+	}
 	switch this.dataRegions {
 	case 1:
 		return 1
@@ -134,6 +191,9 @@ func (this *SymbolInfo) getHorizontalDataRegions() int {
 }
 
 func (this *SymbolInfo) getVerticalDataRegions() int {
+	if this.dmre {
+		return 1
+	}
 	switch this.dataRegions {
 	case 1, 2:
 		return 1
@@ -188,6 +188,72 @@ func TestSymbolInfo_Lookup(t *testing.T) {
 	}
 }
 
+func TestSymbolInfo_LookupDMRE(t *testing.T) {
+	r, _ := SymbolInfo_Lookup(18, SymbolShapeHint_FORCE_RECTANGLE, nil, nil, false)
+	if r.IsDMRE() {
+		t.Fatalf("SymbolInfo_Lookup must not return DMRE, %v", r)
+	}
+
+	// classic symbol is preferred for the same capacity
+	r, _ = SymbolInfo_Lookup(18, SymbolShapeHint_FORCE_NONE_DMRE, nil, nil, false)
+	if r.IsDMRE() || r.GetSymbolWidth() != 18 {
+		t.Fatalf("SymbolInfo_Lookup must be 18x18, %v", r)
+	}
+
+	r, _ = SymbolInfo_Lookup(17, SymbolShapeHint_FORCE_RECTANGLE_DMRE, nil, nil, false)
+	if !r.IsDMRE() {
+		t.Fatalf("SymbolInfo_Lookup must return DMRE, %v", r)
+	}
+	if w, h := r.GetSymbolWidth(), r.GetSymbolHeight(); w != 48 || h != 8 {
+		t.Fatalf("SymbolInfo_Lookup symbol size = %vx%v, expect 48x8", w, h)
+	}
+
+	maxSize, _ := gozxing.NewDimension(200, 8)
+	r, _ = SymbolInfo_Lookup(50, SymbolShapeHint_FORCE_RECTANGLE_DMRE, nil, maxSize, false)
+	if w, h := r.GetSymbolWidth(), r.GetSymbolHeight(); w != 144 || h != 8 {
+		t.Fatalf("SymbolInfo_Lookup symbol size = %vx%v, expect 144x8", w, h)
+	}
+	if w, h := r.GetSymbolDataWidth(), r.GetSymbolDataHeight(); w != 132 || h != 6 {
+		t.Fatalf("SymbolInfo_Lookup symbol data size = %vx%v, expect 132x6", w, h)
+	}
+
+	r, _ = SymbolInfo_Lookup(64, SymbolShapeHint_FORCE_RECTANGLE_DMRE, nil, maxSize, false)
+	if r != nil {
+		t.Fatalf("SymbolInfo_Lookup must be nil, %v", r)
+	}
+
+	r, _ = SymbolInfo_Lookup(18, SymbolShapeHint_FORCE_SQUARE, nil, nil, false)
+	if r.IsDMRE() || r.rectangular {
+		t.Fatalf("SymbolInfo_Lookup must be square, %v", r)
+	}
+}
+
+func TestSymbolShapeHint(t *testing.T) {
+	tests := []struct {
+		shape     SymbolShapeHint
+		square    bool
+		rectangle bool
+		dmre      bool
+	}{
+		{SymbolShapeHint_FORCE_NONE, true, true, false},
+		{SymbolShapeHint_FORCE_SQUARE, true, false, false},
+		{SymbolShapeHint_FORCE_RECTANGLE, false, true, false},
+		{SymbolShapeHint_FORCE_NONE_DMRE, true, true, true},
+		{SymbolShapeHint_FORCE_RECTANGLE_DMRE, false, true, true},
+	}
+	for _, test := range tests {
+		if r := test.shape.allowsSquare(); r != test.square {
+			t.Fatalf("%v allowsSquare = %v, expect %v", test.shape, r, test.square)
+		}
+		if r := test.shape.allowsRectangle(); r != test.rectangle {
+			t.Fatalf("%v allowsRectangle = %v, expect %v", test.shape, r, test.rectangle)
+		}
+		if r := test.shape.allowsDMRE(); r != test.dmre {
+			t.Fatalf("%v allowsDMRE = %v, expect %v", test.shape, r, test.dmre)
+		}
+	}
+}
+
 func TestSymbolInfo_getHorizontalDataRegions(t *testing.T) {
 	s := NewSymbolInfo(false, 3, 5, 8, 8, 1)
 	if r := s.getHorizontalDataRegions(); r != 1 {
@@ -2,10 +2,27 @@ package encoder
 
 // SymbolShapeHint Enumeration for DataMatrix symbol shape hint.
 // It can be used to force square or rectangular symbols.
+//
+// The DMRE variants additionally allow the rectangular extension sizes of ISO/IEC 21471 (DMRE),
+// which are not used unless requested explicitly.
 type SymbolShapeHint int
 
 const (
 	SymbolShapeHint_FORCE_NONE = SymbolShapeHint(iota)
 	SymbolShapeHint_FORCE_SQUARE
 	SymbolShapeHint_FORCE_RECTANGLE
+	SymbolShapeHint_FORCE_NONE_DMRE
+	SymbolShapeHint_FORCE_RECTANGLE_DMRE
 )
+
+func (this SymbolShapeHint) allowsSquare() bool {
+	return this != SymbolShapeHint_FORCE_RECTANGLE && this != SymbolShapeHint_FORCE_RECTANGLE_DMRE
+}
+
+func (this SymbolShapeHint) allowsRectangle() bool {
+	return this != SymbolShapeHint_FORCE_SQUARE
+}
+
+func (this SymbolShapeHint) allowsDMRE() bool {
+	return this == SymbolShapeHint_FORCE_NONE_DMRE || this == SymbolShapeHint_FORCE_RECTANGLE_DMRE
+}
@@ -31,7 +31,7 @@ func (this X12Encoder) encode(context *EncoderContext) error {
 		if (count % 3) == 0 {
 			buffer = c40WriteNextTriplet(context, buffer)
 
-			newMode := HighLevelEncoder_lookAheadTest(context.GetMessage(), context.pos, this.getEncodingMode())
+			newMode := context.lookAheadTest(this.getEncodingMode())
 			if newMode != this.getEncodingMode() {
 				// Return to ASCII encodation, which will actually handle latch to new mode
 				context.SignalEncoderChange(HighLevelEncoder_ASCII_ENCODATION)
@@ -72,8 +72,8 @@ func x12HandleEOD(context *EncoderContext, buffer []byte) error {
 	available := context.GetSymbolInfo().GetDataCapacity() - context.GetCodewordCount()
 	count := len(buffer)
 	context.pos -= count
-	if context.GetRemainingCharacters() > 1 || available > 1 ||
-		context.GetRemainingCharacters() != available {
+	remaining := context.getRemainingASCIICodewordCount()
+	if remaining > 1 || available > 1 || remaining != available {
 		context.WriteCodeword(HighLevelEncoder_X12_UNLATCH)
 	}
 	if context.GetNewEncoding() < 0 {
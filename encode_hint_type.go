@@ -21,6 +21,7 @@ const (
 
 	/**
 	 * Specifies the matrix shape for Data Matrix (type {@link com.google.zxing.datamatrix.encoder.SymbolShapeHint})
+	 * DMRE (ISO/IEC 21471) rectangular sizes are used only with FORCE_NONE_DMRE or FORCE_RECTANGLE_DMRE.
 	 */
 	EncodeHintType_DATA_MATRIX_SHAPE
 
@@ -99,6 +100,44 @@ const (
 	 *  Valid values are "A", "B", "C".
 	 */
 	EncodeHintType_FORCE_CODE_SET
+
+	/**
+	 * Forces which encodation will be used for Data Matrix (Type {@link Integer} of
+	 * {@link com.google.zxing.datamatrix.encoder.HighLevelEncoder} encodation mode,
+	 * or {@link String} "ASCII", "C40", "TEXT", "X12", "EDIFACT" or "BASE256").
+	 * The encodation is kept as long as it can encode the following characters.
+	 */
+	EncodeHintType_DATA_MATRIX_FORCE_ENCODATION
+
+	/**
+	 * Specifies whether to use compact mode for Data Matrix (type {@link Boolean}, or "true" or "false"
+	 * {@link String } value).
+	 * The compact encoding mode searches all the encodation switches for the minimal number of codewords,
+	 * and chooses the smallest symbol. Characters that are not in the ISO-8859-1 character set are
+	 * encoded in UTF-8 with ECI unless {@link #CHARACTER_SET} is specified.
+	 * This option and {@link #DATA_MATRIX_FORCE_ENCODATION} are mutually exclusive.
+	 */
+	EncodeHintType_DATA_MATRIX_COMPACT
+
+	/**
+	 * Specifies whether to use compact mode for QR code (type {@link Boolean}, or "true" or "false"
+	 * {@link String } value).
+	 * The compact encoding mode splits the content into Numeric, Alphanumeric, Byte and Kanji segments
+	 * which give the minimal number of bits, and chooses the smallest version.
+	 * Non-ASCII characters are encoded in UTF-8 without ECI, or in the other character sets with ECI switching
+	 * when it needs fewer bits.
+	 * If {@link #CHARACTER_SET} is specified, the characters it can encode are encoded in it preferentially.
+	 */
+	EncodeHintType_QR_COMPACT
+
+	/**
+	 * Specifies the AIM application indicator for QR code, which is encoded with FNC1 in second position.
+	 * (Type {@link Integer} of 0-255 value, or {@link String} of two digits "00"-"99" or one letter).
+	 * This option and {@link #GS1_FORMAT} are mutually exclusive.
+	 * With this option, the GS character (0x1D) in the contents is encoded as the FNC1 separator
+	 * and '%' is encoded as a literal percent sign. (With {@link #GS1_FORMAT}, '%' is the separator.)
+	 */
+	EncodeHintType_QR_APPLICATION_INDICATOR
 )
 
 func (this EncodeHintType) String() string {
@@ -131,6 +170,14 @@ func (this EncodeHintType) String() string {
 		return "GS1_FORMAT"
 	case EncodeHintType_FORCE_CODE_SET:
 		return "FORCE_CODE_SET"
+	case EncodeHintType_DATA_MATRIX_FORCE_ENCODATION:
+		return "DATA_MATRIX_FORCE_ENCODATION"
+	case EncodeHintType_DATA_MATRIX_COMPACT:
+		return "DATA_MATRIX_COMPACT"
+	case EncodeHintType_QR_COMPACT:
+		return "QR_COMPACT"
+	case EncodeHintType_QR_APPLICATION_INDICATOR:
+		return "QR_APPLICATION_INDICATOR"
 	}
 	return ""
 }
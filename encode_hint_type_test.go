@@ -26,5 +26,9 @@ func TestEncodeHintType_String(t *testing.T) {
 	testEncodeHintType_String(t, EncodeHintType_QR_MASK_PATTERN, "QR_MASK_PATTERN")
 	testEncodeHintType_String(t, EncodeHintType_GS1_FORMAT, "GS1_FORMAT")
 	testEncodeHintType_String(t, EncodeHintType_FORCE_CODE_SET, "FORCE_CODE_SET")
+	testEncodeHintType_String(t, EncodeHintType_DATA_MATRIX_FORCE_ENCODATION, "DATA_MATRIX_FORCE_ENCODATION")
+	testEncodeHintType_String(t, EncodeHintType_DATA_MATRIX_COMPACT, "DATA_MATRIX_COMPACT")
+	testEncodeHintType_String(t, EncodeHintType_QR_COMPACT, "QR_COMPACT")
+	testEncodeHintType_String(t, EncodeHintType_QR_APPLICATION_INDICATOR, "QR_APPLICATION_INDICATOR")
 	testEncodeHintType_String(t, EncodeHintType(-1), "")
 }
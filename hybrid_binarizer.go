@@ -11,12 +11,25 @@ const (
 type HybridBinarizer struct {
 	*GlobalHistogramBinarizer
 	matrix *BitMatrix
+	buffer *BitMatrix
 }
 
 func NewHybridBinarizer(source LuminanceSource) Binarizer {
+	return NewHybridBinarizerWithBuffer(source, nil)
+}
+
+// NewHybridBinarizerWithBuffer creates a HybridBinarizer which writes the black matrix into the buffer
+// if it has the same size as the source, so that the buffer can be reused for the images of the same size.
+// The buffer must not be used by others until the black matrix is discarded.
+//
+// @param source the luminance source to binarize
+// @param buffer the matrix to write the black matrix into, or nil
+//
+func NewHybridBinarizerWithBuffer(source LuminanceSource, buffer *BitMatrix) Binarizer {
 	return &HybridBinarizer{
 		NewGlobalHistgramBinarizer(source).(*GlobalHistogramBinarizer),
 		nil,
+		buffer,
 	}
 }
 
@@ -39,7 +52,12 @@ func (this *HybridBinarizer) GetBlackMatrix() (*BitMatrix, error) {
 		}
 		blackPoints := this.calculateBlackPoints(luminances, subWidth, subHeight, width, height)
 
-		newMatrix, _ := NewBitMatrix(width, height)
+		newMatrix := this.buffer
+		if newMatrix != nil && newMatrix.GetWidth() == width && newMatrix.GetHeight() == height {
+			newMatrix.Clear()
+		} else {
+			newMatrix, _ = NewBitMatrix(width, height)
+		}
 		this.calculateThresholdForBlock(luminances, subWidth, subHeight, width, height, blackPoints, newMatrix)
 		this.matrix = newMatrix
 	} else {
@@ -103,3 +103,47 @@ func TestHybridBinarizer_calculateBlackPoints(t *testing.T) {
 		t.Fatalf("calculateBlackpoints:\n%v\nexpect:\n%v", points, expect)
 	}
 }
+
+func TestHybridBinarizer_WithBuffer(t *testing.T) {
+	buffer, _ := NewBitMatrix(63, 63)
+	buffer.SetRegion(0, 0, 63, 63)
+
+	b := NewHybridBinarizerWithBuffer(newTestLuminanceSource2(63), buffer)
+	m, e := b.GetBlackMatrix()
+	if e != nil {
+		t.Fatalf("GetBlackMatrix returns error, %v", e)
+	}
+	if m != buffer {
+		t.Fatalf("GetBlackMatrix must return the buffer")
+	}
+	for y := 0; y < m.GetHeight(); y++ {
+		for x := 0; x < m.GetWidth(); x++ {
+			expect := (x+y)%2 == 0
+			if r := m.Get(x, y); r != expect {
+				t.Fatalf("GetBlackMatrix [%v,%v] is %v, expect %v", x, y, r, expect)
+			}
+		}
+	}
+
+	// the binarizer for the other source does not share the buffer
+	m, e = b.CreateBinarizer(newTestLuminanceSource2(63)).GetBlackMatrix()
+	if e != nil {
+		t.Fatalf("GetBlackMatrix returns error, %v", e)
+	}
+	if m == buffer {
+		t.Fatalf("CreateBinarizer must not share the buffer")
+	}
+
+	// the buffer of the different size is not used
+	b = NewHybridBinarizerWithBuffer(newTestLuminanceSource2(39), buffer)
+	m, e = b.GetBlackMatrix()
+	if e != nil {
+		t.Fatalf("GetBlackMatrix returns error, %v", e)
+	}
+	if m == buffer {
+		t.Fatalf("GetBlackMatrix must not return the buffer of the different size")
+	}
+	if w, h := m.GetWidth(), m.GetHeight(); w != 39 || h != 39 {
+		t.Fatalf("GetBlackMatrix size = %vx%v, expect 39x39", w, h)
+	}
+}
@@ -0,0 +1,88 @@
+package aztec
+
+import (
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/aztec"
+	"github.com/makiuchi-d/gozxing/aztec/decoder"
+	"github.com/makiuchi-d/gozxing/multi"
+	"github.com/makiuchi-d/gozxing/multi/aztec/detector"
+)
+
+// AztecMultiReader This implementation can detect and decode multiple Aztec codes in an image.
+type AztecMultiReader struct {
+	*aztec.AztecReader
+}
+
+var _ multi.MultipleBarcodeReader = &AztecMultiReader{}
+
+func NewAztecMultiReader() multi.MultipleBarcodeReader {
+	return &AztecMultiReader{
+		aztec.NewAztecReader(),
+	}
+}
+
+func (this *AztecMultiReader) DecodeMultipleWithoutHint(image *gozxing.BinaryBitmap) ([]*gozxing.Result, error) {
+	return this.DecodeMultiple(image, nil)
+}
+
+// DecodeMultiple Locates and decodes the Aztec codes in an image.
+// The bull's eyes are found once, and the codes at them which cannot be decoded
+// are tried again as the mirror-images.
+//
+// @return the results of the decoded codes with their corner points
+// @throws NotFoundException if no Aztec code can be decoded
+//
+func (this *AztecMultiReader) DecodeMultiple(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error) {
+	matrix, e := image.GetBlackMatrix()
+	if e != nil {
+		return nil, gozxing.WrapReaderException(e)
+	}
+
+	multiDetector := detector.NewMultiDetector(matrix)
+	centers := multiDetector.FindBullsEyes()
+	results := make([]*gozxing.Result, 0)
+	for _, mirrored := range []bool{false, true} {
+		for _, center := range centers {
+			if isInsideResults(results, center) {
+				// a part of the decoded symbol
+				continue
+			}
+			detectorResult, e := multiDetector.DetectAt(center, mirrored)
+			if e != nil {
+				continue
+			}
+			decoderResult, e := decoder.NewDecoder().Decode(detectorResult)
+			if e != nil {
+				if _, ok := e.(gozxing.ReaderException); ok {
+					// ignore and continue
+					continue
+				}
+				return nil, e
+			}
+			results = append(results,
+				aztec.AztecReader_CreateResult(decoderResult, detectorResult, mirrored))
+		}
+	}
+	if len(results) == 0 {
+		return nil, gozxing.NewNotFoundException("no Aztec code can be decoded")
+	}
+
+	if rpcb, ok := hints[gozxing.DecodeHintType_NEED_RESULT_POINT_CALLBACK].(gozxing.ResultPointCallback); ok && rpcb != nil {
+		for _, result := range results {
+			for _, point := range result.GetResultPoints() {
+				rpcb(point)
+			}
+		}
+	}
+	return results, nil
+}
+
+// isInsideResults returns true if the point is inside of any of the results
+func isInsideResults(results []*gozxing.Result, point gozxing.ResultPoint) bool {
+	for _, result := range results {
+		if detector.MultiDetector_IsInside(result.GetResultPoints(), point) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package aztec
+
+import (
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/testutil"
+)
+
+func TestAztecMultiReader_DecodeMultiple(t *testing.T) {
+	reader := NewAztecMultiReader()
+
+	// testdata/1.png is composed of the images of zxing core/src/test/resources/blackbox/aztec-1/
+	// 7.png, Historico.png, abc-19x19C.png, dlusbs.png (mirrored) and tableShifts.png
+	bmp := testutil.NewBinaryBitmapFromFile("testdata/1.png")
+	wants := []struct {
+		text     string
+		mirrored bool
+	}{
+		{"Code 2D!", false},
+		{"Histórico", false},
+		{"abcdefghijklmnopqrstuvwxyz", false},
+		{"AhUUDgdy672;..:8KjHH776JHHn3g. 8lm/%22Nn873R2897ks4JKDJ9JJaza2323!::;09UJRrhDQSKJDKdSJSdskjdslkEdjseze:ze", false},
+		{"3333h3i3jITIT", true},
+	}
+
+	points := make([]gozxing.ResultPoint, 0)
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_NEED_RESULT_POINT_CALLBACK: gozxing.ResultPointCallback(
+			func(p gozxing.ResultPoint) { points = append(points, p) }),
+	}
+	results, e := reader.DecodeMultiple(bmp, hints)
+	if e != nil {
+		t.Fatalf("DecodeMultiple returns error: %v", e)
+	}
+	if len(results) != len(wants) {
+		t.Fatalf("len(results) = %v, wants %v", len(results), len(wants))
+	}
+	for i, result := range results {
+		if txt := result.GetText(); txt != wants[i].text {
+			t.Fatalf("results[%v] = %v, wants %v", i, txt, wants[i].text)
+		}
+		if f := result.GetBarcodeFormat(); f != gozxing.BarcodeFormat_AZTEC {
+			t.Fatalf("results[%v] format = %v", i, f)
+		}
+		metadata := result.GetResultMetadata()
+		if m := metadata[gozxing.ResultMetadataType_MIRRORED]; m != wants[i].mirrored {
+			t.Fatalf("results[%v] mirrored = %v, wants %v", i, m, wants[i].mirrored)
+		}
+		if m, ok := metadata[gozxing.ResultMetadataType_AZTEC_LAYERS]; !ok || m.(int) < 1 {
+			t.Fatalf("results[%v] layers = %v", i, m)
+		}
+		if l := len(result.GetResultPoints()); l != 4 {
+			t.Fatalf("results[%v] has %v points, wants 4", i, l)
+		}
+	}
+	if l := len(points); l != 4*len(wants) {
+		t.Fatalf("callback called %v times, wants %v", l, 4*len(wants))
+	}
+}
+
+func TestAztecMultiReader_DecodeMultipleFail(t *testing.T) {
+	reader := NewAztecMultiReader()
+
+	img, _ := gozxing.NewBitMatrix(100, 100)
+	_, e := reader.DecodeMultipleWithoutHint(testutil.NewBinaryBitmapFromBitMatrix(img))
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("DecodeMultiple must be NotFoundException, %T", e)
+	}
+
+	// bull's eye with broken mode message
+	bullsEye, _ := gozxing.ParseStringToBitMatrix(""+
+		"                                  \n"+
+		"      ######          ##  ##      \n"+
+		"      ######################      \n"+
+		"        ##              ##        \n"+
+		"        ##  ##########  ##        \n"+
+		"        ##  ##      ##  ##        \n"+
+		"        ##  ##  ##  ##  ##        \n"+
+		"        ##  ##      ##  ##        \n"+
+		"        ##  ##########  ##        \n"+
+		"        ##              ##        \n"+
+		"        ####################      \n"+
+		"                    ##            \n"+
+		"                                  \n",
+		"##", "  ")
+	img = testutil.ExpandBitMatrix(bullsEye, 3)
+	_, e = reader.DecodeMultipleWithoutHint(testutil.NewBinaryBitmapFromBitMatrix(img))
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("DecodeMultiple must be NotFoundException, %T", e)
+	}
+}
@@ -0,0 +1,267 @@
+package detector
+
+import (
+	"math"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/aztec/detector"
+)
+
+const (
+	multiDetector_BULLS_EYE_RUNS = 9 // the runs of the compact bull's eye across the center
+)
+
+// MultiDetector Encapsulates logic that can detect one or more Aztec codes in an image.
+//
+// Each row of the image is scanned for the concentric rings of the bull's eye,
+// which are the runs of the same width in the ratio 1:1:1:1:1:1:1:1:1.
+// The candidates are cross-checked vertically and detected at their center.
+type MultiDetector struct {
+	image *gozxing.BitMatrix
+}
+
+func NewMultiDetector(image *gozxing.BitMatrix) *MultiDetector {
+	return &MultiDetector{image}
+}
+
+// bullsEyeCandidate is a candidate of the center of the bull's eye
+type bullsEyeCandidate struct {
+	x, y       float64
+	moduleSize float64
+	count      int // number of the rows the candidate is found
+}
+
+// aboutEquals returns true if the center is inside of the center ring of this candidate
+func (c *bullsEyeCandidate) aboutEquals(moduleSize, x, y float64) bool {
+	d := math.Max(c.moduleSize, moduleSize) * 2
+	return math.Abs(x-c.x) <= d && math.Abs(y-c.y) <= d
+}
+
+// combine returns the candidate at the average of this and the new center
+func (c *bullsEyeCandidate) combine(moduleSize, x, y float64) *bullsEyeCandidate {
+	n := float64(c.count)
+	return &bullsEyeCandidate{
+		x:          (c.x*n + x) / (n + 1),
+		y:          (c.y*n + y) / (n + 1),
+		moduleSize: (c.moduleSize*n + moduleSize) / (n + 1),
+		count:      c.count + 1,
+	}
+}
+
+// DetectMulti Detects the Aztec codes in the image.
+//
+// @param isMirror if true, image is a mirror-image of original
+// @return the detector results of the found codes, from the top of the image
+// @throws NotFoundException if no Aztec code can be found
+//
+func (this *MultiDetector) DetectMulti(isMirror bool) ([]*detector.AztecDetectorResult, error) {
+	centers := this.FindBullsEyes()
+
+	results := make([]*detector.AztecDetectorResult, 0)
+	for _, center := range centers {
+		if isInsideResults(results, center) {
+			// a part of the found symbol
+			continue
+		}
+		result, e := this.DetectAt(center, isMirror)
+		if e != nil {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		return nil, gozxing.NewNotFoundException("no Aztec code in %v candidates", len(centers))
+	}
+	return results, nil
+}
+
+// FindBullsEyes Finds the centers of the bull's eyes in the image.
+//
+// @return the centers of the bull's eyes, from the top of the image
+//
+func (this *MultiDetector) FindBullsEyes() []gozxing.ResultPoint {
+	candidates := this.findBullsEyeCandidates()
+	centers := make([]gozxing.ResultPoint, 0, len(candidates))
+	for _, c := range candidates {
+		centers = append(centers, gozxing.NewResultPoint(c.x, c.y))
+	}
+	return centers
+}
+
+// DetectAt Detects the Aztec code at the center of the bull's eye.
+//
+// @param center the center of the bull's eye found by FindBullsEyes
+// @param isMirror if true, image is a mirror-image of original
+// @return the detector result of the code
+// @throws NotFoundException if no Aztec code can be found at the center
+//
+func (this *MultiDetector) DetectAt(center gozxing.ResultPoint, isMirror bool) (*detector.AztecDetectorResult, error) {
+	return detector.NewDetector(this.image).DetectAt(int(center.GetX()), int(center.GetY()), isMirror)
+}
+
+// findBullsEyeCandidates Finds the centers of the bull's eyes in the image, from the top.
+func (this *MultiDetector) findBullsEyeCandidates() []*bullsEyeCandidate {
+	image := this.image
+	width := image.GetWidth()
+	height := image.GetHeight()
+
+	candidates := make([]*bullsEyeCandidate, 0)
+	row := gozxing.NewBitArray(width)
+	runs := make([]int, 0)
+	for y := 0; y < height; y++ {
+		row = image.GetRow(y, row)
+
+		// positions where the color changes: black runs start at runs[2n] and end at runs[2n+1]
+		runs = runs[:0]
+		for x := row.GetNextSet(0); x < width; {
+			end := row.GetNextUnset(x)
+			runs = append(runs, x, end)
+			x = row.GetNextSet(end)
+		}
+
+		for i := 0; i+multiDetector_BULLS_EYE_RUNS <= len(runs); i += 2 {
+			// the outermost rings may be connected to the black modules outside of the bull's eye,
+			// so that only the inner 7 runs are checked.
+			moduleSize, ok := foundBullsEyeRuns(runs[i+1 : i+multiDetector_BULLS_EYE_RUNS])
+			if !ok {
+				continue
+			}
+			centerX := float64(runs[i+4]+runs[i+5]) / 2
+			if c, ok := this.handlePossibleCenter(centerX, float64(y)+0.5, moduleSize); ok {
+				candidates = addCandidate(candidates, c)
+			}
+		}
+	}
+	return candidates
+}
+
+// foundBullsEyeRuns returns the module size if the widths of the runs between the positions are about the same
+func foundBullsEyeRuns(positions []int) (float64, bool) {
+	n := len(positions) - 1
+	moduleSize := float64(positions[n]-positions[0]) / float64(n)
+	if moduleSize < 1 {
+		return 0, false
+	}
+	maxVariance := moduleSize/2 + 1
+	for i := 0; i < n; i++ {
+		if math.Abs(float64(positions[i+1]-positions[i])-moduleSize) > maxVariance {
+			return 0, false
+		}
+	}
+	return moduleSize, true
+}
+
+// handlePossibleCenter Cross-checks the candidate vertically, and then horizontally again.
+//
+// @return the center of the bull's eye and true if the candidate passed the checks
+//
+func (this *MultiDetector) handlePossibleCenter(centerX, centerY, moduleSize float64) (*bullsEyeCandidate, bool) {
+	x := int(centerX)
+	y := int(centerY)
+	centerY, ok := this.crossCheck(x, y, 0, 1, moduleSize)
+	if !ok {
+		return nil, false
+	}
+	centerX, ok = this.crossCheck(x, int(centerY), 1, 0, moduleSize)
+	if !ok {
+		return nil, false
+	}
+	return &bullsEyeCandidate{centerX, centerY, moduleSize, 1}, true
+}
+
+// crossCheck Counts the runs from the point to the both directions along (dx, dy),
+// and checks they are the rings of the bull's eye.
+//
+// @return the center of the center ring along the direction, and true if the check passed
+//
+func (this *MultiDetector) crossCheck(x, y, dx, dy int, moduleSize float64) (float64, bool) {
+	image := this.image
+	if !image.Get(x, y) {
+		return 0, false
+	}
+	maxVariance := moduleSize/2 + 1
+	runs := multiDetector_BULLS_EYE_RUNS / 2
+
+	// scan returns the end of the center ring and the widths of the inner rings in the direction
+	scan := func(sign int) (int, bool) {
+		px, py := x, y
+		color := true
+		end := 0
+		for i := 0; i <= runs; i++ {
+			length := 0
+			for this.isValid(px, py) && image.Get(px, py) == color {
+				px += dx * sign
+				py += dy * sign
+				length++
+			}
+			if i == 0 {
+				end = (px-x)*dx + (py-y)*dy
+			} else if i < runs && math.Abs(float64(length)-moduleSize) > maxVariance {
+				return 0, false
+			} else if length == 0 {
+				// the outermost ring is not found
+				return 0, false
+			}
+			color = !color
+		}
+		return end, true
+	}
+
+	end1, ok := scan(1)
+	if !ok {
+		return 0, false
+	}
+	end2, ok := scan(-1)
+	if !ok {
+		return 0, false
+	}
+	// the both ends of the center ring are exclusive
+	if math.Abs(float64(end1-end2-1)-moduleSize) > maxVariance {
+		return 0, false
+	}
+	start := x*dx + y*dy
+	return float64(start) + float64(end1+end2+1)/2, true
+}
+
+func (this *MultiDetector) isValid(x, y int) bool {
+	return x >= 0 && x < this.image.GetWidth() && y >= 0 && y < this.image.GetHeight()
+}
+
+// addCandidate combines the candidate with the existing one at the same center, or appends it
+func addCandidate(candidates []*bullsEyeCandidate, c *bullsEyeCandidate) []*bullsEyeCandidate {
+	for i, existing := range candidates {
+		if existing.aboutEquals(c.moduleSize, c.x, c.y) {
+			candidates[i] = existing.combine(c.moduleSize, c.x, c.y)
+			return candidates
+		}
+	}
+	return append(candidates, c)
+}
+
+// isInsideResults returns true if the point is inside of any of the results
+func isInsideResults(results []*detector.AztecDetectorResult, point gozxing.ResultPoint) bool {
+	for _, result := range results {
+		if MultiDetector_IsInside(result.GetPoints(), point) {
+			return true
+		}
+	}
+	return false
+}
+
+// MultiDetector_IsInside returns true if the point is inside of the bounding box of the points
+func MultiDetector_IsInside(points []gozxing.ResultPoint, point gozxing.ResultPoint) bool {
+	if len(points) == 0 {
+		return false
+	}
+	minX, minY := points[0].GetX(), points[0].GetY()
+	maxX, maxY := minX, minY
+	for _, p := range points[1:] {
+		minX = math.Min(minX, p.GetX())
+		minY = math.Min(minY, p.GetY())
+		maxX = math.Max(maxX, p.GetX())
+		maxY = math.Max(maxY, p.GetY())
+	}
+	x, y := point.GetX(), point.GetY()
+	return minX <= x && x <= maxX && minY <= y && y <= maxY
+}
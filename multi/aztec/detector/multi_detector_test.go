@@ -0,0 +1,255 @@
+package detector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/testutil"
+)
+
+var (
+	// compact Aztec code (15x15)
+	testCompact, _ = gozxing.ParseStringToBitMatrix(""+
+		"    ##    ##  ####        ##  \n"+
+		"  ######    ##  ######      ##\n"+
+		"    ####        ##  ##  ##    \n"+
+		"##########################    \n"+
+		"####  ##              ##      \n"+
+		"    ####  ##########  ##  ##  \n"+
+		"  ##  ##  ##      ##  ##      \n"+
+		"  ######  ##  ##  ##  ########\n"+
+		"  ######  ##      ##  ##      \n"+
+		"  ######  ##########  ####    \n"+
+		"    ####              ######  \n"+
+		"##    ####################  ##\n"+
+		"##        ##    ##  ##        \n"+
+		"####      ######  ##  ##    ##\n"+
+		"########    ####  ####  ##  ##\n",
+		"##", "  ")
+
+	// full size Aztec code (19x19)
+	testFull, _ = gozxing.ParseStringToBitMatrix(""+
+		"          ####  ##    ##  ##    ######\n"+
+		"      ####        ##    ##            \n"+
+		"##  ####                        ####  \n"+
+		"  ##################################  \n"+
+		"####  ##                      ##    ##\n"+
+		"    ####  ##################  ##    ##\n"+
+		"##  ####  ##              ##  ####    \n"+
+		"      ##  ##  ##########  ##  ##  ##  \n"+
+		"    ####  ##  ##      ##  ##  ##  ####\n"+
+		"  ##  ##  ##  ##  ##  ##  ##  ##  ##  \n"+
+		"  ##  ##  ##  ##      ##  ##  ####    \n"+
+		"##  ####  ##  ##########  ##  ######  \n"+
+		"##    ##  ##              ##  ##  ####\n"+
+		"  ##  ##  ##################  ####    \n"+
+		"##  ####                      ##    ##\n"+
+		"####  ############################    \n"+
+		"####    ##          ####  ####        \n"+
+		"        ####  ######    ####  ##      \n"+
+		"    ####  ####              ##########\n",
+		"##", "  ")
+)
+
+// putMatrix draws the expanded matrix into the image at (left, top)
+func putMatrix(img, matrix *gozxing.BitMatrix, scale, left, top int) {
+	matrix = testutil.ExpandBitMatrix(matrix, scale)
+	for y := 0; y < matrix.GetHeight(); y++ {
+		for x := 0; x < matrix.GetWidth(); x++ {
+			if matrix.Get(x, y) && left+x >= 0 && top+y >= 0 {
+				img.Set(left+x, top+y)
+			}
+		}
+	}
+}
+
+func TestBullsEyeCandidate(t *testing.T) {
+	c := &bullsEyeCandidate{10, 20, 2, 1}
+	if !c.aboutEquals(3, 15.5, 25.5) {
+		t.Fatalf("aboutEquals(3, 15.5, 25.5) must be true")
+	}
+	if c.aboutEquals(2, 14.5, 20) {
+		t.Fatalf("aboutEquals(2, 14.5, 20) must be false")
+	}
+
+	c = c.combine(4, 13, 23)
+	wants := &bullsEyeCandidate{11.5, 21.5, 3, 2}
+	if !reflect.DeepEqual(c, wants) {
+		t.Fatalf("combine = %v, wants %v", c, wants)
+	}
+}
+
+func TestFoundBullsEyeRuns(t *testing.T) {
+	if _, ok := foundBullsEyeRuns([]int{0, 1, 2, 3, 3, 4, 5, 6}); ok {
+		t.Fatalf("foundBullsEyeRuns must be false for the runs less than 1 pixel")
+	}
+	if _, ok := foundBullsEyeRuns([]int{0, 3, 6, 9, 16, 19, 22, 25}); ok {
+		t.Fatalf("foundBullsEyeRuns must be false for the long center run")
+	}
+	moduleSize, ok := foundBullsEyeRuns([]int{0, 3, 6, 10, 13, 15, 18, 21})
+	if !ok {
+		t.Fatalf("foundBullsEyeRuns must be true")
+	}
+	if moduleSize != 3 {
+		t.Fatalf("moduleSize = %v, wants 3", moduleSize)
+	}
+}
+
+func TestMultiDetector_crossCheck(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(100, 100)
+	putMatrix(img, testCompact, 3, 10, 10)
+	detector := NewMultiDetector(img)
+
+	// white
+	if _, ok := detector.crossCheck(10, 10, 0, 1, 3); ok {
+		t.Fatalf("crossCheck on white pixel must be false")
+	}
+	// ring of the bull's eye
+	if _, ok := detector.crossCheck(10+9*3+1, 10+7*3+1, 0, 1, 3); ok {
+		t.Fatalf("crossCheck on the ring must be false")
+	}
+	// module size mismatch
+	if _, ok := detector.crossCheck(10+7*3+1, 10+7*3+1, 0, 1, 10); ok {
+		t.Fatalf("crossCheck with wrong module size must be false")
+	}
+
+	center, ok := detector.crossCheck(10+7*3+1, 10+7*3, 0, 1, 3)
+	if !ok {
+		t.Fatalf("crossCheck vertically must be true")
+	}
+	if wants := float64(10 + 7*3 + 1.5); center != wants {
+		t.Fatalf("crossCheck vertically = %v, wants %v", center, wants)
+	}
+	center, ok = detector.crossCheck(10+7*3+2, 10+7*3+1, 1, 0, 3)
+	if !ok {
+		t.Fatalf("crossCheck horizontally must be true")
+	}
+	if wants := float64(10 + 7*3 + 1.5); center != wants {
+		t.Fatalf("crossCheck horizontally = %v, wants %v", center, wants)
+	}
+
+	// outermost ring is out of the image
+	img, _ = gozxing.NewBitMatrix(40, 40)
+	putMatrix(img, testCompact, 3, -12, -12)
+	if _, ok := NewMultiDetector(img).crossCheck(10, 10, 0, 1, 3); ok {
+		t.Fatalf("crossCheck must be false without the outermost ring")
+	}
+}
+
+func TestMultiDetector_findBullsEyeCandidates(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(200, 150)
+	putMatrix(img, testFull, 3, 100, 10)
+	putMatrix(img, testCompact, 4, 10, 70)
+	detector := NewMultiDetector(img)
+
+	candidates := detector.findBullsEyeCandidates()
+	if len(candidates) != 2 {
+		t.Fatalf("len(candidates) = %v, wants 2", len(candidates))
+	}
+	// from the top
+	if c := candidates[0]; int(c.x) != 100+9*3+1 || int(c.y) != 10+9*3+1 || c.moduleSize != 3 {
+		t.Fatalf("candidates[0] = %v", c)
+	}
+	if c := candidates[1]; int(c.x) != 10+7*4+2 || int(c.y) != 70+7*4+2 || c.moduleSize != 4 {
+		t.Fatalf("candidates[1] = %v", c)
+	}
+}
+
+func TestMultiDetector_DetectMulti(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(200, 150)
+	detector := NewMultiDetector(img)
+
+	_, e := detector.DetectMulti(false)
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("DetectMulti must be NotFoundException, %T", e)
+	}
+
+	putMatrix(img, testFull, 3, 100, 10)
+	putMatrix(img, testCompact, 4, 10, 70)
+	putMatrix(img, testutil.MirrorBitMatrix(testCompact), 2, 10, 10)
+
+	results, e := detector.DetectMulti(false)
+	if e != nil {
+		t.Fatalf("DetectMulti returns error: %v", e)
+	}
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %v, wants 2", len(results))
+	}
+	if r := results[0]; r.IsCompact() || !reflect.DeepEqual(r.GetBits(), testFull) {
+		t.Fatalf("results[0] = \n%v", r.GetBits())
+	}
+	if r := results[1]; !r.IsCompact() || !reflect.DeepEqual(r.GetBits(), testCompact) {
+		t.Fatalf("results[1] = \n%v", r.GetBits())
+	}
+
+	results, e = detector.DetectMulti(true)
+	if e != nil {
+		t.Fatalf("DetectMulti returns error: %v", e)
+	}
+	if len(results) != 1 {
+		t.Fatalf("len(results) = %v, wants 1", len(results))
+	}
+	if r := results[0]; !r.IsCompact() || !reflect.DeepEqual(r.GetBits(), testCompact) {
+		t.Fatalf("results[0] = \n%v", r.GetBits())
+	}
+}
+
+func TestMultiDetector_DetectAt(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(200, 150)
+	putMatrix(img, testCompact, 4, 10, 70)
+	putMatrix(img, testutil.MirrorBitMatrix(testCompact), 2, 10, 10)
+	detector := NewMultiDetector(img)
+
+	centers := detector.FindBullsEyes()
+	if len(centers) != 2 {
+		t.Fatalf("len(centers) = %v, wants 2", len(centers))
+	}
+
+	// the mirrored one at the top
+	if _, e := detector.DetectAt(centers[0], false); e == nil {
+		t.Fatalf("DetectAt(centers[0], false) must be error")
+	}
+	r, e := detector.DetectAt(centers[0], true)
+	if e != nil {
+		t.Fatalf("DetectAt(centers[0], true) returns error: %v", e)
+	}
+	if !r.IsCompact() || !reflect.DeepEqual(r.GetBits(), testCompact) {
+		t.Fatalf("DetectAt(centers[0], true) = \n%v", r.GetBits())
+	}
+
+	r, e = detector.DetectAt(centers[1], false)
+	if e != nil {
+		t.Fatalf("DetectAt(centers[1], false) returns error: %v", e)
+	}
+	if !r.IsCompact() || !reflect.DeepEqual(r.GetBits(), testCompact) {
+		t.Fatalf("DetectAt(centers[1], false) = \n%v", r.GetBits())
+	}
+}
+
+func TestMultiDetector_IsInside(t *testing.T) {
+	points := []gozxing.ResultPoint{
+		gozxing.NewResultPoint(10, 20),
+		gozxing.NewResultPoint(30, 10),
+		gozxing.NewResultPoint(40, 30),
+		gozxing.NewResultPoint(20, 40),
+	}
+	tests := []struct {
+		x, y   float64
+		inside bool
+	}{
+		{25, 25, true},
+		{10, 10, true},
+		{40, 40, true},
+		{9, 25, false},
+		{25, 41, false},
+	}
+	for _, test := range tests {
+		if r := MultiDetector_IsInside(points, gozxing.NewResultPoint(test.x, test.y)); r != test.inside {
+			t.Fatalf("MultiDetector_IsInside(%v, %v) = %v, wants %v", test.x, test.y, r, test.inside)
+		}
+	}
+	if MultiDetector_IsInside(nil, gozxing.NewResultPoint(0, 0)) {
+		t.Fatalf("MultiDetector_IsInside(nil) must be false")
+	}
+}
@@ -0,0 +1,84 @@
+package multi
+
+import (
+	"context"
+
+	"github.com/makiuchi-d/gozxing"
+)
+
+// ByQuadrantReader This class attempts to decode a barcode from an image, not by scanning the whole image,
+// but by scanning subsets of the image. This is important when there may be multiple barcodes in
+// an image, and detecting a barcode may find parts of multiple barcode and fail to decode
+// (e.g. QR Codes). Instead this scans the whole image, the four quadrants of the image,
+// and then the center of the image, and returns the first result.
+// The result points are translated into the coordinates of the whole image.
+type ByQuadrantReader struct {
+	delegate gozxing.Reader
+}
+
+var _ gozxing.ReaderWithContext = &ByQuadrantReader{}
+
+func NewByQuadrantReader(delegate gozxing.Reader) *ByQuadrantReader {
+	return &ByQuadrantReader{delegate}
+}
+
+func (this *ByQuadrantReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+	return this.Decode(image, nil)
+}
+
+// Decode Decodes the whole image, each quadrant of the image and the center of the image in order.
+//
+// @param image image of barcode to decode, which must support cropping
+// @param hints the decode hints passed to the delegate reader
+// @return the first result found
+// @throws NotFoundException if no barcode is found in all the regions
+//
+func (this *ByQuadrantReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return this.DecodeContext(context.Background(), image, hints)
+}
+
+// DecodeContext Decodes the regions of the image as Decode, and stops when the context is done.
+// The context is passed to the delegate reader if it is a ReaderWithContext.
+//
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *ByQuadrantReader) DecodeContext(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	result, e := gozxing.Reader_DecodeContext(ctx, this.delegate, image, hints)
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		return result, e
+	}
+
+	width := image.GetWidth()
+	height := image.GetHeight()
+	halfWidth := width / 2
+	halfHeight := height / 2
+	quarterWidth := halfWidth / 2
+	quarterHeight := halfHeight / 2
+
+	regions := [][2]int{
+		{0, 0},                        // top left
+		{halfWidth, 0},                // top right
+		{0, halfHeight},               // bottom left
+		{halfWidth, halfHeight},       // bottom right
+		{quarterWidth, quarterHeight}, // center
+	}
+	for _, region := range regions {
+		left, top := region[0], region[1]
+		cropped, e := image.Crop(left, top, halfWidth, halfHeight)
+		if e != nil {
+			return nil, gozxing.WrapReaderException(e)
+		}
+		result, e = gozxing.Reader_DecodeContext(ctx, this.delegate, cropped, hints)
+		if e == nil {
+			return TranslateResultPoints(result, left, top), nil
+		}
+		if _, ok := e.(gozxing.NotFoundException); !ok {
+			return nil, e
+		}
+	}
+	return nil, gozxing.NewNotFoundException()
+}
+
+func (this *ByQuadrantReader) Reset() {
+	this.delegate.Reset()
+}
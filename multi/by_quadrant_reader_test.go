@@ -0,0 +1,230 @@
+package multi
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	errors "golang.org/x/xerrors"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+type testQuadrantReader struct {
+	decode  func(image *gozxing.BinaryBitmap) (*gozxing.Result, error)
+	regions [][2]int
+	reset   bool
+}
+
+func (this *testQuadrantReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+	return this.Decode(image, nil)
+}
+
+func (this *testQuadrantReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	this.regions = append(this.regions, [2]int{image.GetWidth(), image.GetHeight()})
+	return this.decode(image)
+}
+
+func (this *testQuadrantReader) Reset() {
+	this.reset = true
+}
+
+// newTestQRCodeImage creates the image with the QR Code at (left, top)
+func newTestQRCodeImage(t testing.TB, width, height, left, top int) *gozxing.BinaryBitmap {
+	t.Helper()
+	qr, e := qrcode.NewQRCodeWriter().Encode("ByQuadrantReader", gozxing.BarcodeFormat_QR_CODE, 100, 100, nil)
+	if e != nil {
+		t.Fatalf("Encode returns error, %v", e)
+	}
+	img, _ := gozxing.NewBitMatrix(width, height)
+	for y := 0; y < qr.GetHeight(); y++ {
+		for x := 0; x < qr.GetWidth(); x++ {
+			if qr.Get(x, y) {
+				img.Set(left+x, top+y)
+			}
+		}
+	}
+	bmp, _ := gozxing.NewBinaryBitmapFromImage(img)
+	return bmp
+}
+
+func TestByQuadrantReader_Decode(t *testing.T) {
+	bmp := newTestQRCodeImage(t, 400, 300, 0, 0)
+
+	delegate := &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			return nil, gozxing.NewNotFoundException()
+		},
+	}
+	reader := NewByQuadrantReader(delegate)
+	_, e := reader.DecodeWithoutHints(bmp)
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("Decode must be NotFoundException, %T", e)
+	}
+	wants := [][2]int{{400, 300}, {200, 150}, {200, 150}, {200, 150}, {200, 150}, {200, 150}}
+	if len(delegate.regions) != len(wants) {
+		t.Fatalf("decoded regions = %v, wants %v", delegate.regions, wants)
+	}
+	for i := range wants {
+		if delegate.regions[i] != wants[i] {
+			t.Fatalf("decoded regions = %v, wants %v", delegate.regions, wants)
+		}
+	}
+
+	// the errors other than NotFoundException are returned immediately
+	delegate = &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			if image.GetWidth() == 400 {
+				return nil, gozxing.NewNotFoundException()
+			}
+			return nil, gozxing.NewFormatException()
+		},
+	}
+	reader = NewByQuadrantReader(delegate)
+	_, e = reader.DecodeWithoutHints(bmp)
+	if _, ok := e.(gozxing.FormatException); !ok {
+		t.Fatalf("Decode must be FormatException, %T", e)
+	}
+	if n := len(delegate.regions); n != 2 {
+		t.Fatalf("decoded regions = %v, wants 2 regions", delegate.regions)
+	}
+
+	delegate = &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			return nil, errors.New("error")
+		},
+	}
+	reader = NewByQuadrantReader(delegate)
+	if _, e = reader.DecodeWithoutHints(bmp); e == nil {
+		t.Fatalf("Decode must be error")
+	}
+	if n := len(delegate.regions); n != 1 {
+		t.Fatalf("decoded regions = %v, wants 1 region", delegate.regions)
+	}
+
+	reader.Reset()
+	if !delegate.reset {
+		t.Fatalf("Reset must reset the delegate reader")
+	}
+}
+
+func TestByQuadrantReader_DecodeQuadrants(t *testing.T) {
+	width, height := 600, 500
+	qrReader := qrcode.NewQRCodeReader()
+	delegate := &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			if image.GetWidth() == width {
+				// fails with the whole image
+				return nil, gozxing.NewNotFoundException()
+			}
+			return qrReader.DecodeWithoutHints(image)
+		},
+	}
+	reader := NewByQuadrantReader(delegate)
+
+	tests := []struct {
+		left, top int
+		regions   int
+	}{
+		{20, 30, 2},   // top left
+		{320, 40, 3},  // top right
+		{10, 270, 4},  // bottom left
+		{330, 260, 5}, // bottom right
+		{200, 180, 6}, // center
+	}
+	for _, test := range tests {
+		bmp := newTestQRCodeImage(t, width, height, test.left, test.top)
+		expect, e := qrReader.DecodeWithoutHints(bmp)
+		if e != nil {
+			t.Fatalf("Decode whole image(%v,%v) returns error, %v", test.left, test.top, e)
+		}
+
+		delegate.regions = nil
+		result, e := reader.DecodeWithoutHints(bmp)
+		if e != nil {
+			t.Fatalf("Decode(%v,%v) returns error, %v", test.left, test.top, e)
+		}
+		if txt := result.GetText(); txt != "ByQuadrantReader" {
+			t.Fatalf("Decode(%v,%v) text = \"%v\"", test.left, test.top, txt)
+		}
+		if n := len(delegate.regions); n != test.regions {
+			t.Fatalf("Decode(%v,%v) decoded regions = %v, wants %v regions", test.left, test.top, delegate.regions, test.regions)
+		}
+		points := result.GetResultPoints()
+		expectPoints := expect.GetResultPoints()
+		if len(points) != len(expectPoints) {
+			t.Fatalf("Decode(%v,%v) points = %v, expect %v", test.left, test.top, points, expectPoints)
+		}
+		for i := range points {
+			if math.Abs(points[i].GetX()-expectPoints[i].GetX()) > 1 ||
+				math.Abs(points[i].GetY()-expectPoints[i].GetY()) > 1 {
+				t.Fatalf("Decode(%v,%v) points = %v, expect %v", test.left, test.top, points, expectPoints)
+			}
+		}
+	}
+}
+
+func TestByQuadrantReader_DecodeCropFail(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(10, 10)
+	bmp, _ := gozxing.NewBinaryBitmap(gozxing.NewHybridBinarizer(&uncroppableSource{gozxing.NewLuminanceSourceFromImage(img)}))
+	delegate := &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			return nil, gozxing.NewNotFoundException()
+		},
+	}
+	_, e := NewByQuadrantReader(delegate).DecodeWithoutHints(bmp)
+	if _, ok := e.(gozxing.ReaderException); !ok {
+		t.Fatalf("Decode must be ReaderException, %T", e)
+	}
+}
+
+type uncroppableSource struct {
+	gozxing.LuminanceSource
+}
+
+func (*uncroppableSource) IsCropSupported() bool {
+	return false
+}
+
+func (*uncroppableSource) Crop(left, top, width, height int) (gozxing.LuminanceSource, error) {
+	return nil, errors.New("UnsupportedOperationException")
+}
+
+func TestByQuadrantReader_DecodeContext(t *testing.T) {
+	bmp := newTestQRCodeImage(t, 400, 400, 250, 250)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// canceled while decoding the whole image
+	delegate := &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			cancel()
+			return nil, gozxing.NewNotFoundException()
+		},
+	}
+	reader := NewByQuadrantReader(delegate)
+	_, e := reader.DecodeContext(ctx, bmp, nil)
+	if _, ok := e.(gozxing.NotFoundException); ok {
+		t.Fatalf("DecodeContext must not be NotFoundException, %v", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DecodeContext must wrap context.Canceled, %v", e)
+	}
+	if n := len(delegate.regions); n != 1 {
+		t.Fatalf("decoded regions = %v, wants 1", n)
+	}
+
+	// the context is passed to the delegate
+	reader = NewByQuadrantReader(qrcode.NewQRCodeReader())
+	_, e = reader.DecodeContext(ctx, bmp, nil)
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DecodeContext must wrap context.Canceled, %v", e)
+	}
+	result, e := reader.DecodeContext(context.Background(), bmp, nil)
+	if e != nil {
+		t.Fatalf("DecodeContext returns error, %v", e)
+	}
+	if txt := result.GetText(); txt != "ByQuadrantReader" {
+		t.Fatalf("DecodeContext text = %v, wants ByQuadrantReader", txt)
+	}
+}
@@ -0,0 +1,58 @@
+package datamatrix
+
+import (
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	"github.com/makiuchi-d/gozxing/multi"
+	"github.com/makiuchi-d/gozxing/multi/datamatrix/detector"
+)
+
+// DataMatrixMultiReader This implementation can detect and decode multiple Data Matrix codes in an image.
+type DataMatrixMultiReader struct {
+	*datamatrix.DataMatrixReader
+}
+
+var _ multi.MultipleBarcodeReader = &DataMatrixMultiReader{}
+
+func NewDataMatrixMultiReader() multi.MultipleBarcodeReader {
+	return &DataMatrixMultiReader{
+		datamatrix.NewDataMatrixReader(),
+	}
+}
+
+func (this *DataMatrixMultiReader) DecodeMultipleWithoutHint(image *gozxing.BinaryBitmap) ([]*gozxing.Result, error) {
+	return this.DecodeMultiple(image, nil)
+}
+
+// DecodeMultiple Locates and decodes the Data Matrix codes in an image.
+//
+// @return the results of the decoded codes with their corner points
+// @throws NotFoundException if no Data Matrix code can be decoded
+//
+func (this *DataMatrixMultiReader) DecodeMultiple(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error) {
+	matrix, e := image.GetBlackMatrix()
+	if e != nil {
+		return nil, gozxing.WrapReaderException(e)
+	}
+	detectorResults, e := detector.NewMultiDetector(matrix).DetectMulti()
+	if e != nil {
+		return nil, e
+	}
+	results := make([]*gozxing.Result, 0)
+	for _, detectorResult := range detectorResults {
+		decoderResult, e := this.GetDecoder().Decode(detectorResult.GetBits())
+		if e != nil {
+			if _, ok := e.(gozxing.ReaderException); ok {
+				// ignore and continue
+				continue
+			}
+			return nil, e
+		}
+		results = append(results,
+			datamatrix.DataMatrixReader_CreateResult(decoderResult, detectorResult.GetPoints()))
+	}
+	if len(results) == 0 {
+		return nil, gozxing.NewNotFoundException("no Data Matrix code can be decoded")
+	}
+	return results, nil
+}
@@ -0,0 +1,97 @@
+package datamatrix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	"github.com/makiuchi-d/gozxing/testutil"
+)
+
+// putDataMatrix draws the Data Matrix code into the image at (left, top)
+func putDataMatrix(t testing.TB, img *gozxing.BitMatrix, contents string, scale, rotation, left, top int) *gozxing.BitMatrix {
+	t.Helper()
+	matrix, e := datamatrix.NewDataMatrixWriter().EncodeWithoutHint(contents, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0)
+	if e != nil {
+		t.Fatalf("Encode(%v) returns error, %v", contents, e)
+	}
+	matrix = testutil.ExpandBitMatrix(matrix, scale)
+	for r := 0; r < rotation; r += 90 {
+		matrix.Rotate90()
+	}
+	for y := 0; y < matrix.GetHeight(); y++ {
+		for x := 0; x < matrix.GetWidth(); x++ {
+			if matrix.Get(x, y) {
+				img.Set(left+x, top+y)
+			}
+		}
+	}
+	return matrix
+}
+
+func TestDataMatrixMultiReader_DecodeMultiple(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(1000, 800)
+	expects := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		contents := fmt.Sprintf("component-%02d", i)
+		left := 20 + (i%5)*190
+		top := 20 + (i/5)*190
+		scale := 3 + i%3
+		rotation := (i % 4) * 90
+		putDataMatrix(t, img, contents, scale, rotation, left, top)
+		expects[contents] = true
+	}
+	bmp := testutil.NewBinaryBitmapFromBitMatrix(img)
+
+	reader := NewDataMatrixMultiReader()
+	results, e := reader.DecodeMultipleWithoutHint(bmp)
+	if e != nil {
+		t.Fatalf("DecodeMultiple returns error, %v", e)
+	}
+	if len(results) != len(expects) {
+		t.Fatalf("len(results) = %v, wants %v", len(results), len(expects))
+	}
+	found := make(map[string]bool)
+	for _, result := range results {
+		txt := result.GetText()
+		if !expects[txt] || found[txt] {
+			t.Fatalf("unexpected result %v", txt)
+		}
+		found[txt] = true
+		if f := result.GetBarcodeFormat(); f != gozxing.BarcodeFormat_DATA_MATRIX {
+			t.Fatalf("result %v format = %v", txt, f)
+		}
+		if id := result.GetResultMetadata()[gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER]; id != "]d1" {
+			t.Fatalf("result %v symbology identifier = %v", txt, id)
+		}
+		// the corner points are on the modules of the symbol
+		points := result.GetResultPoints()
+		if len(points) != 4 {
+			t.Fatalf("result %v points = %v", txt, points)
+		}
+		for _, p := range points[:3] {
+			if !img.Get(int(p.GetX()), int(p.GetY())) {
+				t.Fatalf("result %v point %v is not on the finder pattern", txt, p)
+			}
+		}
+	}
+}
+
+func TestDataMatrixMultiReader_DecodeMultipleFail(t *testing.T) {
+	reader := NewDataMatrixMultiReader()
+
+	img, _ := gozxing.NewBitMatrix(100, 100)
+	_, e := reader.DecodeMultipleWithoutHint(testutil.NewBinaryBitmapFromBitMatrix(img))
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("DecodeMultiple must be NotFoundException, %T", e)
+	}
+
+	// broken symbol
+	matrix := putDataMatrix(t, img, "broken", 3, 0, 20, 20)
+	img.SetRegion(35, 35, matrix.GetWidth()-20, matrix.GetHeight()-20)
+	_, e = reader.DecodeMultipleWithoutHint(testutil.NewBinaryBitmapFromBitMatrix(img))
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("DecodeMultiple must be NotFoundException, %T", e)
+	}
+}
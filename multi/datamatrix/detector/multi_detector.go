@@ -0,0 +1,253 @@
+package detector
+
+import (
+	"sort"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
+	"github.com/makiuchi-d/gozxing/datamatrix/detector"
+)
+
+const (
+	multiDetector_MIN_CANDIDATE_SIZE  = 10 // minimum width and height of a candidate in pixels
+	multiDetector_MAX_CANDIDATE_RATIO = 10 // maximum aspect ratio of a candidate (DMRE 8x64 has 1:8)
+)
+
+// MultiDetector Encapsulates logic that can detect one or more Data Matrix codes in an image.
+//
+// The L-shaped finder pattern of a Data Matrix code is a connected component of black pixels
+// whose bounding box covers the whole symbol. Each large enough connected component of the image
+// is taken as a candidate of the finder pattern, and the region around it is detected
+// by the Data Matrix detector.
+type MultiDetector struct {
+	image *gozxing.BitMatrix
+}
+
+func NewMultiDetector(image *gozxing.BitMatrix) *MultiDetector {
+	return &MultiDetector{image}
+}
+
+// finderCandidate is the bounding box of a connected component of black pixels
+type finderCandidate struct {
+	minX, minY, maxX, maxY int
+	count                  int // number of the black pixels
+}
+
+func (c *finderCandidate) width() int {
+	return c.maxX - c.minX + 1
+}
+
+func (c *finderCandidate) height() int {
+	return c.maxY - c.minY + 1
+}
+
+// isLShapeCandidate returns true if the component can be the L-shaped finder pattern
+func (c *finderCandidate) isLShapeCandidate() bool {
+	w, h := c.width(), c.height()
+	if w < multiDetector_MIN_CANDIDATE_SIZE || h < multiDetector_MIN_CANDIDATE_SIZE {
+		return false
+	}
+	if w > h*multiDetector_MAX_CANDIDATE_RATIO || h > w*multiDetector_MAX_CANDIDATE_RATIO {
+		return false
+	}
+	// the solid sides of the L-shape have at least (width + height) / 2 pixels even if rotated
+	return c.count >= (w+h)/2
+}
+
+// DetectMulti Detects the Data Matrix codes in the image.
+//
+// @return the detector results of the found codes, the larger first
+// @throws NotFoundException if no Data Matrix code can be found
+//
+func (this *MultiDetector) DetectMulti() ([]*common.DetectorResult, error) {
+	candidates := this.findCandidates()
+
+	results := make([]*common.DetectorResult, 0)
+	found := make([]*finderCandidate, 0)
+	for _, c := range candidates {
+		centerX := (c.minX + c.maxX) / 2
+		centerY := (c.minY + c.maxY) / 2
+		if isInside(found, centerX, centerY) {
+			// a part of the found symbol
+			continue
+		}
+		result, e := this.detect(c)
+		if e != nil {
+			continue
+		}
+		bounds := boundsOf(result.GetPoints())
+		if isInside(found, (bounds.minX+bounds.maxX)/2, (bounds.minY+bounds.maxY)/2) {
+			continue
+		}
+		found = append(found, bounds)
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		return nil, gozxing.NewNotFoundException("no Data Matrix code in %v candidates", len(candidates))
+	}
+	return results, nil
+}
+
+// detect Detects a Data Matrix code in the region around the candidate.
+func (this *MultiDetector) detect(c *finderCandidate) (*common.DetectorResult, error) {
+	// The bounding box of the L-shape does not contain the top right corner of a rotated symbol.
+	margin := max(c.width(), c.height())/4 + 2
+	left := max(0, c.minX-margin)
+	top := max(0, c.minY-margin)
+	right := min(this.image.GetWidth()-1, c.maxX+margin)
+	bottom := min(this.image.GetHeight()-1, c.maxY+margin)
+
+	region, e := gozxing.NewBitMatrix(right-left+1, bottom-top+1)
+	if e != nil {
+		return nil, gozxing.WrapNotFoundException(e)
+	}
+	for y := top; y <= bottom; y++ {
+		for x := left; x <= right; x++ {
+			if this.image.Get(x, y) {
+				region.Set(x-left, y-top)
+			}
+		}
+	}
+
+	d, e := detector.NewDetector(region)
+	if e != nil {
+		return nil, e
+	}
+	result, e := d.Detect()
+	if e != nil {
+		return nil, e
+	}
+
+	points := result.GetPoints()
+	translated := make([]gozxing.ResultPoint, len(points))
+	for i, p := range points {
+		translated[i] = gozxing.NewResultPoint(p.GetX()+float64(left), p.GetY()+float64(top))
+	}
+	return common.NewDetectorResult(result.GetBits(), translated), nil
+}
+
+// findCandidates Finds the connected components (8-connectivity) of black pixels
+// which can be the L-shaped finder pattern, the larger first.
+func (this *MultiDetector) findCandidates() []*finderCandidate {
+	type run struct {
+		y, left, right int // [left, right)
+	}
+
+	image := this.image
+	width := image.GetWidth()
+	height := image.GetHeight()
+
+	runs := make([]run, 0)
+	parents := make([]int, 0)
+	find := func(i int) int {
+		for parents[i] != i {
+			parents[i] = parents[parents[i]]
+			i = parents[i]
+		}
+		return i
+	}
+	union := func(i, j int) {
+		ri, rj := find(i), find(j)
+		if ri < rj {
+			parents[rj] = ri
+		} else if rj < ri {
+			parents[ri] = rj
+		}
+	}
+
+	row := gozxing.NewBitArray(width)
+	prevStart, prevEnd := 0, 0 // runs of the previous row
+	for y := 0; y < height; y++ {
+		row = image.GetRow(y, row)
+		start := len(runs)
+		p := prevStart
+		for x := row.GetNextSet(0); x < width; {
+			end := row.GetNextUnset(x)
+			i := len(runs)
+			runs = append(runs, run{y, x, end})
+			parents = append(parents, i)
+			// connect with the runs in the previous row which touch this run (including diagonally)
+			for p < prevEnd && runs[p].right < x {
+				p++
+			}
+			for q := p; q < prevEnd && runs[q].left <= end; q++ {
+				union(i, q)
+			}
+			x = row.GetNextSet(end)
+		}
+		prevStart, prevEnd = start, len(runs)
+	}
+
+	components := make(map[int]*finderCandidate)
+	for i, r := range runs {
+		root := find(i)
+		c, ok := components[root]
+		if !ok {
+			c = &finderCandidate{r.left, r.y, r.right - 1, r.y, 0}
+			components[root] = c
+		}
+		c.minX = min(c.minX, r.left)
+		c.maxX = max(c.maxX, r.right-1)
+		c.minY = min(c.minY, r.y)
+		c.maxY = max(c.maxY, r.y)
+		c.count += r.right - r.left
+	}
+
+	candidates := make([]*finderCandidate, 0)
+	for _, c := range components {
+		if c.isLShapeCandidate() {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if ai, aj := ci.width()*ci.height(), cj.width()*cj.height(); ai != aj {
+			return ai > aj
+		}
+		if ci.minY != cj.minY {
+			return ci.minY < cj.minY
+		}
+		return ci.minX < cj.minX
+	})
+	return candidates
+}
+
+// boundsOf returns the bounding box of the points
+func boundsOf(points []gozxing.ResultPoint) *finderCandidate {
+	b := &finderCandidate{
+		minX: int(points[0].GetX()), minY: int(points[0].GetY()),
+		maxX: int(points[0].GetX()), maxY: int(points[0].GetY()),
+	}
+	for _, p := range points[1:] {
+		b.minX = min(b.minX, int(p.GetX()))
+		b.minY = min(b.minY, int(p.GetY()))
+		b.maxX = max(b.maxX, int(p.GetX()))
+		b.maxY = max(b.maxY, int(p.GetY()))
+	}
+	return b
+}
+
+// isInside returns true if the point is inside of any of the bounding boxes
+func isInside(bounds []*finderCandidate, x, y int) bool {
+	for _, b := range bounds {
+		if b.minX <= x && x <= b.maxX && b.minY <= y && y <= b.maxY {
+			return true
+		}
+	}
+	return false
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
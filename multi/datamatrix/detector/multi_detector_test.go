@@ -0,0 +1,96 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	"github.com/makiuchi-d/gozxing/testutil"
+)
+
+func TestFinderCandidate_isLShapeCandidate(t *testing.T) {
+	tests := []struct {
+		c     finderCandidate
+		wants bool
+	}{
+		{finderCandidate{0, 0, 9, 9, 19}, true},
+		{finderCandidate{0, 0, 8, 9, 19}, false},  // too narrow
+		{finderCandidate{0, 0, 9, 8, 19}, false},  // too short
+		{finderCandidate{0, 0, 9, 109, 50}, false}, // too long
+		{finderCandidate{0, 0, 9, 99, 60}, true},
+		{finderCandidate{0, 0, 19, 19, 19}, false}, // too few pixels
+		{finderCandidate{0, 0, 19, 19, 20}, true},
+	}
+	for _, test := range tests {
+		if r := test.c.isLShapeCandidate(); r != test.wants {
+			t.Fatalf("%v isLShapeCandidate = %v, wants %v", test.c, r, test.wants)
+		}
+	}
+}
+
+func TestMultiDetector_findCandidates(t *testing.T) {
+	img, _ := gozxing.ParseStringToBitMatrix(""+
+		"                          \n"+
+		" X    X      XXXXXXXXXXXX \n"+
+		" X     X                X \n"+
+		" X      X               X \n"+
+		" X       X              X \n"+
+		" X        X             X \n"+
+		" X         X            X \n"+
+		" X          X           X \n"+
+		" X           X          X \n"+
+		" X            X         X \n"+
+		" XXXXXXXXXX             X \n"+
+		"                          \n"+
+		" XXXXXXXXXXX  XX    XXX   \n"+
+		"                          \n", "X", " ")
+	detector := NewMultiDetector(img)
+	candidates := detector.findCandidates()
+	// the L-shape, and the bracket; the diagonal line and the short lines are not candidates
+	if n := len(candidates); n != 2 {
+		t.Fatalf("len(candidates) = %v, wants 2: %v", n, candidates)
+	}
+	wants := []finderCandidate{
+		{13, 1, 24, 10, 21},
+		{1, 1, 10, 10, 19},
+	}
+	for i, c := range candidates {
+		if *c != wants[i] {
+			t.Fatalf("candidates[%v] = %v, wants %v", i, *c, wants[i])
+		}
+	}
+}
+
+func TestMultiDetector_DetectMulti(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(100, 100)
+	if _, e := NewMultiDetector(img).DetectMulti(); e == nil {
+		t.Fatalf("DetectMulti must be error")
+	}
+
+	img, _ = gozxing.NewBitMatrix(400, 200)
+	for i, contents := range []string{"first", "second symbol"} {
+		matrix, _ := datamatrix.NewDataMatrixWriter().EncodeWithoutHint(contents, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0)
+		matrix = testutil.ExpandBitMatrix(matrix, 4)
+		for y := 0; y < matrix.GetHeight(); y++ {
+			for x := 0; x < matrix.GetWidth(); x++ {
+				if matrix.Get(x, y) {
+					img.Set(20+i*200+x, 30+y)
+				}
+			}
+		}
+	}
+	results, e := NewMultiDetector(img).DetectMulti()
+	if e != nil {
+		t.Fatalf("DetectMulti returns error, %v", e)
+	}
+	if n := len(results); n != 2 {
+		t.Fatalf("len(results) = %v, wants 2", n)
+	}
+	// the larger first
+	if w0, w1 := results[0].GetBits().GetWidth(), results[1].GetBits().GetWidth(); w0 <= w1 {
+		t.Fatalf("dimensions = %v, %v, the larger must be first", w0, w1)
+	}
+	if x := results[0].GetPoints()[0].GetX(); x < 220 {
+		t.Fatalf("results[0] point = %v, wants the second symbol", results[0].GetPoints()[0])
+	}
+}
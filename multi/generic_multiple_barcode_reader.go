@@ -0,0 +1,188 @@
+package multi
+
+import (
+	"context"
+
+	"github.com/makiuchi-d/gozxing"
+)
+
+const (
+	genericMultipleBarcodeReader_MIN_DIMENSION_TO_RECUR = 100
+	genericMultipleBarcodeReader_MAX_DEPTH              = 4
+)
+
+// GenericMultipleBarcodeReader Attempts to locate multiple barcodes in an image by repeatedly decoding portion of the image.
+// After one barcode is found, the areas left, above, right and below the barcode's
+// ResultPoints are scanned, recursively.
+//
+// A caller may want to also employ ByQuadrantReader when attempting to find multiple
+// 2D barcodes, like QR Codes, in an image, where the presence of multiple barcodes might prevent
+// detecting any one of them.
+//
+// That is, instead of passing a Reader a caller might pass
+// NewByQuadrantReader(reader).
+type GenericMultipleBarcodeReader struct {
+	delegate gozxing.Reader
+}
+
+var _ MultipleBarcodeReaderWithContext = &GenericMultipleBarcodeReader{}
+
+func NewGenericMultipleBarcodeReader(delegate gozxing.Reader) *GenericMultipleBarcodeReader {
+	return &GenericMultipleBarcodeReader{delegate}
+}
+
+func (this *GenericMultipleBarcodeReader) DecodeMultipleWithoutHint(image *gozxing.BinaryBitmap) ([]*gozxing.Result, error) {
+	return this.DecodeMultiple(image, nil)
+}
+
+// DecodeMultiple Decodes the barcodes in the image, and the regions around the found barcodes recursively.
+// The results which have the same text and format are returned only once.
+//
+// @param image image of barcodes to decode, which must support cropping
+// @param hints the decode hints passed to the delegate reader
+// @return the results in the order they were found
+// @throws NotFoundException if no barcode is found
+//
+func (this *GenericMultipleBarcodeReader) DecodeMultiple(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error) {
+	return this.DecodeMultipleContext(context.Background(), image, hints)
+}
+
+// DecodeMultipleContext Decodes the barcodes in the image as DecodeMultiple,
+// and stops the recursion when the context is done.
+// The context is passed to the delegate reader if it is a ReaderWithContext.
+//
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *GenericMultipleBarcodeReader) DecodeMultipleContext(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error) {
+	results := make([]*gozxing.Result, 0)
+	results, e := this.doDecodeMultiple(ctx, image, hints, results, 0, 0, 0)
+	if e != nil {
+		return nil, e
+	}
+	if len(results) == 0 {
+		return nil, gozxing.NewNotFoundException()
+	}
+	return results, nil
+}
+
+func (this *GenericMultipleBarcodeReader) doDecodeMultiple(ctx context.Context, image *gozxing.BinaryBitmap,
+	hints map[gozxing.DecodeHintType]interface{}, results []*gozxing.Result,
+	xOffset, yOffset, currentDepth int) ([]*gozxing.Result, error) {
+
+	if currentDepth > genericMultipleBarcodeReader_MAX_DEPTH {
+		return results, nil
+	}
+
+	result, e := gozxing.Reader_DecodeContext(ctx, this.delegate, image, hints)
+	if e != nil {
+		if err := gozxing.Reader_CheckContext(ctx); err != nil {
+			return nil, err
+		}
+		if _, ok := e.(gozxing.ReaderException); ok {
+			return results, nil
+		}
+		return nil, e
+	}
+
+	alreadyFound := false
+	for _, existingResult := range results {
+		if existingResult.GetText() == result.GetText() &&
+			existingResult.GetBarcodeFormat() == result.GetBarcodeFormat() {
+			alreadyFound = true
+			break
+		}
+	}
+	if !alreadyFound {
+		results = append(results, TranslateResultPoints(result, xOffset, yOffset))
+	}
+
+	resultPoints := result.GetResultPoints()
+	if len(resultPoints) == 0 {
+		return results, nil
+	}
+	width := image.GetWidth()
+	height := image.GetHeight()
+	minX := float64(width)
+	minY := float64(height)
+	maxX := 0.0
+	maxY := 0.0
+	for _, point := range resultPoints {
+		if point == nil {
+			continue
+		}
+		x := point.GetX()
+		y := point.GetY()
+		if x < minX {
+			minX = x
+		}
+		if y < minY {
+			minY = y
+		}
+		if x > maxX {
+			maxX = x
+		}
+		if y > maxY {
+			maxY = y
+		}
+	}
+
+	type region struct {
+		left, top, width, height int
+	}
+	var regions []region
+	// Decode left of barcode
+	if minX > genericMultipleBarcodeReader_MIN_DIMENSION_TO_RECUR {
+		regions = append(regions, region{0, 0, int(minX), height})
+	}
+	// Decode above barcode
+	if minY > genericMultipleBarcodeReader_MIN_DIMENSION_TO_RECUR {
+		regions = append(regions, region{0, 0, width, int(minY)})
+	}
+	// Decode right of barcode
+	if maxX < float64(width-genericMultipleBarcodeReader_MIN_DIMENSION_TO_RECUR) {
+		regions = append(regions, region{int(maxX), 0, width - int(maxX), height})
+	}
+	// Decode below barcode
+	if maxY < float64(height-genericMultipleBarcodeReader_MIN_DIMENSION_TO_RECUR) {
+		regions = append(regions, region{0, int(maxY), width, height - int(maxY)})
+	}
+
+	for _, r := range regions {
+		cropped, e := image.Crop(r.left, r.top, r.width, r.height)
+		if e != nil {
+			return nil, gozxing.WrapReaderException(e)
+		}
+		results, e = this.doDecodeMultiple(
+			ctx, cropped, hints, results, xOffset+r.left, yOffset+r.top, currentDepth+1)
+		if e != nil {
+			return nil, e
+		}
+	}
+	return results, nil
+}
+
+// TranslateResultPoints Translates the points of the result decoded from a cropped image
+// into the coordinates of the original image.
+//
+// @param result the result decoded from the cropped image
+// @param xOffset the left of the cropped region
+// @param yOffset the top of the cropped region
+// @return the new result with the translated points, or the result itself if it has no points
+//
+func TranslateResultPoints(result *gozxing.Result, xOffset, yOffset int) *gozxing.Result {
+	oldResultPoints := result.GetResultPoints()
+	if oldResultPoints == nil {
+		return result
+	}
+	newResultPoints := make([]gozxing.ResultPoint, len(oldResultPoints))
+	for i, oldPoint := range oldResultPoints {
+		if oldPoint != nil {
+			newResultPoints[i] = gozxing.NewResultPoint(
+				oldPoint.GetX()+float64(xOffset), oldPoint.GetY()+float64(yOffset))
+		}
+	}
+	newResult := gozxing.NewResultWithNumBits(result.GetText(), result.GetRawBytes(), result.GetNumBits(),
+		newResultPoints, result.GetBarcodeFormat(), result.GetTimestamp())
+	newResult.PutAllMetadata(result.GetResultMetadata())
+	return newResult
+}
@@ -0,0 +1,213 @@
+package multi
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	errors "golang.org/x/xerrors"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// putBarcode draws the barcode into the image at (left, top)
+func putBarcode(t testing.TB, img *gozxing.BitMatrix, writer gozxing.Writer, contents string,
+	format gozxing.BarcodeFormat, width, height, left, top int) {
+	t.Helper()
+	matrix, e := writer.EncodeWithoutHint(contents, format, width, height)
+	if e != nil {
+		t.Fatalf("Encode(%v) returns error, %v", contents, e)
+	}
+	for y := 0; y < matrix.GetHeight(); y++ {
+		for x := 0; x < matrix.GetWidth(); x++ {
+			if matrix.Get(x, y) {
+				img.Set(left+x, top+y)
+			}
+		}
+	}
+}
+
+func TestGenericMultipleBarcodeReader_DecodeMultiple(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(700, 500)
+	writer := oned.NewCode128Writer()
+	putBarcode(t, img, writer, "CENTER", gozxing.BarcodeFormat_CODE_128, 200, 60, 250, 220)
+	putBarcode(t, img, writer, "TOP", gozxing.BarcodeFormat_CODE_128, 200, 60, 250, 20)
+	putBarcode(t, img, writer, "LEFT", gozxing.BarcodeFormat_CODE_128, 200, 60, 10, 300)
+	putBarcode(t, img, writer, "RIGHT", gozxing.BarcodeFormat_CODE_128, 200, 60, 480, 130)
+	putBarcode(t, img, writer, "BOTTOM", gozxing.BarcodeFormat_CODE_128, 200, 60, 250, 420)
+	putBarcode(t, img, writer, "TOP", gozxing.BarcodeFormat_CODE_128, 200, 60, 480, 420)
+	bmp, _ := gozxing.NewBinaryBitmapFromImage(img)
+
+	reader := NewGenericMultipleBarcodeReader(oned.NewCode128Reader())
+	results, e := reader.DecodeMultipleWithoutHint(bmp)
+	if e != nil {
+		t.Fatalf("DecodeMultiple returns error, %v", e)
+	}
+
+	texts := make([]string, 0, len(results))
+	for _, result := range results {
+		texts = append(texts, result.GetText())
+	}
+	sort.Strings(texts)
+	wants := []string{"BOTTOM", "CENTER", "LEFT", "RIGHT", "TOP"}
+	if len(texts) != len(wants) {
+		t.Fatalf("results = %v, wants %v", texts, wants)
+	}
+	for i := range wants {
+		if texts[i] != wants[i] {
+			t.Fatalf("results = %v, wants %v", texts, wants)
+		}
+	}
+
+	// the result points are in the coordinates of the whole image
+	for _, result := range results {
+		for _, p := range result.GetResultPoints() {
+			x, y := int(p.GetX()), int(p.GetY())
+			if !img.Get(x, y) && !img.Get(x-1, y) && !img.Get(x+1, y) {
+				t.Fatalf("result %v point %v is not on the barcode", result.GetText(), p)
+			}
+		}
+	}
+}
+
+func TestGenericMultipleBarcodeReader_DecodeMultipleQRCode(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(600, 600)
+	writer := qrcode.NewQRCodeWriter()
+	putBarcode(t, img, writer, "QR1", gozxing.BarcodeFormat_QR_CODE, 150, 150, 20, 20)
+	putBarcode(t, img, writer, "QR2", gozxing.BarcodeFormat_QR_CODE, 150, 150, 420, 420)
+	bmp, _ := gozxing.NewBinaryBitmapFromImage(img)
+
+	reader := NewGenericMultipleBarcodeReader(NewByQuadrantReader(qrcode.NewQRCodeReader()))
+	results, e := reader.DecodeMultiple(bmp, nil)
+	if e != nil {
+		t.Fatalf("DecodeMultiple returns error, %v", e)
+	}
+	found := map[string]bool{}
+	for _, result := range results {
+		found[result.GetText()] = true
+	}
+	if len(results) != 2 || !found["QR1"] || !found["QR2"] {
+		t.Fatalf("results = %v, wants QR1 and QR2", results)
+	}
+}
+
+func TestGenericMultipleBarcodeReader_DecodeMultipleFail(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(300, 300)
+	bmp, _ := gozxing.NewBinaryBitmapFromImage(img)
+
+	reader := NewGenericMultipleBarcodeReader(oned.NewCode128Reader())
+	_, e := reader.DecodeMultipleWithoutHint(bmp)
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("DecodeMultiple must be NotFoundException, %T", e)
+	}
+
+	delegate := &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			return nil, errors.New("error")
+		},
+	}
+	reader = NewGenericMultipleBarcodeReader(delegate)
+	if _, e = reader.DecodeMultipleWithoutHint(bmp); e == nil {
+		t.Fatalf("DecodeMultiple must be error")
+	}
+}
+
+func TestGenericMultipleBarcodeReader_MaxDepth(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(2000, 300)
+	bmp, _ := gozxing.NewBinaryBitmapFromImage(img)
+
+	// always finds a barcode at the left edge of the image, then the whole image is decoded as the right region
+	delegate := &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			y := float64(image.GetHeight() - 1)
+			points := []gozxing.ResultPoint{gozxing.NewResultPoint(0, 0), gozxing.NewResultPoint(0, y)}
+			return gozxing.NewResult("text", nil, points, gozxing.BarcodeFormat_CODE_128), nil
+		},
+	}
+	reader := NewGenericMultipleBarcodeReader(delegate)
+	results, e := reader.DecodeMultipleWithoutHint(bmp)
+	if e != nil {
+		t.Fatalf("DecodeMultiple returns error, %v", e)
+	}
+	if n := len(results); n != 1 {
+		t.Fatalf("len(results) = %v, wants 1", n)
+	}
+	// depth 0 to MAX_DEPTH
+	if n, wants := len(delegate.regions), genericMultipleBarcodeReader_MAX_DEPTH+1; n != wants {
+		t.Fatalf("decoded regions = %v, wants %v", n, wants)
+	}
+	if p := results[0].GetResultPoints()[1]; p.GetX() != 0 || p.GetY() != 299 {
+		t.Fatalf("result point = %v, wants (0, 299)", p)
+	}
+}
+
+func TestGenericMultipleBarcodeReader_DecodeMultipleContext(t *testing.T) {
+	img, _ := gozxing.NewBitMatrix(2000, 300)
+	bmp, _ := gozxing.NewBinaryBitmapFromImage(img)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// canceled after the first barcode is found, before decoding the right region
+	delegate := &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			cancel()
+			y := float64(image.GetHeight() - 1)
+			points := []gozxing.ResultPoint{gozxing.NewResultPoint(0, 0), gozxing.NewResultPoint(0, y)}
+			return gozxing.NewResult("text", nil, points, gozxing.BarcodeFormat_CODE_128), nil
+		},
+	}
+	reader := NewGenericMultipleBarcodeReader(delegate)
+	_, e := reader.DecodeMultipleContext(ctx, bmp, nil)
+	if _, ok := e.(gozxing.ReaderException); !ok {
+		t.Fatalf("DecodeMultipleContext must be ReaderException, %T", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DecodeMultipleContext must wrap context.Canceled, %v", e)
+	}
+	if n := len(delegate.regions); n != 1 {
+		t.Fatalf("decoded regions = %v, wants 1", n)
+	}
+
+	// the error of the delegate is not ignored when the context is done
+	ctx, cancel = context.WithCancel(context.Background())
+	delegate = &testQuadrantReader{
+		decode: func(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+			cancel()
+			return nil, gozxing.NewNotFoundException()
+		},
+	}
+	reader = NewGenericMultipleBarcodeReader(delegate)
+	_, e = reader.DecodeMultipleContext(ctx, bmp, nil)
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DecodeMultipleContext must wrap context.Canceled, %v", e)
+	}
+}
+
+func TestTranslateResultPoints(t *testing.T) {
+	result := gozxing.NewResult("text", []byte{1}, nil, gozxing.BarcodeFormat_QR_CODE)
+	if r := TranslateResultPoints(result, 1, 2); r != result {
+		t.Fatalf("TranslateResultPoints without points must return the same result")
+	}
+
+	points := []gozxing.ResultPoint{gozxing.NewResultPoint(1, 2), nil}
+	result = gozxing.NewResult("text", []byte{1}, points, gozxing.BarcodeFormat_QR_CODE)
+	result.PutMetadata(gozxing.ResultMetadataType_ORIENTATION, 90)
+	r := TranslateResultPoints(result, 10, 20)
+	if txt := r.GetText(); txt != "text" {
+		t.Fatalf("text = %v, wants text", txt)
+	}
+	if o := r.GetResultMetadata()[gozxing.ResultMetadataType_ORIENTATION]; o != 90 {
+		t.Fatalf("ORIENTATION = %v, wants 90", o)
+	}
+	newPoints := r.GetResultPoints()
+	if p := newPoints[0]; p.GetX() != 11 || p.GetY() != 22 {
+		t.Fatalf("points[0] = %v, wants (11, 22)", p)
+	}
+	if newPoints[1] != nil {
+		t.Fatalf("points[1] = %v, wants nil", newPoints[1])
+	}
+	if p := points[0]; p.GetX() != 1 || p.GetY() != 2 {
+		t.Fatalf("original points must not be modified, %v", p)
+	}
+}
@@ -1,6 +1,8 @@
 package multi
 
 import (
+	"context"
+
 	"github.com/makiuchi-d/gozxing"
 )
 
@@ -13,3 +15,14 @@ type MultipleBarcodeReader interface {
 
 	DecodeMultiple(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error)
 }
+
+// MultipleBarcodeReaderWithContext is the MultipleBarcodeReader which can stop decoding when the context is done.
+type MultipleBarcodeReaderWithContext interface {
+	MultipleBarcodeReader
+
+	// DecodeMultipleContext Decodes the barcodes in the image as DecodeMultiple,
+	// and stops when the context is canceled or its deadline is exceeded.
+	//
+	// @throws ReaderException wrapping ctx.Err() if the context is done
+	DecodeMultipleContext(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error)
+}
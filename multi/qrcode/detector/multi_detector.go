@@ -1,6 +1,8 @@
 package detector
 
 import (
+	"context"
+
 	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/common"
 	"github.com/makiuchi-d/gozxing/qrcode/detector"
@@ -19,11 +21,22 @@ func NewMultiDetector(image *gozxing.BitMatrix) *MultiDetector {
 }
 
 func (this *MultiDetector) DetectMulti(hints map[gozxing.DecodeHintType]interface{}) ([]*common.DetectorResult, error) {
+	return this.DetectMultiContext(context.Background(), hints)
+}
+
+// DetectMultiContext Detects the QR Codes as DetectMulti, and stops when the context is done.
+//
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *MultiDetector) DetectMultiContext(ctx context.Context, hints map[gozxing.DecodeHintType]interface{}) ([]*common.DetectorResult, error) {
 	image := this.GetImage()
 	resultPointCallback, _ := hints[gozxing.DecodeHintType_NEED_RESULT_POINT_CALLBACK].(gozxing.ResultPointCallback)
 
 	finder := NewMultiFinderPatternFinder(image, resultPointCallback)
-	infos, e := finder.FindMulti(hints)
+	infos, e := finder.FindMultiContext(ctx, hints)
+	if _, ok := e.(gozxing.NotFoundException); e != nil && !ok {
+		return nil, e
+	}
 	if e != nil || len(infos) == 0 {
 		return nil, gozxing.WrapNotFoundException(e)
 	}
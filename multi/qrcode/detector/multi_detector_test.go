@@ -1,8 +1,11 @@
 package detector
 
 import (
+	"context"
 	"testing"
 
+	errors "golang.org/x/xerrors"
+
 	"github.com/makiuchi-d/gozxing"
 )
 
@@ -67,3 +70,26 @@ FORTESTCALLBACKS:
 		t.Fatalf("callbacked points must contain %v", test)
 	}
 }
+
+func TestMultiDetector_DetectMultiContext(t *testing.T) {
+	img, _ := gozxing.ParseStringToBitMatrix(qrstr, "##", "  ")
+	det := NewMultiDetector(img)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, e := det.DetectMultiContext(ctx, nil)
+	if _, ok := e.(gozxing.NotFoundException); ok {
+		t.Fatalf("DetectMultiContext must not be NotFoundException, %v", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DetectMultiContext must wrap context.Canceled, %v", e)
+	}
+
+	results, e := det.DetectMultiContext(context.Background(), nil)
+	if e != nil {
+		t.Fatalf("DetectMultiContext returns error: %v", e)
+	}
+	if len(results) == 0 {
+		t.Fatalf("DetectMultiContext returns no results")
+	}
+}
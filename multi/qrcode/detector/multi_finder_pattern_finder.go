@@ -1,6 +1,7 @@
 package detector
 
 import (
+	"context"
 	"math"
 	"sort"
 
@@ -194,6 +195,16 @@ func (this *MultiFinderPatternFinder) selectMultipleBestPatterns() ([][]*detecto
 }
 
 func (this *MultiFinderPatternFinder) FindMulti(hints map[gozxing.DecodeHintType]interface{}) ([]*detector.FinderPatternInfo, error) {
+	return this.FindMultiContext(context.Background(), hints)
+}
+
+// FindMultiContext Finds the finder patterns of the QR Codes as FindMulti,
+// and stops scanning the rows when the context is done.
+//
+// @throws NotFoundException if no finder patterns are found
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *MultiFinderPatternFinder) FindMultiContext(ctx context.Context, hints map[gozxing.DecodeHintType]interface{}) ([]*detector.FinderPatternInfo, error) {
 	_, tryHarder := hints[gozxing.DecodeHintType_TRY_HARDER]
 	image := this.GetImage()
 	maxI := image.GetHeight()
@@ -212,6 +223,9 @@ func (this *MultiFinderPatternFinder) FindMulti(hints map[gozxing.DecodeHintType
 
 	stateCount := make([]int, 5)
 	for i := iSkip - 1; i < maxI; i += iSkip {
+		if e := gozxing.Reader_CheckContext(ctx); e != nil {
+			return nil, e
+		}
 		// Get a row of black/white values
 		detector.FinderPatternFinder_doClearCounts(stateCount)
 		currentState := 0
@@ -1,11 +1,14 @@
 package detector
 
 import (
+	"context"
 	"reflect"
 	"sort"
 	"testing"
 	"unsafe"
 
+	errors "golang.org/x/xerrors"
+
 	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/qrcode/detector"
 )
@@ -207,3 +210,18 @@ FORTESTPOINTS:
 			test.tlx, test.tly, test.blx, test.bly, test.trx, test.try)
 	}
 }
+
+func TestMultiFinderPatternFinder_FindMultiContext(t *testing.T) {
+	img, _ := gozxing.ParseStringToBitMatrix(qrstr, "##", "  ")
+	finder := NewMultiFinderPatternFinder(img, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, e := finder.FindMultiContext(ctx, nil)
+	if _, ok := e.(gozxing.NotFoundException); ok {
+		t.Fatalf("FindMultiContext must not be NotFoundException, %v", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("FindMultiContext must wrap context.Canceled, %v", e)
+	}
+}
@@ -1,6 +1,7 @@
 package qrcode
 
 import (
+	"context"
 	"sort"
 
 	"github.com/makiuchi-d/gozxing"
@@ -20,6 +21,8 @@ type QRCodeMultiReader struct {
 	*qrcode.QRCodeReader
 }
 
+var _ multi.MultipleBarcodeReaderWithContext = &QRCodeMultiReader{}
+
 func NewQRCodeMultiReader() multi.MultipleBarcodeReader {
 	return &QRCodeMultiReader{
 		qrcode.NewQRCodeReader().(*qrcode.QRCodeReader),
@@ -30,17 +33,56 @@ func (this *QRCodeMultiReader) DecodeMultipleWithoutHint(image *gozxing.BinaryBi
 	return this.DecodeMultiple(image, nil)
 }
 
+// DecodeMultiple Locates and decodes the QR codes in an image.
+//
+// If the ALSO_INVERTED hint is set, the QR codes in the inverted image are also decoded,
+// and their results have the INVERTED metadata.
+//
 func (this *QRCodeMultiReader) DecodeMultiple(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error) {
+	return this.DecodeMultipleContext(context.Background(), image, hints)
+}
+
+// DecodeMultipleContext Locates and decodes the QR codes in an image as DecodeMultiple,
+// and stops when the context is done.
+//
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *QRCodeMultiReader) DecodeMultipleContext(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error) {
+	results, e := this.decodeMultiple(ctx, image, hints)
+	if _, ok := hints[gozxing.DecodeHintType_ALSO_INVERTED]; !ok {
+		return results, e
+	}
+	if _, ok := e.(gozxing.ReaderException); e != nil && !ok {
+		return results, e
+	}
+	inverted, err := image.Invert()
+	if err != nil {
+		return results, e
+	}
+	invertedResults, err := this.decodeMultiple(ctx, inverted, hints)
+	if err != nil || len(invertedResults) == 0 {
+		return results, e
+	}
+	for _, result := range invertedResults {
+		result.PutMetadata(gozxing.ResultMetadataType_INVERTED, true)
+	}
+	return append(results, invertedResults...), nil
+}
+
+func (this *QRCodeMultiReader) decodeMultiple(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error) {
 	results := make([]*gozxing.Result, 0)
 	matrix, e := image.GetBlackMatrix()
 	if e != nil {
 		return results, e
 	}
-	detectorResults, e := detector.NewMultiDetector(matrix).DetectMulti(hints)
+	detectorResults, e := detector.NewMultiDetector(matrix).DetectMultiContext(ctx, hints)
 	if e != nil {
 		return results, e
 	}
 	for _, detectorResult := range detectorResults {
+		if e := gozxing.Reader_CheckContext(ctx); e != nil {
+			return results, e
+		}
 		decoderResult, e := this.GetDecoder().Decode(detectorResult.GetBits(), hints)
 		if e != nil {
 			if _, ok := e.(gozxing.ReaderException); ok {
@@ -1,11 +1,15 @@
 package qrcode
 
 import (
+	"context"
 	"reflect"
 	"sort"
 	"testing"
 
+	errors "golang.org/x/xerrors"
+
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/multi"
 	"github.com/makiuchi-d/gozxing/testutil"
 )
 
@@ -206,3 +210,65 @@ func TestQRCodeMultiReader_DecodeMultipleWithoutHint(t *testing.T) {
 		}
 	}
 }
+
+func TestQRCodeMultiReader_DecodeMultipleAlsoInverted(t *testing.T) {
+	reader := NewQRCodeMultiReader()
+	file := "testdata/1.png"
+	img, _ := testutil.NewBinaryBitmapFromFile(file).Invert()
+
+	if _, e := reader.DecodeMultiple(img, nil); e == nil {
+		t.Fatalf("DecodeMultiple(inverted %s) without ALSO_INVERTED must be error", file)
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	results, e := reader.DecodeMultiple(img, hints)
+	if e != nil {
+		t.Fatalf("DecodeMultiple(inverted %s) returns error: %v", file, e)
+	}
+	if n := len(results); n != 4 {
+		t.Fatalf("DecodeMultiple(inverted %s) len(results) = %v, wants 4", file, n)
+	}
+	for i, r := range results {
+		if inv := r.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; inv != true {
+			t.Fatalf("DecodeMultiple(inverted %s) results[%v] INVERTED = %v, wants true", file, i, inv)
+		}
+	}
+
+	results, e = reader.DecodeMultiple(testutil.NewBinaryBitmapFromFile(file), hints)
+	if e != nil {
+		t.Fatalf("DecodeMultiple(%s) returns error: %v", file, e)
+	}
+	if n := len(results); n != 4 {
+		t.Fatalf("DecodeMultiple(%s) len(results) = %v, wants 4", file, n)
+	}
+	for i, r := range results {
+		if inv, ok := r.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; ok {
+			t.Fatalf("DecodeMultiple(%s) results[%v] INVERTED = %v, wants none", file, i, inv)
+		}
+	}
+}
+
+func TestQRCodeMultiReader_DecodeMultipleContext(t *testing.T) {
+	reader := NewQRCodeMultiReader().(multi.MultipleBarcodeReaderWithContext)
+	bmp := testutil.NewBinaryBitmapFromFile("testdata/1.png")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, e := reader.DecodeMultipleContext(ctx, bmp, nil)
+	if _, ok := e.(gozxing.ReaderException); !ok {
+		t.Fatalf("DecodeMultipleContext must be ReaderException, %T", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DecodeMultipleContext must wrap context.Canceled, %v", e)
+	}
+
+	results, e := reader.DecodeMultipleContext(context.Background(), bmp, nil)
+	if e != nil {
+		t.Fatalf("DecodeMultipleContext returns error: %v", e)
+	}
+	if len(results) != 4 {
+		t.Fatalf("len(results) = %v, wants 4", len(results))
+	}
+}
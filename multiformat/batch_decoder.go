@@ -0,0 +1,197 @@
+package multiformat
+
+import (
+	"context"
+	"image"
+	"runtime"
+	"sync"
+
+	errors "golang.org/x/xerrors"
+
+	"github.com/makiuchi-d/gozxing"
+)
+
+// BatchResult is the result of an image decoded by BatchDecoder.
+type BatchResult struct {
+	Index  int             // the index of the image in the order it was received
+	Result *gozxing.Result // the decoded result, or nil if failed
+	Err    error           // the error, such as NotFoundException, or nil if decoded
+}
+
+// BatchDecoder decodes many images with a bounded number of worker goroutines.
+//
+// Each worker has its own readers and the buffers for the black matrix and the rows,
+// which are reused for the images it decodes.
+type BatchDecoder struct {
+	workers int
+	hints   map[gozxing.DecodeHintType]interface{}
+}
+
+// NewBatchDecoder creates a BatchDecoder.
+//
+// @param workers the number of the worker goroutines, or runtime.NumCPU() if it is not positive
+// @param hints the hints to decode all the images with
+//
+func NewBatchDecoder(workers int, hints map[gozxing.DecodeHintType]interface{}) *BatchDecoder {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &BatchDecoder{workers, hints}
+}
+
+// DecodeImages Decodes the images received from the channel until it is closed.
+//
+// The results are sent to the returned channel in the order they are decoded,
+// which is closed after all the images are decoded, or the context is done.
+// The images not decoded before the context is done are not reported.
+// The caller must receive all the results, or cancel the context, not to block the workers.
+//
+// @param ctx the context to stop decoding
+// @param images the images to decode
+// @return the channel of the results tagged with the index of the image
+//
+func (this *BatchDecoder) DecodeImages(ctx context.Context, images <-chan image.Image) <-chan *BatchResult {
+	jobs := make(chan batchJob)
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			var img image.Image
+			var ok bool
+			select {
+			case img, ok = <-images:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			source := func() (gozxing.LuminanceSource, error) {
+				if img == nil {
+					return nil, errors.New("IllegalArgumentException: image must be non-null")
+				}
+				return gozxing.NewLuminanceSourceFromImage(img), nil
+			}
+			select {
+			case jobs <- batchJob{index, source}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return this.dispatch(ctx, jobs)
+}
+
+// DecodeSources Decodes the luminance sources received from the channel until it is closed,
+// as DecodeImages.
+//
+// @param ctx the context to stop decoding
+// @param sources the luminance sources to decode
+// @return the channel of the results tagged with the index of the source
+//
+func (this *BatchDecoder) DecodeSources(ctx context.Context, sources <-chan gozxing.LuminanceSource) <-chan *BatchResult {
+	jobs := make(chan batchJob)
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			var src gozxing.LuminanceSource
+			var ok bool
+			select {
+			case src, ok = <-sources:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			source := func() (gozxing.LuminanceSource, error) {
+				if src == nil {
+					return nil, errors.New("IllegalArgumentException: source must be non-null")
+				}
+				return src, nil
+			}
+			select {
+			case jobs <- batchJob{index, source}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return this.dispatch(ctx, jobs)
+}
+
+// batchJob is an input of the workers.
+// The source is created by the worker, since converting an image.Image is as heavy as decoding.
+type batchJob struct {
+	index  int
+	source func() (gozxing.LuminanceSource, error)
+}
+
+// dispatch Decodes the jobs on the worker goroutines until the jobs channel is closed.
+func (this *BatchDecoder) dispatch(ctx context.Context, jobs <-chan batchJob) <-chan *BatchResult {
+	results := make(chan *BatchResult, this.workers)
+
+	var wg sync.WaitGroup
+	wg.Add(this.workers)
+	for i := 0; i < this.workers; i++ {
+		go func() {
+			defer wg.Done()
+			worker := newBatchWorker(this.hints)
+			for job := range jobs {
+				result, e := worker.decode(ctx, job.source)
+				if gozxing.Reader_CheckContext(ctx) != nil {
+					// the rest of the jobs are dropped until the jobs channel is closed
+					continue
+				}
+				select {
+				case results <- &BatchResult{job.index, result, e}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// batchWorker keeps the readers and the buffer of the black matrix for a worker goroutine.
+// The 1D readers keep their row buffers in themselves.
+type batchWorker struct {
+	reader  *MultiFormatReader
+	hints   map[gozxing.DecodeHintType]interface{}
+	readers []gozxing.Reader
+	matrix  *gozxing.BitMatrix
+}
+
+func newBatchWorker(hints map[gozxing.DecodeHintType]interface{}) *batchWorker {
+	reader := NewMultiFormatReader()
+	readers := reader.setHints(hints)
+	return &batchWorker{
+		reader:  reader,
+		hints:   hints,
+		readers: readers,
+	}
+}
+
+// decode Decodes the source by the readers of the worker, reusing the buffer of the black matrix.
+func (this *batchWorker) decode(ctx context.Context, source func() (gozxing.LuminanceSource, error)) (*gozxing.Result, error) {
+	src, e := source()
+	if e != nil {
+		return nil, e
+	}
+	width, height := src.GetWidth(), src.GetHeight()
+	if this.matrix == nil || this.matrix.GetWidth() != width || this.matrix.GetHeight() != height {
+		this.matrix, _ = gozxing.NewBitMatrix(width, height)
+	}
+	image, e := gozxing.NewBinaryBitmap(gozxing.NewHybridBinarizerWithBuffer(src, this.matrix))
+	if e != nil {
+		return nil, e
+	}
+
+	// the state of the readers, such as the pairs of the RSS-14 reader, must not be carried to the next image
+	defer this.reader.Reset()
+	return this.reader.decodeInternal(ctx, image, this.hints, this.readers)
+}
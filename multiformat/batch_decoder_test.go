@@ -0,0 +1,196 @@
+package multiformat
+
+import (
+	"context"
+	"image"
+	"runtime"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+type batchTest struct {
+	image image.Image
+	text  string // empty if the image cannot be decoded
+}
+
+func newBatchTests(t testing.TB) []batchTest {
+	blank, _ := gozxing.NewBitMatrix(100, 100)
+	qr := newTestImage(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	return []batchTest{
+		{qr, "QR Code"},
+		{newTestImage(t, datamatrix.NewDataMatrixWriter(), "Data Matrix", gozxing.BarcodeFormat_DATA_MATRIX, 100, 100), "Data Matrix"},
+		{blank, ""},
+		{newTestImage(t, oned.NewCode128Writer(), "Code 128", gozxing.BarcodeFormat_CODE_128, 200, 50), "Code 128"},
+		{qr, "QR Code"},
+		{newTestImage(t, qrcode.NewQRCodeWriter(), "Same size", gozxing.BarcodeFormat_QR_CODE, 100, 100), "Same size"},
+		{newTestImage(t, oned.NewEAN13Writer(), "4901234567894", gozxing.BarcodeFormat_EAN_13, 200, 50), "4901234567894"},
+	}
+}
+
+func testBatchResults(t testing.TB, results <-chan *BatchResult, tests []batchTest) {
+	t.Helper()
+	found := make([]bool, len(tests))
+	for r := range results {
+		if r.Index < 0 || r.Index >= len(tests) {
+			t.Fatalf("result index = %v, expect [0, %v)", r.Index, len(tests))
+		}
+		if found[r.Index] {
+			t.Fatalf("result[%v] is reported twice", r.Index)
+		}
+		found[r.Index] = true
+
+		test := tests[r.Index]
+		if test.text == "" {
+			if _, ok := r.Err.(gozxing.NotFoundException); !ok {
+				t.Fatalf("result[%v] must be NotFoundException, %v, %v", r.Index, r.Result, r.Err)
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Fatalf("result[%v] error, %v", r.Index, r.Err)
+		}
+		if txt := r.Result.GetText(); txt != test.text {
+			t.Fatalf("result[%v] text = \"%v\", expect \"%v\"", r.Index, txt, test.text)
+		}
+	}
+	for i, f := range found {
+		if !f {
+			t.Fatalf("result[%v] is not reported", i)
+		}
+	}
+}
+
+func TestNewBatchDecoder(t *testing.T) {
+	decoder := NewBatchDecoder(0, nil)
+	if decoder.workers != runtime.NumCPU() {
+		t.Fatalf("workers = %v, expect %v", decoder.workers, runtime.NumCPU())
+	}
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	}
+	decoder = NewBatchDecoder(3, hints)
+	if decoder.workers != 3 {
+		t.Fatalf("workers = %v, expect 3", decoder.workers)
+	}
+	if _, ok := decoder.hints[gozxing.DecodeHintType_TRY_HARDER]; !ok {
+		t.Fatalf("hints = %v, expect %v", decoder.hints, hints)
+	}
+}
+
+func TestBatchDecoder_DecodeImages(t *testing.T) {
+	tests := newBatchTests(t)
+	for _, workers := range []int{1, 3} {
+		images := make(chan image.Image)
+		go func() {
+			defer close(images)
+			for _, test := range tests {
+				images <- test.image
+			}
+		}()
+		results := NewBatchDecoder(workers, nil).DecodeImages(context.Background(), images)
+		testBatchResults(t, results, tests)
+	}
+
+	// nil image
+	images := make(chan image.Image, 1)
+	images <- nil
+	close(images)
+	r := <-NewBatchDecoder(1, nil).DecodeImages(context.Background(), images)
+	if r.Err == nil {
+		t.Fatalf("DecodeImages(nil) must be error, %v", r.Result)
+	}
+}
+
+func TestBatchDecoder_DecodeSources(t *testing.T) {
+	tests := newBatchTests(t)
+	sources := make(chan gozxing.LuminanceSource)
+	go func() {
+		defer close(sources)
+		for _, test := range tests {
+			sources <- gozxing.NewLuminanceSourceFromImage(test.image)
+		}
+	}()
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	}
+	results := NewBatchDecoder(2, hints).DecodeSources(context.Background(), sources)
+	testBatchResults(t, results, tests)
+
+	// nil source
+	sources = make(chan gozxing.LuminanceSource, 1)
+	sources <- nil
+	close(sources)
+	r := <-NewBatchDecoder(1, nil).DecodeSources(context.Background(), sources)
+	if r.Err == nil {
+		t.Fatalf("DecodeSources(nil) must be error, %v", r.Result)
+	}
+}
+
+func TestBatchDecoder_Cancel(t *testing.T) {
+	tests := newBatchTests(t)
+	decoder := NewBatchDecoder(2, nil)
+
+	// the input channel is not closed, but the results channel is closed by the context
+	ctx, cancel := context.WithCancel(context.Background())
+	images := make(chan image.Image)
+	results := decoder.DecodeImages(ctx, images)
+	images <- tests[0].image
+	r := <-results
+	if r.Err != nil || r.Result.GetText() != tests[0].text {
+		t.Fatalf("result = %v, %v, expect %v", r.Result, r.Err, tests[0].text)
+	}
+	cancel()
+	for r := range results {
+		t.Fatalf("no results must be reported after canceled, %v", r)
+	}
+
+	sources := make(chan gozxing.LuminanceSource)
+	results = decoder.DecodeSources(ctx, sources)
+	for r := range results {
+		t.Fatalf("no results must be reported with the canceled context, %v", r)
+	}
+}
+
+func TestBatchWorker_decode(t *testing.T) {
+	tests := newBatchTests(t)
+	worker := newBatchWorker(nil)
+
+	source := func(img image.Image) func() (gozxing.LuminanceSource, error) {
+		return func() (gozxing.LuminanceSource, error) {
+			return gozxing.NewLuminanceSourceFromImage(img), nil
+		}
+	}
+
+	if _, e := worker.decode(context.Background(), source(tests[0].image)); e != nil {
+		t.Fatalf("decode returns error, %v", e)
+	}
+	buffer := worker.matrix
+
+	// the buffer is reused for the image of the same size
+	r, e := worker.decode(context.Background(), source(tests[5].image))
+	if e != nil {
+		t.Fatalf("decode returns error, %v", e)
+	}
+	if txt := r.GetText(); txt != tests[5].text {
+		t.Fatalf("decode text = \"%v\", expect \"%v\"", txt, tests[5].text)
+	}
+	if worker.matrix != buffer {
+		t.Fatalf("the buffer must be reused for the same size")
+	}
+
+	// the buffer is replaced for the image of the different size
+	r, e = worker.decode(context.Background(), source(tests[3].image))
+	if e != nil {
+		t.Fatalf("decode returns error, %v", e)
+	}
+	if txt := r.GetText(); txt != tests[3].text {
+		t.Fatalf("decode text = \"%v\", expect \"%v\"", txt, tests[3].text)
+	}
+	if worker.matrix == buffer {
+		t.Fatalf("the buffer must be replaced for the different size")
+	}
+}
@@ -0,0 +1,221 @@
+package multiformat
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+)
+
+// ConcurrentMultiFormatReader decodes an image by the readers of MultiFormatReader in parallel.
+//
+// Each call creates the readers and starts up to the given number of goroutines to run them.
+// When any 2D reader is used, the black matrix of the image is created once before the dispatch
+// and shared by all the readers.
+// Since nothing is kept between the calls, a ConcurrentMultiFormatReader can be used
+// by multiple goroutines at the same time.
+//
+// The readers which do not implement gozxing.ReaderWithContext, such as the Data Matrix and Aztec readers,
+// cannot be stopped once they have started. They run to the end in the background
+// even after the first success or the context is done, and their results are discarded.
+type ConcurrentMultiFormatReader struct {
+	workers int
+}
+
+var _ gozxing.ReaderWithContext = &ConcurrentMultiFormatReader{}
+
+// NewConcurrentMultiFormatReader creates a ConcurrentMultiFormatReader.
+//
+// @param workers the number of the goroutines for each call, or runtime.NumCPU() if it is not positive
+//
+func NewConcurrentMultiFormatReader(workers int) *ConcurrentMultiFormatReader {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &ConcurrentMultiFormatReader{workers}
+}
+
+func (this *ConcurrentMultiFormatReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+	return this.DecodeContext(context.Background(), image, nil)
+}
+
+// Decode Decodes an image by all the readers in parallel, and returns the first success.
+//
+// @param image The pixel data to decode
+// @param hints The hints to use
+// @return The contents of the image found first
+// @throws NotFoundException if no reader can decode the image
+//
+func (this *ConcurrentMultiFormatReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return this.DecodeContext(context.Background(), image, hints)
+}
+
+// DecodeContext Decodes an image by all the readers in parallel, and returns the first success.
+// The other readers are stopped as soon as one of them succeeds,
+// except the ones which do not implement gozxing.ReaderWithContext.
+//
+// @param ctx The context to stop decoding
+// @param image The pixel data to decode
+// @param hints The hints to use
+// @return The contents of the image found first
+// @throws NotFoundException if no reader can decode the image
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *ConcurrentMultiFormatReader) DecodeContext(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	decode := func(image *gozxing.BinaryBitmap, _ map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+		results, e := this.dispatch(ctx, image, hints, true)
+		if e != nil {
+			return nil, e
+		}
+		return results[0], nil
+	}
+	return gozxing.Reader_DecodeAlsoInverted(image, hints, decode)
+}
+
+// DecodeAll Decodes an image by all the readers in parallel, and returns all the successes.
+//
+// @param image The pixel data to decode
+// @param hints The hints to use
+// @return The results of the readers which succeeded, in the order of the readers
+// @throws NotFoundException if no reader can decode the image
+//
+func (this *ConcurrentMultiFormatReader) DecodeAll(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error) {
+	return this.DecodeAllContext(context.Background(), image, hints)
+}
+
+// DecodeAllContext Decodes an image by all the readers in parallel as DecodeAll,
+// and stops the readers when the context is done.
+//
+// @param ctx The context to stop decoding
+// @param image The pixel data to decode
+// @param hints The hints to use
+// @return The results of the readers which succeeded, in the order of the readers
+// @throws NotFoundException if no reader can decode the image
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *ConcurrentMultiFormatReader) DecodeAllContext(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) ([]*gozxing.Result, error) {
+	results, e := this.dispatch(ctx, image, hints, false)
+	if e == nil {
+		return results, nil
+	}
+	if _, ok := hints[gozxing.DecodeHintType_ALSO_INVERTED]; !ok {
+		return nil, e
+	}
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		return nil, e
+	}
+	inverted, err := image.Invert()
+	if err != nil {
+		return nil, e
+	}
+	results, err = this.dispatch(ctx, inverted, hints, false)
+	if err != nil {
+		return nil, e
+	}
+	for _, result := range results {
+		result.PutMetadata(gozxing.ResultMetadataType_INVERTED, true)
+	}
+	return results, nil
+}
+
+func (this *ConcurrentMultiFormatReader) Reset() {
+	// nothing to do, the readers are created for each call
+}
+
+// dispatch Decodes the image by the readers on the worker goroutines.
+//
+// @param first if true, returns the first success and stops the other readers
+// @return the results in the order of the readers, or only the first success
+// @throws NotFoundException if no reader can decode the image
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *ConcurrentMultiFormatReader) dispatch(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}, first bool) ([]*gozxing.Result, error) {
+	if e := gozxing.Reader_CheckContext(ctx); e != nil {
+		return nil, e
+	}
+
+	readers := multiFormatReader_createReaders(hints)
+	hints = multiFormatReader_removeAlsoInverted(hints)
+
+	// Create the shared black matrix before the dispatch, so that the 2D readers do not wait for each other.
+	// The 1D readers work with the black rows, and do not need it.
+	for _, reader := range readers {
+		if _, ok := reader.(oned.RowDecoder); !ok {
+			_, _ = image.GetBlackMatrix()
+			break
+		}
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type output struct {
+		index  int
+		result *gozxing.Result
+		err    error
+	}
+	indices := make(chan int, len(readers))
+	outputs := make(chan output, len(readers))
+	for i := range readers {
+		indices <- i
+	}
+	close(indices)
+
+	workers := this.workers
+	if workers > len(readers) {
+		workers = len(readers)
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				result, e := gozxing.Reader_DecodeContext(workerCtx, readers[i], image, hints)
+				outputs <- output{i, result, e}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(outputs)
+	}()
+
+	results := make([]*gozxing.Result, len(readers))
+	found := false
+	var err error
+	for o := range outputs {
+		if o.err == nil {
+			if first {
+				// the other workers are stopped by cancel(), except the readers without the context,
+				// and outputs is buffered not to block them
+				return []*gozxing.Result{o.result}, nil
+			}
+			results[o.index] = o.result
+			found = true
+			continue
+		}
+		if _, ok := o.err.(gozxing.ReaderException); !ok && err == nil {
+			err = o.err
+		}
+	}
+
+	if found {
+		founds := make([]*gozxing.Result, 0, len(results))
+		for _, result := range results {
+			if result != nil {
+				founds = append(founds, result)
+			}
+		}
+		return founds, nil
+	}
+	if e := gozxing.Reader_CheckContext(ctx); e != nil {
+		return nil, e
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, gozxing.NewNotFoundException()
+}
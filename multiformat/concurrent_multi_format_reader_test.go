@@ -0,0 +1,296 @@
+package multiformat
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	errors "golang.org/x/xerrors"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/makiuchi-d/gozxing/testutil"
+)
+
+// newTestQRAndCode128 creates the image of a QR Code above a Code 128
+func newTestQRAndCode128(t testing.TB) *gozxing.BinaryBitmap {
+	t.Helper()
+	qr, e := qrcode.NewQRCodeWriter().EncodeWithoutHint("QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	if e != nil {
+		t.Fatalf("Encode(QR_CODE) returns error, %v", e)
+	}
+	code128, e := oned.NewCode128Writer().EncodeWithoutHint("Code 128", gozxing.BarcodeFormat_CODE_128, 200, 50)
+	if e != nil {
+		t.Fatalf("Encode(CODE_128) returns error, %v", e)
+	}
+	img, _ := gozxing.NewBitMatrix(220, 180)
+	for y := 0; y < qr.GetHeight(); y++ {
+		for x := 0; x < qr.GetWidth(); x++ {
+			if qr.Get(x, y) {
+				img.Set(x+60, y+10)
+			}
+		}
+	}
+	for y := 0; y < code128.GetHeight(); y++ {
+		for x := 0; x < code128.GetWidth(); x++ {
+			if code128.Get(x, y) {
+				img.Set(x+10, y+120)
+			}
+		}
+	}
+	return testutil.NewBinaryBitmapFromBitMatrix(img)
+}
+
+func TestNewConcurrentMultiFormatReader(t *testing.T) {
+	reader := NewConcurrentMultiFormatReader(0)
+	if reader.workers != runtime.NumCPU() {
+		t.Fatalf("workers = %v, expect %v", reader.workers, runtime.NumCPU())
+	}
+	reader = NewConcurrentMultiFormatReader(3)
+	if reader.workers != 3 {
+		t.Fatalf("workers = %v, expect 3", reader.workers)
+	}
+}
+
+func TestConcurrentMultiFormatReader_Decode(t *testing.T) {
+	qr := newTestBinaryBitmap(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	code128 := newTestBinaryBitmap(t, oned.NewCode128Writer(), "Code 128", gozxing.BarcodeFormat_CODE_128, 200, 50)
+	az := testutil.NewBinaryBitmapFromFile("testdata/aztec.png")
+
+	for _, workers := range []int{1, 4} {
+		reader := NewConcurrentMultiFormatReader(workers)
+		reader.Reset()
+
+		result, e := reader.DecodeWithoutHints(qr)
+		if e != nil {
+			t.Fatalf("DecodeWithoutHints returns error, %v", e)
+		}
+		if txt := result.GetText(); txt != "QR Code" {
+			t.Fatalf("DecodeWithoutHints text = \"%v\", expect \"QR Code\"", txt)
+		}
+
+		result, e = reader.Decode(code128, nil)
+		if e != nil {
+			t.Fatalf("Decode returns error, %v", e)
+		}
+		if txt := result.GetText(); txt != "Code 128" {
+			t.Fatalf("Decode text = \"%v\", expect \"Code 128\"", txt)
+		}
+
+		result, e = reader.Decode(az, nil)
+		if e != nil {
+			t.Fatalf("Decode returns error, %v", e)
+		}
+		if txt := result.GetText(); txt != "Code 2D!" {
+			t.Fatalf("Decode text = \"%v\", expect \"Code 2D!\"", txt)
+		}
+	}
+
+	reader := NewConcurrentMultiFormatReader(0)
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_QR_CODE},
+	}
+	if _, e := reader.Decode(code128, hints); e == nil {
+		t.Fatalf("Decode(CODE_128) with POSSIBLE_FORMATS QR_CODE must be error")
+	}
+
+	img, _ := gozxing.NewBitMatrix(50, 50)
+	if _, e := reader.Decode(testutil.NewBinaryBitmapFromBitMatrix(img), nil); e == nil {
+		t.Fatalf("Decode must be error")
+	} else if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("Decode must be NotFoundException, %T", e)
+	}
+}
+
+func TestConcurrentMultiFormatReader_DecodeAll(t *testing.T) {
+	image := newTestQRAndCode128(t)
+	reader := NewConcurrentMultiFormatReader(0)
+
+	results, e := reader.DecodeAll(image, nil)
+	if e != nil {
+		t.Fatalf("DecodeAll returns error, %v", e)
+	}
+	// in the order of the readers: 1D reader is first in "normal" mode
+	expects := []struct {
+		text   string
+		format gozxing.BarcodeFormat
+	}{
+		{"Code 128", gozxing.BarcodeFormat_CODE_128},
+		{"QR Code", gozxing.BarcodeFormat_QR_CODE},
+	}
+	if len(results) != len(expects) {
+		t.Fatalf("DecodeAll results = %v, expect %v", results, expects)
+	}
+	for i, r := range results {
+		if txt, f := r.GetText(), r.GetBarcodeFormat(); txt != expects[i].text || f != expects[i].format {
+			t.Fatalf("DecodeAll results[%v] = %v(%v), expect %v(%v)", i, txt, f, expects[i].text, expects[i].format)
+		}
+	}
+
+	img, _ := gozxing.NewBitMatrix(50, 50)
+	if _, e := reader.DecodeAll(testutil.NewBinaryBitmapFromBitMatrix(img), nil); e == nil {
+		t.Fatalf("DecodeAll must be error")
+	} else if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("DecodeAll must be NotFoundException, %T", e)
+	}
+}
+
+func TestConcurrentMultiFormatReader_DecodeAlsoInverted(t *testing.T) {
+	qr := newTestBinaryBitmap(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	inverted, _ := qr.Invert()
+	reader := NewConcurrentMultiFormatReader(0)
+
+	if _, e := reader.Decode(inverted, nil); e == nil {
+		t.Fatalf("Decode(inverted) without ALSO_INVERTED must be error")
+	}
+	if _, e := reader.DecodeAll(inverted, nil); e == nil {
+		t.Fatalf("DecodeAll(inverted) without ALSO_INVERTED must be error")
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	result, e := reader.Decode(inverted, hints)
+	if e != nil {
+		t.Fatalf("Decode(inverted) returns error, %v", e)
+	}
+	if inv := result.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; inv != true {
+		t.Fatalf("Decode(inverted) INVERTED = %v, expect true", inv)
+	}
+
+	results, e := reader.DecodeAll(inverted, hints)
+	if e != nil {
+		t.Fatalf("DecodeAll(inverted) returns error, %v", e)
+	}
+	if len(results) != 1 {
+		t.Fatalf("DecodeAll(inverted) results = %v, expect 1 result", results)
+	}
+	if inv := results[0].GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; inv != true {
+		t.Fatalf("DecodeAll(inverted) INVERTED = %v, expect true", inv)
+	}
+}
+
+func TestConcurrentMultiFormatReader_DecodeContext(t *testing.T) {
+	image := newTestQRAndCode128(t)
+	reader := NewConcurrentMultiFormatReader(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, e := reader.DecodeContext(ctx, image, nil)
+	if _, ok := e.(gozxing.NotFoundException); ok {
+		t.Fatalf("DecodeContext must not be NotFoundException, %v", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DecodeContext must wrap context.Canceled, %v", e)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	_, e = reader.DecodeAllContext(ctx, image, nil)
+	if !errors.Is(e, context.DeadlineExceeded) {
+		t.Fatalf("DecodeAllContext must wrap context.DeadlineExceeded, %v", e)
+	}
+}
+
+// testCountingContext counts the calls of Err()
+type testCountingContext struct {
+	context.Context
+	errCalls int
+}
+
+func (this *testCountingContext) Err() error {
+	this.errCalls++
+	return this.Context.Err()
+}
+
+func TestConcurrentMultiFormatReader_DecodeContextAlsoInverted(t *testing.T) {
+	image := newTestQRAndCode128(t)
+	reader := NewConcurrentMultiFormatReader(0)
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	for _, parent := range []context.Context{canceled, expired} {
+		ctx := &testCountingContext{Context: parent}
+		_, e := reader.DecodeContext(ctx, image, hints)
+		if !errors.Is(e, parent.Err()) {
+			t.Fatalf("DecodeContext must wrap %v, %v", parent.Err(), e)
+		}
+		// the context is checked once before the dispatch of the original image
+		if ctx.errCalls != 1 {
+			t.Fatalf("DecodeContext must not decode the inverted image, Err() is called %v times", ctx.errCalls)
+		}
+	}
+}
+
+// testCountingBinarizer counts the calls of GetBlackMatrix()
+type testCountingBinarizer struct {
+	gozxing.Binarizer
+	matrixCalls int32
+}
+
+func (this *testCountingBinarizer) GetBlackMatrix() (*gozxing.BitMatrix, error) {
+	atomic.AddInt32(&this.matrixCalls, 1)
+	return this.Binarizer.GetBlackMatrix()
+}
+
+func TestConcurrentMultiFormatReader_BlackMatrix(t *testing.T) {
+	img := newTestImage(t, oned.NewCode128Writer(), "Code 128", gozxing.BarcodeFormat_CODE_128, 200, 50)
+	source := gozxing.NewLuminanceSourceFromImage(img)
+	reader := NewConcurrentMultiFormatReader(0)
+
+	// the black matrix is not created only for the 1D readers
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_CODE_128},
+	}
+	binarizer := &testCountingBinarizer{Binarizer: gozxing.NewHybridBinarizer(source)}
+	image, _ := gozxing.NewBinaryBitmap(binarizer)
+	if _, e := reader.Decode(image, hints); e != nil {
+		t.Fatalf("Decode returns error, %v", e)
+	}
+	if n := atomic.LoadInt32(&binarizer.matrixCalls); n != 0 {
+		t.Fatalf("GetBlackMatrix is called %v times, expect 0", n)
+	}
+
+	// the black matrix is created once before the dispatch for the 2D readers
+	binarizer = &testCountingBinarizer{Binarizer: gozxing.NewHybridBinarizer(source)}
+	image, _ = gozxing.NewBinaryBitmap(binarizer)
+	if _, e := reader.DecodeAll(image, nil); e != nil {
+		t.Fatalf("DecodeAll returns error, %v", e)
+	}
+	if n := atomic.LoadInt32(&binarizer.matrixCalls); n != 1 {
+		t.Fatalf("GetBlackMatrix is called %v times, expect 1", n)
+	}
+}
+
+func TestConcurrentMultiFormatReader_Concurrent(t *testing.T) {
+	image := newTestQRAndCode128(t)
+	reader := NewConcurrentMultiFormatReader(2)
+
+	const n = 4
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			results, e := reader.DecodeAll(image, nil)
+			if e != nil {
+				t.Errorf("DecodeAll returns error, %v", e)
+				return
+			}
+			if len(results) != 2 {
+				t.Errorf("DecodeAll results = %v, expect 2 results", results)
+			}
+		}()
+	}
+	wg.Wait()
+}
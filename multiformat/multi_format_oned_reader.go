@@ -0,0 +1,22 @@
+package multiformat
+
+import (
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/oned/rss"
+)
+
+// NewMultiFormatOneDReader Creates a reader that can read all the 1D formats including RSS-14.
+// Each row of the image is fetched once and passed to all the row decoders
+// for the POSSIBLE_FORMATS hint, or all of them if the hint is not given.
+//
+func NewMultiFormatOneDReader(hints map[gozxing.DecodeHintType]interface{}) gozxing.Reader {
+	possibleFormats, _ := hints[gozxing.DecodeHintType_POSSIBLE_FORMATS].([]gozxing.BarcodeFormat)
+	formats := gozxing.BarcodeFormats(possibleFormats)
+
+	var rowDecoders []oned.RowDecoder
+	if len(formats) == 0 || formats.Contains(gozxing.BarcodeFormat_RSS_14) {
+		rowDecoders = append(rowDecoders, rss.NewRSS14Reader().(oned.RowDecoder))
+	}
+	return oned.NewMultiFormatOneDReaderWithRowDecoders(hints, rowDecoders...)
+}
@@ -0,0 +1,33 @@
+package multiformat
+
+import (
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/testutil"
+)
+
+func TestNewMultiFormatOneDReader(t *testing.T) {
+	file := "../oned/rss/testdata/1_1.png"
+	metadata := map[gozxing.ResultMetadataType]interface{}{
+		gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER: "]e0",
+	}
+
+	rss14 := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_RSS_14},
+	}
+	for _, h := range []map[gozxing.DecodeHintType]interface{}{nil, rss14} {
+		reader := NewMultiFormatOneDReader(h)
+		testutil.TestFile(t, reader, file, "04412345678909", gozxing.BarcodeFormat_RSS_14, h, metadata)
+	}
+
+	// RSS-14 is not read without the hint for it
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_CODE_128},
+	}
+	reader := NewMultiFormatOneDReader(hints)
+	img := testutil.NewBinaryBitmapFromFile(file)
+	if _, e := reader.Decode(img, hints); e == nil {
+		t.Fatalf("Decode must be error")
+	}
+}
@@ -0,0 +1,217 @@
+// Package multiformat provides the readers and the writer for all the barcode formats of gozxing.
+package multiformat
+
+import (
+	"context"
+	"sync"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/aztec"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// MultiFormatReader is a convenience class and the main entry point into the library for most uses.
+// By default it attempts to decode all barcode formats that the library supports. Optionally, you
+// can provide a hints object to request different behavior, for example only decoding QR codes.
+//
+// MultiFormatReader is safe for concurrent use by multiple goroutines.
+// Each call decodes with the hints and the readers at the time it is called.
+type MultiFormatReader struct {
+	mutex   sync.Mutex // guards the hints and the readers
+	hints   map[gozxing.DecodeHintType]interface{}
+	readers []gozxing.Reader
+}
+
+var _ gozxing.ReaderWithContext = &MultiFormatReader{}
+
+func NewMultiFormatReader() *MultiFormatReader {
+	return &MultiFormatReader{}
+}
+
+// DecodeWithoutHints This version of decode honors the intent of Reader.decode(BinaryBitmap) in that it
+// passes null as a hint to the decoders. However, that makes it inefficient to call repeatedly.
+// Use SetHints() followed by DecodeWithState() for continuous scan applications.
+//
+// @param image The pixel data to decode
+// @return The contents of the image
+// @throws NotFoundException Any errors which occurred
+//
+func (this *MultiFormatReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+	return this.DecodeContext(context.Background(), image, nil)
+}
+
+// Decode Decode an image using the hints provided. Does not honor existing state.
+//
+// @param image The pixel data to decode
+// @param hints The hints to use, clearing the previous state.
+// @return The contents of the image
+// @throws NotFoundException Any errors which occurred
+//
+func (this *MultiFormatReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return this.DecodeContext(context.Background(), image, hints)
+}
+
+// DecodeContext Decode an image using the hints provided as Decode,
+// and stops trying the readers when the context is done.
+//
+// @param ctx The context to stop decoding
+// @param image The pixel data to decode
+// @param hints The hints to use, clearing the previous state.
+// @return The contents of the image
+// @throws NotFoundException Any errors which occurred
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *MultiFormatReader) DecodeContext(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	readers := this.setHints(hints)
+	return this.decodeInternal(ctx, image, hints, readers)
+}
+
+// DecodeWithState Decode an image using the state set up by calling SetHints() previously.
+// Continuous scan clients will get a large speed increase by using this instead of Decode().
+//
+// @param image The pixel data to decode
+// @return The contents of the image
+// @throws NotFoundException Any errors which occurred
+//
+func (this *MultiFormatReader) DecodeWithState(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+	this.mutex.Lock()
+	hints, readers := this.hints, this.readers
+	this.mutex.Unlock()
+
+	// Make sure to set up the default state so we don't crash
+	if readers == nil {
+		readers = this.setHints(nil)
+	}
+	return this.decodeInternal(context.Background(), image, hints, readers)
+}
+
+// SetHints This method adds state to the MultiFormatReader. By setting the hints once, subsequent calls
+// to DecodeWithState(image) can reuse the same set of readers without reallocating memory. This
+// is important for performance in continuous scan clients.
+//
+// @param hints The set of hints to use for subsequent calls to decode(image)
+//
+func (this *MultiFormatReader) SetHints(hints map[gozxing.DecodeHintType]interface{}) {
+	this.setHints(hints)
+}
+
+// setHints sets the hints and the readers created for them, and returns the readers.
+func (this *MultiFormatReader) setHints(hints map[gozxing.DecodeHintType]interface{}) []gozxing.Reader {
+	readers := multiFormatReader_createReaders(hints)
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.hints = hints
+	this.readers = readers
+	return readers
+}
+
+// multiFormatReader_createReaders creates the readers for the POSSIBLE_FORMATS hint, or all of them.
+// The 1D reader is put upfront in "normal" mode, and at the end in "try harder" mode.
+func multiFormatReader_createReaders(hints map[gozxing.DecodeHintType]interface{}) []gozxing.Reader {
+	_, tryHarder := hints[gozxing.DecodeHintType_TRY_HARDER]
+	formats, _ := hints[gozxing.DecodeHintType_POSSIBLE_FORMATS].([]gozxing.BarcodeFormat)
+	readers := make([]gozxing.Reader, 0)
+	if len(formats) > 0 {
+		possibleFormats := gozxing.BarcodeFormats(formats)
+		addOneDReader := false
+		for _, format := range oneDFormats {
+			if possibleFormats.Contains(format) {
+				addOneDReader = true
+				break
+			}
+		}
+		// Put 1D readers upfront in "normal" mode
+		if addOneDReader && !tryHarder {
+			readers = append(readers, NewMultiFormatOneDReader(hints))
+		}
+		if possibleFormats.Contains(gozxing.BarcodeFormat_QR_CODE) {
+			readers = append(readers, qrcode.NewQRCodeReader())
+		}
+		if possibleFormats.Contains(gozxing.BarcodeFormat_DATA_MATRIX) {
+			readers = append(readers, datamatrix.NewDataMatrixReader())
+		}
+		if possibleFormats.Contains(gozxing.BarcodeFormat_AZTEC) {
+			readers = append(readers, aztec.NewAztecReader())
+		}
+		// At end in "try harder" mode
+		if addOneDReader && tryHarder {
+			readers = append(readers, NewMultiFormatOneDReader(hints))
+		}
+	}
+	if len(readers) == 0 {
+		if !tryHarder {
+			readers = append(readers, NewMultiFormatOneDReader(hints))
+		}
+		readers = append(readers,
+			qrcode.NewQRCodeReader(),
+			datamatrix.NewDataMatrixReader(),
+			aztec.NewAztecReader())
+		if tryHarder {
+			readers = append(readers, NewMultiFormatOneDReader(hints))
+		}
+	}
+	return readers
+}
+
+func (this *MultiFormatReader) Reset() {
+	this.mutex.Lock()
+	readers := this.readers
+	this.mutex.Unlock()
+	for _, reader := range readers {
+		reader.Reset()
+	}
+}
+
+// decodeInternal tries all the readers on the image, and then on the inverted image
+// if the ALSO_INVERTED hint is set. It stops when the context is done.
+func (this *MultiFormatReader) decodeInternal(ctx context.Context, image *gozxing.BinaryBitmap,
+	hints map[gozxing.DecodeHintType]interface{}, readers []gozxing.Reader) (*gozxing.Result, error) {
+	readerHints := multiFormatReader_removeAlsoInverted(hints)
+	decode := func(image *gozxing.BinaryBitmap, _ map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+		for _, reader := range readers {
+			result, e := gozxing.Reader_DecodeContext(ctx, reader, image, readerHints)
+			if e == nil {
+				return result, nil
+			}
+			if err := gozxing.Reader_CheckContext(ctx); err != nil {
+				return nil, err
+			}
+			if _, ok := e.(gozxing.ReaderException); !ok {
+				return nil, e
+			}
+			// continue
+		}
+		return nil, gozxing.NewNotFoundException()
+	}
+	return gozxing.Reader_DecodeAlsoInverted(image, hints, decode)
+}
+
+// multiFormatReader_removeAlsoInverted returns the hints without ALSO_INVERTED.
+// The inverted image is tried after all the readers failed with the original image,
+// so each reader is called without the ALSO_INVERTED hint.
+func multiFormatReader_removeAlsoInverted(hints map[gozxing.DecodeHintType]interface{}) map[gozxing.DecodeHintType]interface{} {
+	if _, ok := hints[gozxing.DecodeHintType_ALSO_INVERTED]; !ok {
+		return hints
+	}
+	newHints := make(map[gozxing.DecodeHintType]interface{}, len(hints))
+	for k, v := range hints {
+		if k != gozxing.DecodeHintType_ALSO_INVERTED {
+			newHints[k] = v
+		}
+	}
+	return newHints
+}
+
+var oneDFormats = []gozxing.BarcodeFormat{
+	gozxing.BarcodeFormat_UPC_A,
+	gozxing.BarcodeFormat_UPC_E,
+	gozxing.BarcodeFormat_EAN_13,
+	gozxing.BarcodeFormat_EAN_8,
+	gozxing.BarcodeFormat_CODABAR,
+	gozxing.BarcodeFormat_CODE_39,
+	gozxing.BarcodeFormat_CODE_93,
+	gozxing.BarcodeFormat_CODE_128,
+	gozxing.BarcodeFormat_ITF,
+	gozxing.BarcodeFormat_RSS_14,
+}
@@ -0,0 +1,280 @@
+package multiformat
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	errors "golang.org/x/xerrors"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/makiuchi-d/gozxing/testutil"
+)
+
+// newTestImage encodes the contents, and adds the quiet zone
+func newTestImage(t testing.TB, writer gozxing.Writer, contents string, format gozxing.BarcodeFormat, width, height int) *gozxing.BitMatrix {
+	t.Helper()
+	matrix, e := writer.EncodeWithoutHint(contents, format, width, height)
+	if e != nil {
+		t.Fatalf("Encode(%v, %v) returns error, %v", contents, format, e)
+	}
+	img, _ := gozxing.NewBitMatrix(matrix.GetWidth()+20, matrix.GetHeight()+20)
+	for y := 0; y < matrix.GetHeight(); y++ {
+		for x := 0; x < matrix.GetWidth(); x++ {
+			if matrix.Get(x, y) {
+				img.Set(x+10, y+10)
+			}
+		}
+	}
+	return img
+}
+
+func newTestBinaryBitmap(t testing.TB, writer gozxing.Writer, contents string, format gozxing.BarcodeFormat, width, height int) *gozxing.BinaryBitmap {
+	t.Helper()
+	return testutil.NewBinaryBitmapFromBitMatrix(newTestImage(t, writer, contents, format, width, height))
+}
+
+func testDecode(t testing.TB, reader *MultiFormatReader, image *gozxing.BinaryBitmap,
+	hints map[gozxing.DecodeHintType]interface{}, text string, format gozxing.BarcodeFormat) *gozxing.Result {
+	t.Helper()
+	result, e := reader.Decode(image, hints)
+	if e != nil {
+		t.Fatalf("Decode(%v) returns error, %v", format, e)
+	}
+	if txt := result.GetText(); txt != text {
+		t.Fatalf("Decode(%v) text = \"%v\", expect \"%v\"", format, txt, text)
+	}
+	if f := result.GetBarcodeFormat(); f != format {
+		t.Fatalf("Decode(%v) format = %v, expect %v", format, f, format)
+	}
+	return result
+}
+
+func TestMultiFormatReader_Decode(t *testing.T) {
+	qr := newTestBinaryBitmap(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	dm := newTestBinaryBitmap(t, datamatrix.NewDataMatrixWriter(), "Data Matrix", gozxing.BarcodeFormat_DATA_MATRIX, 100, 100)
+	code128 := newTestBinaryBitmap(t, oned.NewCode128Writer(), "Code 128", gozxing.BarcodeFormat_CODE_128, 200, 50)
+	ean13 := newTestBinaryBitmap(t, oned.NewEAN13Writer(), "4901234567894", gozxing.BarcodeFormat_EAN_13, 200, 50)
+	az := testutil.NewBinaryBitmapFromFile("testdata/aztec.png")
+
+	reader := NewMultiFormatReader()
+	reader.Reset()
+
+	tryHarder := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	}
+	for _, hints := range []map[gozxing.DecodeHintType]interface{}{nil, tryHarder} {
+		testDecode(t, reader, qr, hints, "QR Code", gozxing.BarcodeFormat_QR_CODE)
+		testDecode(t, reader, dm, hints, "Data Matrix", gozxing.BarcodeFormat_DATA_MATRIX)
+		testDecode(t, reader, code128, hints, "Code 128", gozxing.BarcodeFormat_CODE_128)
+		testDecode(t, reader, ean13, hints, "4901234567894", gozxing.BarcodeFormat_EAN_13)
+		testDecode(t, reader, az, hints, "Code 2D!", gozxing.BarcodeFormat_AZTEC)
+	}
+
+	result, e := reader.DecodeWithoutHints(qr)
+	if e != nil {
+		t.Fatalf("DecodeWithoutHints returns error, %v", e)
+	}
+	if txt := result.GetText(); txt != "QR Code" {
+		t.Fatalf("DecodeWithoutHints text = \"%v\", expect \"QR Code\"", txt)
+	}
+
+	img, _ := gozxing.NewBitMatrix(50, 50)
+	if _, e := reader.Decode(testutil.NewBinaryBitmapFromBitMatrix(img), nil); e == nil {
+		t.Fatalf("Decode must be error")
+	} else if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("Decode must be NotFoundException, %T", e)
+	}
+}
+
+func TestMultiFormatReader_DecodePossibleFormats(t *testing.T) {
+	qr := newTestBinaryBitmap(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	code128 := newTestBinaryBitmap(t, oned.NewCode128Writer(), "Code 128", gozxing.BarcodeFormat_CODE_128, 200, 50)
+	ean13 := newTestBinaryBitmap(t, oned.NewEAN13Writer(), "0123456789012", gozxing.BarcodeFormat_EAN_13, 200, 50)
+
+	reader := NewMultiFormatReader()
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_QR_CODE},
+	}
+	testDecode(t, reader, qr, hints, "QR Code", gozxing.BarcodeFormat_QR_CODE)
+	if _, e := reader.Decode(code128, hints); e == nil {
+		t.Fatalf("Decode(CODE_128) with POSSIBLE_FORMATS QR_CODE must be error")
+	}
+
+	hints = map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_CODE_128},
+	}
+	testDecode(t, reader, code128, hints, "Code 128", gozxing.BarcodeFormat_CODE_128)
+	if _, e := reader.Decode(qr, hints); e == nil {
+		t.Fatalf("Decode(QR_CODE) with POSSIBLE_FORMATS CODE_128 must be error")
+	}
+	if _, e := reader.Decode(ean13, hints); e == nil {
+		t.Fatalf("Decode(EAN_13) with POSSIBLE_FORMATS CODE_128 must be error")
+	}
+
+	hints = map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_UPC_A},
+		gozxing.DecodeHintType_TRY_HARDER:       true,
+	}
+	testDecode(t, reader, ean13, hints, "123456789012", gozxing.BarcodeFormat_UPC_A)
+
+	// formats which are not supported fall back to all the readers
+	hints = map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_MAXICODE},
+	}
+	testDecode(t, reader, qr, hints, "QR Code", gozxing.BarcodeFormat_QR_CODE)
+}
+
+func TestMultiFormatReader_DecodeWithState(t *testing.T) {
+	qr := newTestBinaryBitmap(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	dm := newTestBinaryBitmap(t, datamatrix.NewDataMatrixWriter(), "Data Matrix", gozxing.BarcodeFormat_DATA_MATRIX, 100, 100)
+
+	reader := NewMultiFormatReader()
+	result, e := reader.DecodeWithState(qr)
+	if e != nil {
+		t.Fatalf("DecodeWithState returns error, %v", e)
+	}
+	if txt := result.GetText(); txt != "QR Code" {
+		t.Fatalf("DecodeWithState text = \"%v\", expect \"QR Code\"", txt)
+	}
+
+	reader.SetHints(map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_DATA_MATRIX},
+	})
+	readers := reader.readers
+	if n := len(readers); n != 1 {
+		t.Fatalf("len(readers) = %v, expect 1", n)
+	}
+	for i := 0; i < 2; i++ {
+		result, e = reader.DecodeWithState(dm)
+		if e != nil {
+			t.Fatalf("DecodeWithState returns error, %v", e)
+		}
+		if txt := result.GetText(); txt != "Data Matrix" {
+			t.Fatalf("DecodeWithState text = \"%v\", expect \"Data Matrix\"", txt)
+		}
+		if _, e := reader.DecodeWithState(qr); e == nil {
+			t.Fatalf("DecodeWithState(QR_CODE) must be error")
+		}
+		if reader.readers[0] != readers[0] {
+			t.Fatalf("DecodeWithState must reuse the readers")
+		}
+	}
+}
+
+func TestMultiFormatReader_DecodeAlsoInverted(t *testing.T) {
+	qr := newTestBinaryBitmap(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	inverted, _ := qr.Invert()
+
+	reader := NewMultiFormatReader()
+	if _, e := reader.Decode(inverted, nil); e == nil {
+		t.Fatalf("Decode(inverted) without ALSO_INVERTED must be error")
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	result := testDecode(t, reader, inverted, hints, "QR Code", gozxing.BarcodeFormat_QR_CODE)
+	if inv := result.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; inv != true {
+		t.Fatalf("Decode(inverted) INVERTED = %v, expect true", inv)
+	}
+
+	result = testDecode(t, reader, qr, hints, "QR Code", gozxing.BarcodeFormat_QR_CODE)
+	if inv, ok := result.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; ok {
+		t.Fatalf("Decode INVERTED = %v, expect none", inv)
+	}
+	if _, ok := reader.hints[gozxing.DecodeHintType_ALSO_INVERTED]; !ok {
+		t.Fatalf("hints must not be modified")
+	}
+}
+
+func TestMultiFormatReader_DecodeContext(t *testing.T) {
+	qr := newTestBinaryBitmap(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	reader := NewMultiFormatReader()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, e := reader.DecodeContext(ctx, qr, nil)
+	if _, ok := e.(gozxing.NotFoundException); ok {
+		t.Fatalf("DecodeContext must not be NotFoundException, %v", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DecodeContext must wrap context.Canceled, %v", e)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER:    true,
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	_, e = reader.DecodeContext(ctx, qr, hints)
+	if !errors.Is(e, context.DeadlineExceeded) {
+		t.Fatalf("DecodeContext must wrap context.DeadlineExceeded, %v", e)
+	}
+
+	result, e := reader.DecodeContext(context.Background(), qr, hints)
+	if e != nil {
+		t.Fatalf("DecodeContext returns error, %v", e)
+	}
+	if txt := result.GetText(); txt != "QR Code" {
+		t.Fatalf("DecodeContext text = %v, expect QR Code", txt)
+	}
+}
+
+func TestMultiFormatReader_Concurrent(t *testing.T) {
+	qr := newTestBinaryBitmap(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 100, 100)
+	dm := newTestBinaryBitmap(t, datamatrix.NewDataMatrixWriter(), "Data Matrix", gozxing.BarcodeFormat_DATA_MATRIX, 100, 100)
+	code128 := newTestBinaryBitmap(t, oned.NewCode128Writer(), "Code 128", gozxing.BarcodeFormat_CODE_128, 200, 50)
+
+	reader := NewMultiFormatReader()
+	tryHarder := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	}
+
+	tests := []struct {
+		image *gozxing.BinaryBitmap
+		text  string
+	}{
+		{qr, "QR Code"},
+		{dm, "Data Matrix"},
+		{code128, "Code 128"},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		for _, test := range tests {
+			wg.Add(2)
+			go func(image *gozxing.BinaryBitmap, text string) {
+				defer wg.Done()
+				result, e := reader.Decode(image, tryHarder)
+				if e != nil {
+					t.Errorf("Decode(%v) returns error, %v", text, e)
+					return
+				}
+				if txt := result.GetText(); txt != text {
+					t.Errorf("Decode text = \"%v\", expect \"%v\"", txt, text)
+				}
+			}(test.image, test.text)
+			go func(image *gozxing.BinaryBitmap, text string) {
+				defer wg.Done()
+				// the state is replaced by the other goroutines, which use all the readers
+				reader.SetHints(nil)
+				reader.Reset()
+				result, e := reader.DecodeWithState(image)
+				if e != nil {
+					t.Errorf("DecodeWithState(%v) returns error, %v", text, e)
+					return
+				}
+				if txt := result.GetText(); txt != text {
+					t.Errorf("DecodeWithState text = \"%v\", expect \"%v\"", txt, text)
+				}
+			}(test.image, test.text)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,149 @@
+package multiformat
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// MultiFormatWriter This is a factory class which finds the appropriate Writer subclass for
+// the BarcodeFormat requested and encodes the barcode with the supplied contents.
+//
+// Writers for the formats gozxing does not ship can be added by MultiFormatWriter_RegisterWriter.
+type MultiFormatWriter struct{}
+
+var _ gozxing.Writer = &MultiFormatWriter{}
+
+func NewMultiFormatWriter() *MultiFormatWriter {
+	return &MultiFormatWriter{}
+}
+
+type multiFormatWriterEntry struct {
+	newWriter func() gozxing.Writer
+	hints     []gozxing.EncodeHintType // applicable hints, or nil to accept any hints
+}
+
+var (
+	multiFormatWriterMutex   sync.RWMutex
+	multiFormatWriterEntries = map[gozxing.BarcodeFormat]*multiFormatWriterEntry{}
+)
+
+func init() {
+	oneDHints := []gozxing.EncodeHintType{
+		gozxing.EncodeHintType_MARGIN,
+	}
+	code128Hints := []gozxing.EncodeHintType{
+		gozxing.EncodeHintType_MARGIN,
+		gozxing.EncodeHintType_FORCE_CODE_SET,
+	}
+	qrHints := []gozxing.EncodeHintType{
+		gozxing.EncodeHintType_ERROR_CORRECTION,
+		gozxing.EncodeHintType_CHARACTER_SET,
+		gozxing.EncodeHintType_MARGIN,
+		gozxing.EncodeHintType_QR_VERSION,
+		gozxing.EncodeHintType_QR_MASK_PATTERN,
+		gozxing.EncodeHintType_QR_COMPACT,
+		gozxing.EncodeHintType_GS1_FORMAT,
+		gozxing.EncodeHintType_QR_APPLICATION_INDICATOR,
+	}
+	dataMatrixHints := []gozxing.EncodeHintType{
+		gozxing.EncodeHintType_CHARACTER_SET,
+		gozxing.EncodeHintType_DATA_MATRIX_SHAPE,
+		gozxing.EncodeHintType_MIN_SIZE,
+		gozxing.EncodeHintType_MAX_SIZE,
+		gozxing.EncodeHintType_GS1_FORMAT,
+		gozxing.EncodeHintType_DATA_MATRIX_COMPACT,
+		gozxing.EncodeHintType_DATA_MATRIX_FORCE_ENCODATION,
+	}
+
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_EAN_8, oned.NewEAN8Writer, oneDHints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_UPC_E, oned.NewUPCEWriter, oneDHints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_EAN_13, oned.NewEAN13Writer, oneDHints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_UPC_A, oned.NewUPCAWriter, oneDHints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_QR_CODE,
+		func() gozxing.Writer { return qrcode.NewQRCodeWriter() }, qrHints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_CODE_39, oned.NewCode39Writer, oneDHints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_CODE_93, oned.NewCode93Writer, oneDHints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_CODE_128, oned.NewCode128Writer, code128Hints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_ITF, oned.NewITFWriter, oneDHints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_CODABAR, oned.NewCodaBarWriter, oneDHints...)
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_DATA_MATRIX, datamatrix.NewDataMatrixWriter, dataMatrixHints...)
+}
+
+// MultiFormatWriter_RegisterWriter Registers the writer for the format.
+//
+// The registered writer replaces the writer for the format if already registered,
+// including the writers gozxing ships.
+//
+// @param format the barcode format which the writer encodes
+// @param newWriter the function to create the writer
+// @param hints the hints applicable to the format.
+// If no hints are given, any hints are passed to the writer without the validation.
+//
+func MultiFormatWriter_RegisterWriter(format gozxing.BarcodeFormat, newWriter func() gozxing.Writer, hints ...gozxing.EncodeHintType) {
+	multiFormatWriterMutex.Lock()
+	defer multiFormatWriterMutex.Unlock()
+	var applicableHints []gozxing.EncodeHintType
+	if len(hints) > 0 {
+		applicableHints = append(applicableHints, hints...)
+	}
+	multiFormatWriterEntries[format] = &multiFormatWriterEntry{newWriter, applicableHints}
+}
+
+// MultiFormatWriter_GetSupportedFormats returns the formats which have the registered writer
+func MultiFormatWriter_GetSupportedFormats() gozxing.BarcodeFormats {
+	multiFormatWriterMutex.RLock()
+	defer multiFormatWriterMutex.RUnlock()
+	formats := make(gozxing.BarcodeFormats, 0, len(multiFormatWriterEntries))
+	for format := range multiFormatWriterEntries {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+	return formats
+}
+
+func (this *MultiFormatWriter) EncodeWithoutHint(
+	contents string, format gozxing.BarcodeFormat, width, height int) (*gozxing.BitMatrix, error) {
+	return this.Encode(contents, format, width, height, nil)
+}
+
+// Encode Encodes the contents by the writer for the format.
+//
+// @throws WriterException if no writer is registered for the format,
+// the hints are not applicable to the format, or the writer fails to encode the contents
+//
+func (this *MultiFormatWriter) Encode(contents string, format gozxing.BarcodeFormat,
+	width, height int, hints map[gozxing.EncodeHintType]interface{}) (*gozxing.BitMatrix, error) {
+
+	multiFormatWriterMutex.RLock()
+	entry, ok := multiFormatWriterEntries[format]
+	multiFormatWriterMutex.RUnlock()
+	if !ok {
+		return nil, gozxing.NewWriterException(
+			"IllegalArgumentException: No encoder available for format %v", format)
+	}
+
+	if entry.hints != nil {
+		for hint := range hints {
+			if !encodeHintTypesContains(entry.hints, hint) {
+				return nil, gozxing.NewWriterException(
+					"IllegalArgumentException: %v hint is not applicable to format %v", hint, format)
+			}
+		}
+	}
+
+	return entry.newWriter().Encode(contents, format, width, height, hints)
+}
+
+func encodeHintTypesContains(hints []gozxing.EncodeHintType, hint gozxing.EncodeHintType) bool {
+	for _, h := range hints {
+		if h == hint {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+package multiformat
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix/encoder"
+)
+
+func TestMultiFormatWriter_Encode(t *testing.T) {
+	writer := NewMultiFormatWriter()
+	reader := NewMultiFormatReader()
+
+	tests := []struct {
+		contents string
+		format   gozxing.BarcodeFormat
+		decoded  string
+	}{
+		{"96385074", gozxing.BarcodeFormat_EAN_8, "96385074"},
+		{"01234565", gozxing.BarcodeFormat_UPC_E, "01234565"},
+		{"5901234123457", gozxing.BarcodeFormat_EAN_13, "5901234123457"},
+		{"123456789012", gozxing.BarcodeFormat_UPC_A, "123456789012"},
+		{"QR Code", gozxing.BarcodeFormat_QR_CODE, "QR Code"},
+		{"CODE39", gozxing.BarcodeFormat_CODE_39, "CODE39"},
+		{"CODE93", gozxing.BarcodeFormat_CODE_93, "CODE93"},
+		{"Code 128", gozxing.BarcodeFormat_CODE_128, "Code 128"},
+		{"30712345000010", gozxing.BarcodeFormat_ITF, "30712345000010"},
+		{"A123456B", gozxing.BarcodeFormat_CODABAR, "123456"},
+		{"Data Matrix", gozxing.BarcodeFormat_DATA_MATRIX, "Data Matrix"},
+	}
+	for _, test := range tests {
+		width, height := 200, 50
+		if test.format == gozxing.BarcodeFormat_QR_CODE || test.format == gozxing.BarcodeFormat_DATA_MATRIX {
+			width, height = 100, 100
+		}
+		bmp := newTestBinaryBitmap(t, writer, test.contents, test.format, width, height)
+		hints := map[gozxing.DecodeHintType]interface{}{
+			gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{test.format},
+		}
+		result, e := reader.Decode(bmp, hints)
+		if e != nil {
+			t.Fatalf("Decode(%v) returns error, %v", test.format, e)
+		}
+		if f := result.GetBarcodeFormat(); f != test.format {
+			t.Fatalf("Decode(%v) format = %v", test.format, f)
+		}
+		if txt := result.GetText(); txt != test.decoded {
+			t.Fatalf("Decode(%v) text = \"%v\", expect \"%v\"", test.format, txt, test.decoded)
+		}
+	}
+
+	if _, e := writer.EncodeWithoutHint("test", gozxing.BarcodeFormat_PDF_417, 0, 0); e == nil {
+		t.Fatalf("Encode(PDF_417) must be error")
+	}
+
+	if _, e := writer.EncodeWithoutHint("", gozxing.BarcodeFormat_QR_CODE, 0, 0); e == nil {
+		t.Fatalf("Encode(\"\") must be error")
+	}
+}
+
+func TestMultiFormatWriter_EncodeHints(t *testing.T) {
+	writer := NewMultiFormatWriter()
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_VERSION: 3,
+		gozxing.EncodeHintType_MARGIN:     0,
+	}
+	matrix, e := writer.Encode("QR Code", gozxing.BarcodeFormat_QR_CODE, 0, 0, hints)
+	if e != nil {
+		t.Fatalf("Encode(QR_CODE) returns error, %v", e)
+	}
+	if w := matrix.GetWidth(); w != 29 {
+		t.Fatalf("Encode(QR_CODE) width = %v, expect 29", w)
+	}
+
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_DATA_MATRIX_SHAPE: encoder.SymbolShapeHint_FORCE_SQUARE,
+	}
+	if _, e := writer.Encode("Data Matrix", gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints); e != nil {
+		t.Fatalf("Encode(DATA_MATRIX) returns error, %v", e)
+	}
+	if _, e := writer.Encode("QR Code", gozxing.BarcodeFormat_QR_CODE, 0, 0, hints); e == nil {
+		t.Fatalf("Encode(QR_CODE) with DATA_MATRIX_SHAPE must be error")
+	}
+
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_FORCE_CODE_SET: "B",
+	}
+	if _, e := writer.Encode("Code128", gozxing.BarcodeFormat_CODE_128, 0, 0, hints); e != nil {
+		t.Fatalf("Encode(CODE_128) returns error, %v", e)
+	}
+	if _, e := writer.Encode("CODE39", gozxing.BarcodeFormat_CODE_39, 0, 0, hints); e == nil {
+		t.Fatalf("Encode(CODE_39) with FORCE_CODE_SET must be error")
+	}
+}
+
+type testWriter struct {
+	hints map[gozxing.EncodeHintType]interface{}
+}
+
+func (this *testWriter) EncodeWithoutHint(contents string, format gozxing.BarcodeFormat, width, height int) (*gozxing.BitMatrix, error) {
+	return this.Encode(contents, format, width, height, nil)
+}
+
+func (this *testWriter) Encode(contents string, format gozxing.BarcodeFormat, width, height int, hints map[gozxing.EncodeHintType]interface{}) (*gozxing.BitMatrix, error) {
+	this.hints = hints
+	return gozxing.NewBitMatrix(width, height)
+}
+
+func TestMultiFormatWriter_RegisterWriter(t *testing.T) {
+	qrEntry := multiFormatWriterEntries[gozxing.BarcodeFormat_QR_CODE]
+	defer func() {
+		delete(multiFormatWriterEntries, gozxing.BarcodeFormat_AZTEC)
+		multiFormatWriterEntries[gozxing.BarcodeFormat_QR_CODE] = qrEntry
+	}()
+
+	if MultiFormatWriter_GetSupportedFormats().Contains(gozxing.BarcodeFormat_AZTEC) {
+		t.Fatalf("supported formats must not contain AZTEC")
+	}
+
+	writer := NewMultiFormatWriter()
+	tw := &testWriter{}
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_AZTEC, func() gozxing.Writer { return tw },
+		gozxing.EncodeHintType_AZTEC_LAYERS)
+
+	if !MultiFormatWriter_GetSupportedFormats().Contains(gozxing.BarcodeFormat_AZTEC) {
+		t.Fatalf("supported formats must contain AZTEC")
+	}
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_AZTEC_LAYERS: 3,
+	}
+	matrix, e := writer.Encode("aztec", gozxing.BarcodeFormat_AZTEC, 10, 20, hints)
+	if e != nil {
+		t.Fatalf("Encode(AZTEC) returns error, %v", e)
+	}
+	if w, h := matrix.GetWidth(), matrix.GetHeight(); w != 10 || h != 20 {
+		t.Fatalf("Encode(AZTEC) size = %vx%v, expect 10x20", w, h)
+	}
+	if l := tw.hints[gozxing.EncodeHintType_AZTEC_LAYERS]; l != 3 {
+		t.Fatalf("AZTEC_LAYERS hint = %v, expect 3", l)
+	}
+
+	hints[gozxing.EncodeHintType_MARGIN] = 1
+	if _, e := writer.Encode("aztec", gozxing.BarcodeFormat_AZTEC, 10, 20, hints); e == nil {
+		t.Fatalf("Encode(AZTEC) with MARGIN must be error")
+	}
+
+	// replace the writer without the hint validation
+	MultiFormatWriter_RegisterWriter(gozxing.BarcodeFormat_QR_CODE, func() gozxing.Writer { return tw })
+	if _, e := writer.Encode("qrcode", gozxing.BarcodeFormat_QR_CODE, 5, 5, hints); e != nil {
+		t.Fatalf("Encode(QR_CODE) returns error, %v", e)
+	}
+	if m := tw.hints[gozxing.EncodeHintType_MARGIN]; m != 1 {
+		t.Fatalf("MARGIN hint = %v, expect 1", m)
+	}
+}
+
+func TestMultiFormatWriter_GetSupportedFormats(t *testing.T) {
+	formats := MultiFormatWriter_GetSupportedFormats()
+	expects := gozxing.BarcodeFormats{
+		gozxing.BarcodeFormat_CODABAR,
+		gozxing.BarcodeFormat_CODE_39,
+		gozxing.BarcodeFormat_CODE_93,
+		gozxing.BarcodeFormat_CODE_128,
+		gozxing.BarcodeFormat_DATA_MATRIX,
+		gozxing.BarcodeFormat_EAN_8,
+		gozxing.BarcodeFormat_EAN_13,
+		gozxing.BarcodeFormat_ITF,
+		gozxing.BarcodeFormat_QR_CODE,
+		gozxing.BarcodeFormat_UPC_A,
+		gozxing.BarcodeFormat_UPC_E,
+	}
+	if len(formats) != len(expects) {
+		t.Fatalf("supported formats = %v, expect %v", formats, expects)
+	}
+	for i := range formats {
+		if formats[i] != expects[i] {
+			t.Fatalf("supported formats = %v, expect %v", formats, expects)
+		}
+	}
+}
+
+func TestMultiFormatWriter_Concurrent(t *testing.T) {
+	writer := NewMultiFormatWriter()
+
+	tests := []struct {
+		contents string
+		format   gozxing.BarcodeFormat
+	}{
+		{"5901234123457", gozxing.BarcodeFormat_EAN_13},
+		{"QR Code", gozxing.BarcodeFormat_QR_CODE},
+		{"Code 128", gozxing.BarcodeFormat_CODE_128},
+		{"Data Matrix", gozxing.BarcodeFormat_DATA_MATRIX},
+	}
+	expects := make([]*gozxing.BitMatrix, len(tests))
+	for i, test := range tests {
+		var e error
+		expects[i], e = writer.EncodeWithoutHint(test.contents, test.format, 100, 100)
+		if e != nil {
+			t.Fatalf("Encode(%v) returns error, %v", test.format, e)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		for j, test := range tests {
+			wg.Add(1)
+			go func(j int, contents string, format gozxing.BarcodeFormat) {
+				defer wg.Done()
+				matrix, e := writer.EncodeWithoutHint(contents, format, 100, 100)
+				if e != nil {
+					t.Errorf("Encode(%v) returns error, %v", format, e)
+					return
+				}
+				if matrix.String() != expects[j].String() {
+					t.Errorf("Encode(%v) result differs:\n%v\nexpect:\n%v", format, matrix, expects[j])
+				}
+			}(j, test.contents, test.format)
+		}
+	}
+	wg.Wait()
+}
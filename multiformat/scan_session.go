@@ -0,0 +1,255 @@
+package multiformat
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/multi"
+)
+
+const (
+	// scanSession_FULL_SCAN_INTERVAL is the max number of the frames
+	// decoded only around the tracked barcodes, before the whole frame is scanned for new ones.
+	scanSession_FULL_SCAN_INTERVAL = 10
+
+	// scanSession_MIN_MARGIN is the min margin in pixels around the tracked barcode to crop
+	scanSession_MIN_MARGIN = 16
+)
+
+type ScanEventType int
+
+const (
+	// ScanEventType_NEW_BARCODE a barcode is found, which is not tracked yet
+	ScanEventType_NEW_BARCODE = ScanEventType(iota)
+
+	// ScanEventType_BARCODE_LOST a tracked barcode is not found for the window
+	ScanEventType_BARCODE_LOST
+)
+
+func (t ScanEventType) String() string {
+	switch t {
+	case ScanEventType_NEW_BARCODE:
+		return "NEW_BARCODE"
+	case ScanEventType_BARCODE_LOST:
+		return "BARCODE_LOST"
+	}
+	return ""
+}
+
+// ScanEvent is the event emitted by ScanSession.
+type ScanEvent struct {
+	Type   ScanEventType
+	Result *gozxing.Result // the last result of the barcode, with the points in the frame
+}
+
+// scanTrack is a barcode tracked by ScanSession
+type scanTrack struct {
+	result   *gozxing.Result
+	lastSeen time.Time
+	found    bool // found in the current frame
+}
+
+// scanSessionReader is the Reader which decodes with the readers set up by MultiFormatReader.SetHints,
+// so that the readers are not created again for every region cropped by GenericMultipleBarcodeReader.
+type scanSessionReader struct {
+	reader *MultiFormatReader
+}
+
+func (this scanSessionReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+	return this.reader.DecodeWithState(image)
+}
+
+func (this scanSessionReader) Decode(image *gozxing.BinaryBitmap, _ map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return this.reader.DecodeWithState(image)
+}
+
+func (this scanSessionReader) Reset() {
+	this.reader.Reset()
+}
+
+// ScanSession decodes the successive frames of a video stream, such as the PlanarYUVLuminanceSource
+// from a camera, and tracks the barcodes among the frames.
+//
+// The regions of the tracked barcodes are decoded first, and the whole frame is scanned
+// only when any of them is missed, or once in a while to find new barcodes.
+// A barcode is reported once when it is found, and reported again when it is lost,
+// that is, not found for the window. So the barcode missed in some frames is not duplicated.
+//
+// ScanSession is safe for concurrent use, but the frames must be given in the order of the time.
+type ScanSession struct {
+	hints  map[gozxing.DecodeHintType]interface{}
+	window time.Duration
+
+	mutex               sync.Mutex // guards the following state
+	reader              *MultiFormatReader
+	tracks              []*scanTrack
+	framesSinceFullScan int
+}
+
+// NewScanSession creates a ScanSession.
+//
+// @param hints the hints to decode the frames
+// @param window the duration to keep tracking the barcode after it is found last
+//
+func NewScanSession(hints map[gozxing.DecodeHintType]interface{}, window time.Duration) *ScanSession {
+	reader := NewMultiFormatReader()
+	reader.SetHints(hints)
+	return &ScanSession{
+		hints:  hints,
+		window: window,
+		reader: reader,
+	}
+}
+
+// ProcessFrame Decodes the frame, and updates the tracked barcodes.
+//
+// @param frame the frame to decode
+// @param timestamp the time of the frame
+// @return the events of the barcodes found or lost in this frame
+// @throws ReaderException if the frame cannot be binarized
+//
+func (this *ScanSession) ProcessFrame(frame gozxing.LuminanceSource, timestamp time.Time) ([]*ScanEvent, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, track := range this.tracks {
+		track.found = false
+	}
+
+	missed := len(this.tracks) == 0
+	if frame.IsCropSupported() {
+		for _, track := range this.tracks {
+			if result, ok := this.decodeRegion(frame, track.result); ok {
+				track.result = result
+				track.lastSeen = timestamp
+				track.found = true
+			} else {
+				missed = true
+			}
+		}
+	} else {
+		missed = true
+	}
+
+	events := make([]*ScanEvent, 0)
+	this.framesSinceFullScan++
+	if missed || this.framesSinceFullScan >= scanSession_FULL_SCAN_INTERVAL {
+		this.framesSinceFullScan = 0
+		image, e := gozxing.NewBinaryBitmap(gozxing.NewHybridBinarizer(frame))
+		if e != nil {
+			return nil, gozxing.WrapReaderException(e)
+		}
+		results, e := multi.NewGenericMultipleBarcodeReader(scanSessionReader{this.reader}).DecodeMultiple(image, this.hints)
+		if e != nil {
+			if _, ok := e.(gozxing.ReaderException); !ok {
+				return nil, e
+			}
+		}
+		for _, result := range results {
+			if track := this.findTrack(result); track != nil {
+				if !track.found {
+					track.result = result
+					track.lastSeen = timestamp
+					track.found = true
+				}
+				continue
+			}
+			this.tracks = append(this.tracks, &scanTrack{result, timestamp, true})
+			events = append(events, &ScanEvent{ScanEventType_NEW_BARCODE, result})
+		}
+	}
+
+	tracks := this.tracks[:0]
+	for _, track := range this.tracks {
+		if timestamp.Sub(track.lastSeen) > this.window {
+			events = append(events, &ScanEvent{ScanEventType_BARCODE_LOST, track.result})
+			continue
+		}
+		tracks = append(tracks, track)
+	}
+	this.tracks = tracks
+
+	return events, nil
+}
+
+// Reset Forgets all the tracked barcodes without the events.
+func (this *ScanSession) Reset() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.tracks = nil
+	this.framesSinceFullScan = 0
+	this.reader.Reset()
+}
+
+// findTrack returns the track of the same barcode as the result, or nil
+func (this *ScanSession) findTrack(result *gozxing.Result) *scanTrack {
+	for _, track := range this.tracks {
+		if track.result.GetText() == result.GetText() &&
+			track.result.GetBarcodeFormat() == result.GetBarcodeFormat() {
+			return track
+		}
+	}
+	return nil
+}
+
+// decodeRegion Decodes the region around the last location of the barcode.
+//
+// @return the result with the points in the frame, and true if the same barcode is decoded
+//
+func (this *ScanSession) decodeRegion(frame gozxing.LuminanceSource, last *gozxing.Result) (*gozxing.Result, bool) {
+	left, top, width, height, ok := scanSession_regionOf(last.GetResultPoints(), frame.GetWidth(), frame.GetHeight())
+	if !ok {
+		return nil, false
+	}
+	source, e := frame.Crop(left, top, width, height)
+	if e != nil {
+		return nil, false
+	}
+	image, e := gozxing.NewBinaryBitmap(gozxing.NewHybridBinarizer(source))
+	if e != nil {
+		return nil, false
+	}
+	// the readers are set up with the same hints by the full scan as well
+	result, e := this.reader.DecodeWithState(image)
+	this.reader.Reset()
+	if e != nil {
+		return nil, false
+	}
+	if result.GetText() != last.GetText() || result.GetBarcodeFormat() != last.GetBarcodeFormat() {
+		return nil, false
+	}
+	return multi.TranslateResultPoints(result, left, top), true
+}
+
+// scanSession_regionOf returns the bounding box of the points with the margin, clipped to the frame.
+// The margin is the half of the longer side of the box, so that the moved barcode is still inside.
+func scanSession_regionOf(points []gozxing.ResultPoint, frameWidth, frameHeight int) (left, top, width, height int, ok bool) {
+	if len(points) == 0 {
+		return 0, 0, 0, 0, false
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		if p == nil {
+			continue
+		}
+		minX = math.Min(minX, p.GetX())
+		minY = math.Min(minY, p.GetY())
+		maxX = math.Max(maxX, p.GetX())
+		maxY = math.Max(maxY, p.GetY())
+	}
+	if minX > maxX {
+		return 0, 0, 0, 0, false
+	}
+	margin := math.Max(math.Max(maxX-minX, maxY-minY)/2, scanSession_MIN_MARGIN)
+	left = int(math.Max(minX-margin, 0))
+	top = int(math.Max(minY-margin, 0))
+	right := int(math.Min(maxX+margin, float64(frameWidth)))
+	bottom := int(math.Min(maxY+margin, float64(frameHeight)))
+	if right <= left || bottom <= top {
+		return 0, 0, 0, 0, false
+	}
+	return left, top, right - left, bottom - top, true
+}
@@ -0,0 +1,239 @@
+package multiformat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+const (
+	testFrameWidth  = 320
+	testFrameHeight = 240
+)
+
+type testFramePart struct {
+	image *gozxing.BitMatrix
+	x, y  int
+}
+
+// newTestFrame creates the Y plane of a frame with the images on the white background
+func newTestFrame(t testing.TB, parts ...testFramePart) gozxing.LuminanceSource {
+	t.Helper()
+	yuv := make([]byte, testFrameWidth*testFrameHeight*3/2)
+	for i := range yuv {
+		yuv[i] = 255
+	}
+	for _, p := range parts {
+		for y := 0; y < p.image.GetHeight(); y++ {
+			for x := 0; x < p.image.GetWidth(); x++ {
+				if p.image.Get(x, y) {
+					yuv[(p.y+y)*testFrameWidth+p.x+x] = 0
+				}
+			}
+		}
+	}
+	frame, e := gozxing.NewPlanarYUVLuminanceSource(
+		yuv, testFrameWidth, testFrameHeight, 0, 0, testFrameWidth, testFrameHeight, false)
+	if e != nil {
+		t.Fatalf("NewPlanarYUVLuminanceSource returns error, %v", e)
+	}
+	return frame
+}
+
+func testScanEvents(t testing.TB, events []*ScanEvent, e error, expects ...*ScanEvent) {
+	t.Helper()
+	if e != nil {
+		t.Fatalf("ProcessFrame returns error, %v", e)
+	}
+	if len(events) != len(expects) {
+		t.Fatalf("ProcessFrame events = %v, expect %v", events, expects)
+	}
+	for i, ev := range events {
+		expect := expects[i]
+		if ev.Type != expect.Type ||
+			ev.Result.GetText() != expect.Result.GetText() ||
+			ev.Result.GetBarcodeFormat() != expect.Result.GetBarcodeFormat() {
+			t.Fatalf("ProcessFrame events[%v] = %v %v, expect %v %v",
+				i, ev.Type, ev.Result, expect.Type, expect.Result)
+		}
+	}
+}
+
+func TestScanEventType_String(t *testing.T) {
+	tests := map[ScanEventType]string{
+		ScanEventType_NEW_BARCODE:  "NEW_BARCODE",
+		ScanEventType_BARCODE_LOST: "BARCODE_LOST",
+		ScanEventType(-1):          "",
+	}
+	for typ, expect := range tests {
+		if s := typ.String(); s != expect {
+			t.Fatalf("String() = \"%v\", expect \"%v\"", s, expect)
+		}
+	}
+}
+
+func TestScanSession_ProcessFrame(t *testing.T) {
+	qr := newTestImage(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 80, 80)
+	code128 := newTestImage(t, oned.NewCode128Writer(), "Code 128", gozxing.BarcodeFormat_CODE_128, 160, 40)
+	newQR := &ScanEvent{ScanEventType_NEW_BARCODE, gozxing.NewResult("QR Code", nil, nil, gozxing.BarcodeFormat_QR_CODE)}
+	lostQR := &ScanEvent{ScanEventType_BARCODE_LOST, newQR.Result}
+	newCode128 := &ScanEvent{ScanEventType_NEW_BARCODE, gozxing.NewResult("Code 128", nil, nil, gozxing.BarcodeFormat_CODE_128)}
+	lostCode128 := &ScanEvent{ScanEventType_BARCODE_LOST, newCode128.Result}
+
+	session := NewScanSession(nil, 500*time.Millisecond)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	frameInterval := 33 * time.Millisecond
+
+	events, e := session.ProcessFrame(newTestFrame(t, testFramePart{qr, 20, 20}), now)
+	testScanEvents(t, events, e, newQR)
+	x0 := session.tracks[0].result.GetResultPoints()[0].GetX()
+
+	// the moved barcode is tracked around the last location, without scanning the whole frame
+	now = now.Add(frameInterval)
+	events, e = session.ProcessFrame(newTestFrame(t, testFramePart{qr, 30, 25}), now)
+	testScanEvents(t, events, e)
+	if session.framesSinceFullScan != 1 {
+		t.Fatalf("framesSinceFullScan = %v, expect 1", session.framesSinceFullScan)
+	}
+	if x := session.tracks[0].result.GetResultPoints()[0].GetX(); x < x0+9 || x0+11 < x {
+		t.Fatalf("tracked point x = %v, expect %v", x, x0+10)
+	}
+
+	// the new barcode is found by the full scan once in a while
+	for i := 2; i < scanSession_FULL_SCAN_INTERVAL; i++ {
+		now = now.Add(frameInterval)
+		events, e = session.ProcessFrame(
+			newTestFrame(t, testFramePart{qr, 30, 25}, testFramePart{code128, 130, 170}), now)
+		testScanEvents(t, events, e)
+	}
+	now = now.Add(frameInterval)
+	events, e = session.ProcessFrame(
+		newTestFrame(t, testFramePart{qr, 30, 25}, testFramePart{code128, 130, 170}), now)
+	testScanEvents(t, events, e, newCode128)
+
+	// missed barcodes within the window are not reported again
+	now = now.Add(frameInterval)
+	events, e = session.ProcessFrame(newTestFrame(t), now)
+	testScanEvents(t, events, e)
+	now = now.Add(300 * time.Millisecond)
+	events, e = session.ProcessFrame(newTestFrame(t, testFramePart{qr, 30, 25}), now)
+	testScanEvents(t, events, e)
+
+	// lost after the window
+	now = now.Add(300 * time.Millisecond)
+	events, e = session.ProcessFrame(newTestFrame(t), now)
+	testScanEvents(t, events, e, lostCode128)
+	now = now.Add(300 * time.Millisecond)
+	events, e = session.ProcessFrame(newTestFrame(t), now)
+	testScanEvents(t, events, e, lostQR)
+	if len(session.tracks) != 0 {
+		t.Fatalf("tracks = %v, expect empty", session.tracks)
+	}
+
+	// found again after lost
+	now = now.Add(frameInterval)
+	events, e = session.ProcessFrame(newTestFrame(t, testFramePart{qr, 200, 100}), now)
+	testScanEvents(t, events, e, newQR)
+
+	// forgotten by Reset
+	session.Reset()
+	now = now.Add(frameInterval)
+	events, e = session.ProcessFrame(newTestFrame(t, testFramePart{qr, 200, 100}), now)
+	testScanEvents(t, events, e, newQR)
+}
+
+type testNoCropSource struct {
+	gozxing.LuminanceSource
+}
+
+func (this testNoCropSource) IsCropSupported() bool {
+	return false
+}
+
+func TestScanSession_ProcessFrameNoCrop(t *testing.T) {
+	qr := newTestImage(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 80, 80)
+	newQR := &ScanEvent{ScanEventType_NEW_BARCODE, gozxing.NewResult("QR Code", nil, nil, gozxing.BarcodeFormat_QR_CODE)}
+
+	session := NewScanSession(nil, time.Second)
+	now := time.Now()
+	frame := testNoCropSource{newTestFrame(t, testFramePart{qr, 20, 20})}
+
+	events, e := session.ProcessFrame(frame, now)
+	testScanEvents(t, events, e, newQR)
+
+	// the whole frame is scanned every time
+	events, e = session.ProcessFrame(frame, now.Add(time.Millisecond))
+	testScanEvents(t, events, e)
+	if session.framesSinceFullScan != 0 {
+		t.Fatalf("framesSinceFullScan = %v, expect 0", session.framesSinceFullScan)
+	}
+	if n := len(session.tracks); n != 1 {
+		t.Fatalf("len(tracks) = %v, expect 1", n)
+	}
+}
+
+func TestScanSession_ProcessFrameReusesReaders(t *testing.T) {
+	qr := newTestImage(t, qrcode.NewQRCodeWriter(), "QR Code", gozxing.BarcodeFormat_QR_CODE, 80, 80)
+	code128 := newTestImage(t, oned.NewCode128Writer(), "Code 128", gozxing.BarcodeFormat_CODE_128, 160, 40)
+
+	session := NewScanSession(nil, time.Second)
+	readers := session.reader.readers
+
+	frame := testNoCropSource{newTestFrame(t, testFramePart{qr, 20, 20}, testFramePart{code128, 140, 160})}
+	events, e := session.ProcessFrame(frame, time.Now())
+	if e != nil {
+		t.Fatalf("ProcessFrame returns error, %v", e)
+	}
+	if len(events) != 2 {
+		t.Fatalf("ProcessFrame events = %v, expect 2 events", events)
+	}
+
+	// the full scan decodes the cropped regions with the readers set up by NewScanSession
+	if len(session.reader.readers) != len(readers) {
+		t.Fatalf("readers = %v, expect %v", session.reader.readers, readers)
+	}
+	for i, r := range session.reader.readers {
+		if r != readers[i] {
+			t.Fatalf("readers[%v] must not be recreated", i)
+		}
+	}
+}
+
+func TestScanSession_regionOf(t *testing.T) {
+	p := gozxing.NewResultPoint
+
+	if _, _, _, _, ok := scanSession_regionOf(nil, 100, 100); ok {
+		t.Fatalf("regionOf(nil) must be false")
+	}
+	if _, _, _, _, ok := scanSession_regionOf([]gozxing.ResultPoint{nil}, 100, 100); ok {
+		t.Fatalf("regionOf([nil]) must be false")
+	}
+	if _, _, _, _, ok := scanSession_regionOf([]gozxing.ResultPoint{p(200, 200)}, 100, 100); ok {
+		t.Fatalf("regionOf(outside) must be false")
+	}
+
+	tests := []struct {
+		points                   []gozxing.ResultPoint
+		left, top, width, height int
+	}{
+		// margin is the half of the longer side
+		{[]gozxing.ResultPoint{p(40, 40), p(80, 40), p(40, 80)}, 20, 20, 80, 80},
+		// min margin
+		{[]gozxing.ResultPoint{p(50, 50), p(60, 50)}, 34, 34, 42, 32},
+		// clipped
+		{[]gozxing.ResultPoint{p(10, 10), p(90, 90), nil}, 0, 0, 100, 100},
+	}
+	for _, test := range tests {
+		left, top, width, height, ok := scanSession_regionOf(test.points, 100, 100)
+		if !ok {
+			t.Fatalf("regionOf(%v) must be ok", test.points)
+		}
+		if left != test.left || top != test.top || width != test.width || height != test.height {
+			t.Fatalf("regionOf(%v) = %v,%v,%v,%v, expect %v,%v,%v,%v", test.points,
+				left, top, width, height, test.left, test.top, test.width, test.height)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package oned
+
+import (
+	"github.com/makiuchi-d/gozxing"
+)
+
+type multiFormatOneDReader struct {
+	*OneDReader
+	readers []RowDecoder
+}
+
+// NewMultiFormatOneDReader Creates a reader that can read all the 1D formats in this package.
+// Each row of the image is fetched once and passed to all the row decoders
+// for the POSSIBLE_FORMATS hint, or all of them if the hint is not given.
+// If none of the possible formats is available, the reader finds nothing.
+//
+// RSS-14 is in the oned/rss package, use NewMultiFormatOneDReaderWithRowDecoders
+// or multiformat.NewMultiFormatOneDReader to read it as well.
+//
+func NewMultiFormatOneDReader(hints map[gozxing.DecodeHintType]interface{}) gozxing.Reader {
+	return NewMultiFormatOneDReaderWithRowDecoders(hints)
+}
+
+// NewMultiFormatOneDReaderWithRowDecoders Creates a reader that can read all the 1D formats in this package
+// as NewMultiFormatOneDReader, and the formats of the additional row decoders.
+//
+// @param hints the decode hints to choose the row decoders in this package
+// @param rowDecoders the row decoders for the formats which are not in this package, such as RSS-14.
+// They are tried after the decoders in this package, and the caller chooses them for the POSSIBLE_FORMATS hint.
+//
+func NewMultiFormatOneDReaderWithRowDecoders(hints map[gozxing.DecodeHintType]interface{}, rowDecoders ...RowDecoder) gozxing.Reader {
+	// @SuppressWarnings("unchecked")
+	possibleFormats, _ := hints[gozxing.DecodeHintType_POSSIBLE_FORMATS].([]gozxing.BarcodeFormat)
+	formats := gozxing.BarcodeFormats(possibleFormats)
+	_, useCode39CheckDigit := hints[gozxing.DecodeHintType_ASSUME_CODE_39_CHECK_DIGIT]
+
+	var readers []RowDecoder
+	if len(formats) > 0 {
+		if formats.Contains(gozxing.BarcodeFormat_EAN_13) ||
+			formats.Contains(gozxing.BarcodeFormat_UPC_A) ||
+			formats.Contains(gozxing.BarcodeFormat_EAN_8) ||
+			formats.Contains(gozxing.BarcodeFormat_UPC_E) {
+			readers = append(readers, NewMultiFormatUPCEANReader(hints).(RowDecoder))
+		}
+		if formats.Contains(gozxing.BarcodeFormat_CODE_39) {
+			readers = append(readers, NewCode39ReaderWithCheckDigitFlag(useCode39CheckDigit).(RowDecoder))
+		}
+		if formats.Contains(gozxing.BarcodeFormat_CODE_93) {
+			readers = append(readers, NewCode93Reader().(RowDecoder))
+		}
+		if formats.Contains(gozxing.BarcodeFormat_CODE_128) {
+			readers = append(readers, NewCode128Reader().(RowDecoder))
+		}
+		if formats.Contains(gozxing.BarcodeFormat_ITF) {
+			readers = append(readers, NewITFReader().(RowDecoder))
+		}
+		if formats.Contains(gozxing.BarcodeFormat_CODABAR) {
+			readers = append(readers, NewCodaBarReader().(RowDecoder))
+		}
+	} else {
+		readers = append(readers,
+			NewMultiFormatUPCEANReader(hints).(RowDecoder),
+			NewCode39ReaderWithCheckDigitFlag(useCode39CheckDigit).(RowDecoder),
+			NewCodaBarReader().(RowDecoder),
+			NewCode93Reader().(RowDecoder),
+			NewCode128Reader().(RowDecoder),
+			NewITFReader().(RowDecoder))
+	}
+	readers = append(readers, rowDecoders...)
+
+	this := &multiFormatOneDReader{
+		readers: readers,
+	}
+	this.OneDReader = NewOneDReader(this)
+	return this
+}
+
+func (this *multiFormatOneDReader) DecodeRow(rowNumber int, row *gozxing.BitArray, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	for _, reader := range this.readers {
+		result, e := reader.DecodeRow(rowNumber, row, hints)
+		if e == nil {
+			return result, nil
+		}
+		if _, ok := e.(gozxing.ReaderException); !ok {
+			return nil, e
+		}
+		// continue
+	}
+
+	return nil, gozxing.NewNotFoundException()
+}
+
+func (this *multiFormatOneDReader) Reset() {
+	for _, reader := range this.readers {
+		if r, ok := reader.(gozxing.Reader); ok {
+			r.Reset()
+		}
+	}
+}
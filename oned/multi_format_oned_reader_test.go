@@ -0,0 +1,213 @@
+package oned
+
+import (
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/testutil"
+)
+
+type testRowDecoder struct {
+	decoded bool
+	reset   bool
+}
+
+func (this *testRowDecoder) DecodeRow(rowNumber int, row *gozxing.BitArray, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	if this.decoded {
+		return gozxing.NewResult("test", nil, nil, gozxing.BarcodeFormat_RSS_EXPANDED), nil
+	}
+	return nil, gozxing.NewNotFoundException()
+}
+
+func (this *testRowDecoder) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+	return nil, gozxing.NewNotFoundException()
+}
+
+func (this *testRowDecoder) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return nil, gozxing.NewNotFoundException()
+}
+
+func (this *testRowDecoder) Reset() {
+	this.reset = true
+}
+
+func isUPCEAN(format gozxing.BarcodeFormat) bool {
+	switch format {
+	case gozxing.BarcodeFormat_EAN_13, gozxing.BarcodeFormat_EAN_8,
+		gozxing.BarcodeFormat_UPC_A, gozxing.BarcodeFormat_UPC_E:
+		return true
+	}
+	return false
+}
+
+func TestMultiFormatOneDReader(t *testing.T) {
+	reader := NewMultiFormatOneDReader(nil)
+
+	tests := []struct {
+		file   string
+		text   string
+		format gozxing.BarcodeFormat
+	}{
+		{"testdata/ean13/1.png", "8413000065504", gozxing.BarcodeFormat_EAN_13},
+		{"testdata/ean8/1.png", "48512343", gozxing.BarcodeFormat_EAN_8},
+		{"testdata/upce/1.png", "01234565", gozxing.BarcodeFormat_UPC_E},
+		{"testdata/code39/01.png", "165627", gozxing.BarcodeFormat_CODE_39},
+		{"testdata/code93/1.png", "1234567890", gozxing.BarcodeFormat_CODE_93},
+		{"testdata/code128/1.png", "168901", gozxing.BarcodeFormat_CODE_128},
+		{"testdata/itf/1.png", "30712345000010", gozxing.BarcodeFormat_ITF},
+		{"testdata/codabar/01.png", "1234567890", gozxing.BarcodeFormat_CODABAR},
+	}
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			img := testutil.NewBinaryBitmapFromFile(test.file)
+			r, e := reader.Decode(img, nil)
+			if e != nil {
+				t.Fatalf("reader.Decode returns error: %+v", e)
+			}
+			if text := r.GetText(); text != test.text {
+				t.Fatalf("result text = \"%v\", wants \"%v\"", text, test.text)
+			}
+			if format := r.GetBarcodeFormat(); format != test.format {
+				t.Fatalf("result format = %v, wants %v", format, test.format)
+			}
+
+			// other formats only
+			var formats []gozxing.BarcodeFormat
+			for _, f := range []gozxing.BarcodeFormat{
+				gozxing.BarcodeFormat_EAN_13, gozxing.BarcodeFormat_CODE_39, gozxing.BarcodeFormat_CODE_93,
+				gozxing.BarcodeFormat_CODE_128, gozxing.BarcodeFormat_ITF, gozxing.BarcodeFormat_CODABAR,
+			} {
+				// EAN_13 enables all the UPC/EAN formats
+				if f != test.format && !(f == gozxing.BarcodeFormat_EAN_13 && isUPCEAN(test.format)) {
+					formats = append(formats, f)
+				}
+			}
+			hints := map[gozxing.DecodeHintType]interface{}{
+				gozxing.DecodeHintType_POSSIBLE_FORMATS: formats,
+			}
+			if r, e := NewMultiFormatOneDReader(hints).Decode(img, hints); e == nil {
+				t.Fatalf("reader.Decode with %v must be error: %v", formats, r)
+			}
+		})
+	}
+}
+
+func TestMultiFormatOneDReader_DecodeRow(t *testing.T) {
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{
+			gozxing.BarcodeFormat_CODE_39,
+		},
+		gozxing.DecodeHintType_ASSUME_CODE_39_CHECK_DIGIT: true,
+	}
+	reader := NewMultiFormatOneDReader(hints).(*multiFormatOneDReader)
+	if n := len(reader.readers); n != 1 {
+		t.Fatalf("len(readers) = %v, wants 1", n)
+	}
+	if r, ok := reader.readers[0].(*code39Reader); !ok || !r.usingCheckDigit {
+		t.Fatalf("readers[0] must be Code39Reader using check digit, %T", reader.readers[0])
+	}
+
+	row := gozxing.NewBitArray(30)
+	_, e := reader.DecodeRow(0, row, hints)
+	if _, ok := e.(gozxing.NotFoundException); !ok {
+		t.Fatalf("error must be NotFoundException, %T, %+v", e, e)
+	}
+}
+
+func TestMultiFormatOneDReader_UnavailableFormat(t *testing.T) {
+	// RSS_14 is not available without the row decoder
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{
+			gozxing.BarcodeFormat_RSS_14,
+		},
+	}
+	reader := NewMultiFormatOneDReader(hints).(*multiFormatOneDReader)
+	if n := len(reader.readers); n != 0 {
+		t.Fatalf("len(readers) = %v, wants 0", n)
+	}
+
+	img := testutil.NewBinaryBitmapFromFile("testdata/ean13/1.png")
+	if r, e := reader.Decode(img, hints); e == nil {
+		t.Fatalf("reader.Decode must be error: %v", r)
+	}
+}
+
+func TestMultiFormatOneDReader_Code39CheckDigit(t *testing.T) {
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ASSUME_CODE_39_CHECK_DIGIT: true,
+	}
+	for _, h := range []map[gozxing.DecodeHintType]interface{}{nil, hints} {
+		_, expect := h[gozxing.DecodeHintType_ASSUME_CODE_39_CHECK_DIGIT]
+		reader := NewMultiFormatOneDReader(h).(*multiFormatOneDReader)
+		found := false
+		for _, r := range reader.readers {
+			if r, ok := r.(*code39Reader); ok {
+				found = true
+				if r.usingCheckDigit != expect {
+					t.Fatalf("Code39Reader usingCheckDigit = %v, wants %v", r.usingCheckDigit, expect)
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("readers must contain Code39Reader, %v", reader.readers)
+		}
+	}
+}
+
+func TestNewMultiFormatOneDReaderWithRowDecoders(t *testing.T) {
+	decoder := &testRowDecoder{}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{
+			gozxing.BarcodeFormat_CODE_128,
+			gozxing.BarcodeFormat_RSS_EXPANDED,
+		},
+	}
+	reader := NewMultiFormatOneDReaderWithRowDecoders(hints, decoder).(*multiFormatOneDReader)
+	if n := len(reader.readers); n != 2 || reader.readers[1] != decoder {
+		t.Fatalf("readers = %v, wants [code128, decoder]", reader.readers)
+	}
+
+	hints = map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{
+			gozxing.BarcodeFormat_RSS_EXPANDED,
+		},
+	}
+	reader = NewMultiFormatOneDReaderWithRowDecoders(hints, decoder).(*multiFormatOneDReader)
+	if n := len(reader.readers); n != 1 || reader.readers[0] != decoder {
+		t.Fatalf("readers = %v, wants [decoder]", reader.readers)
+	}
+
+	reader = NewMultiFormatOneDReaderWithRowDecoders(nil, decoder).(*multiFormatOneDReader)
+	if n := len(reader.readers); reader.readers[n-1] != decoder {
+		t.Fatalf("last reader must be the decoder, %T", reader.readers[n-1])
+	}
+
+	row := gozxing.NewBitArray(30)
+	if _, e := reader.DecodeRow(0, row, nil); e == nil {
+		t.Fatalf("DecodeRow must be error")
+	}
+	decoder.decoded = true
+	r, e := reader.DecodeRow(0, row, nil)
+	if e != nil {
+		t.Fatalf("DecodeRow returns error: %+v", e)
+	}
+	if format := r.GetBarcodeFormat(); format != gozxing.BarcodeFormat_RSS_EXPANDED {
+		t.Fatalf("result format = %v, wants RSS_EXPANDED", format)
+	}
+
+	reader.Reset()
+	if !decoder.reset {
+		t.Fatalf("Reset must reset the decoders")
+	}
+}
+
+func TestMultiFormatOneDReader_Concurrent(t *testing.T) {
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	}
+	testutil.TestConcurrentDecode(t, NewMultiFormatOneDReader(hints),
+		[]string{"testdata/ean13/1.png", "testdata/code39/01.png", "testdata/code128/1.png", "testdata/codabar/01.png"},
+		[]string{"8413000065504", "165627", "168901", "1234567890"},
+		hints)
+}
@@ -1,7 +1,9 @@
 package oned
 
 import (
+	"context"
 	"math"
+	"sync"
 
 	"github.com/makiuchi-d/gozxing"
 )
@@ -20,12 +22,20 @@ type RowDecoder interface {
 
 // OneDReader Encapsulates functionality and implementation that is common to all families
 // of one-dimensional barcodes.
+//
+// OneDReader is safe for concurrent use by multiple goroutines.
+// The row decoders reuse their buffers between the rows, so that the images are decoded one by one.
 type OneDReader struct {
 	RowDecoder
+
+	mutex sync.Mutex       // serializes decoding with the RowDecoder
+	row   *gozxing.BitArray // the row buffer reused between the images of the same width
 }
 
+var _ gozxing.ReaderWithContext = &OneDReader{}
+
 func NewOneDReader(rowDecoder RowDecoder) *OneDReader {
-	return &OneDReader{rowDecoder}
+	return &OneDReader{RowDecoder: rowDecoder}
 }
 
 func (this *OneDReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxing.Result, error) {
@@ -33,10 +43,30 @@ func (this *OneDReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozxin
 }
 
 // Decode Note that we don't try rotation without the try harder flag, even if rotation was supported.
+// If the ALSO_INVERTED hint is set, the inverted image is also tried.
 func (this *OneDReader) Decode(
 	image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return this.DecodeContext(context.Background(), image, hints)
+}
 
-	result, e := this.doDecode(image, hints)
+// DecodeContext Decodes the image as Decode, and stops scanning the rows when the context is done.
+//
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *OneDReader) DecodeContext(ctx context.Context,
+	image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return gozxing.Reader_DecodeAlsoInverted(image, hints,
+		func(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+			return this.decode(ctx, image, hints)
+		})
+}
+
+func (this *OneDReader) decode(ctx context.Context,
+	image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+
+	result, e := this.doDecode(ctx, image, hints)
 	if e == nil {
 		return result, nil
 	}
@@ -55,7 +85,7 @@ func (this *OneDReader) Decode(
 		return nil, gozxing.WrapReaderException(e)
 	}
 
-	result, e = this.doDecode(rotatedImage, hints)
+	result, e = this.doDecode(ctx, rotatedImage, hints)
 	if e != nil {
 		return nil, e
 	}
@@ -90,16 +120,21 @@ func (this *OneDReader) Reset() {
 // decided that moving up and down by about 1/16 of the image is pretty good; we try more of the
 // image if "trying harder".
 //
+// @param ctx The context to stop scanning
 // @param image The image to decode
 // @param hints Any hints that were requested
 // @return The contents of the decoded barcode
 // @throws NotFoundException Any spontaneous errors which occur
-func (this *OneDReader) doDecode(
+// @throws ReaderException wrapping ctx.Err() if the context is done
+func (this *OneDReader) doDecode(ctx context.Context,
 	image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
 
 	width := image.GetWidth()
 	height := image.GetHeight()
-	row := gozxing.NewBitArray(width)
+	if this.row == nil || this.row.GetSize() != width {
+		this.row = gozxing.NewBitArray(width)
+	}
+	row := this.row
 
 	_, tryHarder := hints[gozxing.DecodeHintType_TRY_HARDER]
 	rowStep := height >> 5
@@ -116,6 +151,9 @@ func (this *OneDReader) doDecode(
 
 	middle := height / 2
 	for x := 0; x < maxLines; x++ {
+		if e := gozxing.Reader_CheckContext(ctx); e != nil {
+			return nil, e
+		}
 
 		// Scanning from the middle out. Determine which row we're looking at next:
 		rowStepsAboveOrBelow := (x + 1) / 2
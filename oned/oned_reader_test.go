@@ -1,12 +1,15 @@
 package oned
 
 import (
+	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	errors "golang.org/x/xerrors"
 
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/testutil"
 )
 
 func TestRecordPattern(t *testing.T) {
@@ -133,14 +136,14 @@ func TestOneDReader_doDecode(t *testing.T) {
 
 	reader := NewEAN8Reader().(*ean8Reader)
 
-	_, e := reader.doDecode(bmp, nil)
+	_, e := reader.doDecode(context.Background(), bmp, nil)
 	if e == nil {
 		t.Fatalf("doDecode must be error")
 	}
 
 	src = newTestBitSource(1, "0000")
 	bmp, _ = gozxing.NewBinaryBitmap(gozxing.NewGlobalHistgramBinarizer(src))
-	_, e = reader.doDecode(bmp, nil)
+	_, e = reader.doDecode(context.Background(), bmp, nil)
 	if e == nil {
 		t.Fatalf("doDecode must be error")
 	}
@@ -157,7 +160,7 @@ func TestOneDReader_doDecode(t *testing.T) {
 	src = newTestBitSource(10,
 		"000010101001110010001000010101110010101011000101011110110010010011001010000")
 	bmp, _ = gozxing.NewBinaryBitmap(gozxing.NewGlobalHistgramBinarizer(src))
-	r, e := reader.doDecode(bmp, hints)
+	r, e := reader.doDecode(context.Background(), bmp, hints)
 	if e != nil {
 		t.Fatalf("doDecode returns error, %v", e)
 	}
@@ -173,6 +176,73 @@ func TestOneDReader_doDecode(t *testing.T) {
 	}
 }
 
+func TestOneDReader_doDecodeReuseRow(t *testing.T) {
+	reader := NewEAN8Reader().(*ean8Reader)
+	src := newTestBitSource(10,
+		"000010101001110010001000010101110010101011000101011110110010010011001010000")
+	bmp, _ := gozxing.NewBinaryBitmap(gozxing.NewGlobalHistgramBinarizer(src))
+	if _, e := reader.doDecode(context.Background(), bmp, nil); e != nil {
+		t.Fatalf("doDecode returns error, %v", e)
+	}
+	row := reader.row
+
+	// the row is reused for the image of the same width
+	if _, e := reader.doDecode(context.Background(), bmp, nil); e != nil {
+		t.Fatalf("doDecode returns error, %v", e)
+	}
+	if reader.row != row {
+		t.Fatalf("row must be reused for the same width")
+	}
+
+	src = newTestBitSource(10, "0000")
+	bmp, _ = gozxing.NewBinaryBitmap(gozxing.NewGlobalHistgramBinarizer(src))
+	_, _ = reader.doDecode(context.Background(), bmp, nil)
+	if reader.row == row || reader.row.GetSize() != 4 {
+		t.Fatalf("row must be created for the different width, %v", reader.row)
+	}
+}
+
+func TestOneDReader_DecodeContext(t *testing.T) {
+	reader := NewEAN8Reader().(gozxing.ReaderWithContext)
+
+	// "12345670"
+	src := newTestBitSource(10,
+		"000010100110010010011011110101000110101010011101010000100010011100101010000")
+	bmp, _ := gozxing.NewBinaryBitmap(gozxing.NewGlobalHistgramBinarizer(src))
+
+	r, e := reader.DecodeContext(context.Background(), bmp, nil)
+	if e != nil {
+		t.Fatalf("DecodeContext returns error, %v", e)
+	}
+	if txt := r.GetText(); txt != "12345670" {
+		t.Fatalf("DecodeContext text = \"%v\", expect \"12345670\"", txt)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER:    true,
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	_, e = reader.DecodeContext(ctx, bmp, hints)
+	if _, ok := e.(gozxing.ReaderException); !ok {
+		t.Fatalf("DecodeContext must be ReaderException, %T", e)
+	}
+	if _, ok := e.(gozxing.NotFoundException); ok {
+		t.Fatalf("DecodeContext must not be NotFoundException, %v", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DecodeContext must wrap context.Canceled, %v", e)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, e = reader.DecodeContext(ctx, bmp, nil)
+	if !errors.Is(e, context.DeadlineExceeded) {
+		t.Fatalf("DecodeContext must wrap context.DeadlineExceeded, %v", e)
+	}
+}
+
 func TestOneDReader_Reset(t *testing.T) {
 	NewEAN8Reader().Reset() // do nothing
 }
@@ -230,3 +300,27 @@ func TestMin(t *testing.T) {
 		t.Fatalf("min(10, 9) = %v, expect 9", r)
 	}
 }
+
+func TestOneDReader_DecodeAlsoInverted(t *testing.T) {
+	file := "testdata/ean13/1.png"
+	bmp, _ := testutil.NewBinaryBitmapFromFile(file).Invert()
+	reader := NewEAN13Reader()
+
+	if _, e := reader.Decode(bmp, nil); e == nil {
+		t.Fatalf("Decode(inverted %s) without ALSO_INVERTED must be error", file)
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	result, e := reader.Decode(bmp, hints)
+	if e != nil {
+		t.Fatalf("Decode(inverted %s) returns error, %v", file, e)
+	}
+	if txt, expect := result.GetText(), "8413000065504"; txt != expect {
+		t.Fatalf("Decode(inverted %s) = \"%v\", expect \"%v\"", file, txt, expect)
+	}
+	if inv := result.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; inv != true {
+		t.Fatalf("Decode(inverted %s) INVERTED = %v, expect true", file, inv)
+	}
+}
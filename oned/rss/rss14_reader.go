@@ -2,6 +2,7 @@ package rss
 
 import (
 	"strconv"
+	"sync"
 
 	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/common/util"
@@ -34,6 +35,9 @@ var (
 type rss14Reader struct {
 	*oned.OneDReader
 	*AbstractRSSReader
+
+	// The pairs are kept between the rows and the images until Reset.
+	mutex              sync.Mutex // guards the pairs and the buffers of AbstractRSSReader
 	possibleLeftPairs  []*Pair
 	possibleRightPairs []*Pair
 }
@@ -49,6 +53,8 @@ func NewRSS14Reader() gozxing.Reader {
 }
 
 func (this *rss14Reader) DecodeRow(rowNumber int, row *gozxing.BitArray, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
 	leftPair := this.decodePair(row, false, rowNumber, hints)
 	this.possibleLeftPairs = this.addOrTally(this.possibleLeftPairs, leftPair)
 	row.Reverse()
@@ -86,6 +92,8 @@ func (this *rss14Reader) addOrTally(possiblePairs []*Pair, pair *Pair) []*Pair {
 }
 
 func (this *rss14Reader) Reset() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
 	this.possibleLeftPairs = this.possibleLeftPairs[:0]
 	this.possibleRightPairs = this.possibleRightPairs[:0]
 }
@@ -2,9 +2,11 @@ package rss
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
 	"github.com/makiuchi-d/gozxing/testutil"
 )
 
@@ -530,3 +532,38 @@ func TestRSS14Reader(t *testing.T) {
 		reader.Reset()
 	}
 }
+
+func TestRSS14Reader_MultiFormatOneDReader(t *testing.T) {
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{gozxing.BarcodeFormat_RSS_14},
+	}
+	for _, h := range []map[gozxing.DecodeHintType]interface{}{nil, hints} {
+		reader := oned.NewMultiFormatOneDReaderWithRowDecoders(h, NewRSS14Reader().(oned.RowDecoder))
+		testutil.TestFile(t, reader, "testdata/1_1.png", "04412345678909", gozxing.BarcodeFormat_RSS_14, h,
+			map[gozxing.ResultMetadataType]interface{}{
+				gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER: "]e0",
+			})
+	}
+}
+
+func TestRSS14Reader_Concurrent(t *testing.T) {
+	reader := NewRSS14Reader()
+	testutil.TestConcurrentDecode(t, reader,
+		[]string{"testdata/1_1.png"}, []string{"04412345678909"}, nil)
+
+	// the pairs kept by the reader are reset while decoding
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		testutil.TestConcurrentDecode(t, reader,
+			[]string{"testdata/1_1.png"}, []string{"04412345678909"}, nil)
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			reader.Reset()
+		}
+	}()
+	wg.Wait()
+}
@@ -19,6 +19,7 @@ func DecodedBitStreamParser_Decode(
 	bits := common.NewBitSource(bytes)
 	result := make([]byte, 0, 50)
 	byteSegments := make([][]byte, 0, 1)
+	var segments []*common.DecodedSegment
 	symbolSequence := -1
 	parityData := -1
 	symbologyModifier := 0
@@ -27,6 +28,7 @@ func DecodedBitStreamParser_Decode(
 	fc1InEffect := false
 	hasFNC1first := false
 	hasFNC1second := false
+	applicationIndicator := -1
 	var mode *Mode
 	var e error
 
@@ -52,6 +54,11 @@ func DecodedBitStreamParser_Decode(
 			hasFNC1second = true // symbology detection
 			// We do little with FNC1 except alter the parsed result a bit according to the spec
 			fc1InEffect = true
+			// FNC1 in second position is followed by 8 bits of the application indicator
+			applicationIndicator, e = bits.ReadBits(8)
+			if e != nil {
+				return nil, gozxing.WrapFormatException(e)
+			}
 		case Mode_STRUCTURED_APPEND:
 			// sequence number and parity is added later to the result metadata
 			// Read next 8 bits (symbol sequence #) and 8 bits (parity data), then continue
@@ -85,10 +92,13 @@ func DecodedBitStreamParser_Decode(
 				return nil, gozxing.WrapFormatException(e)
 			}
 			if subset == GB2312_SUBSET {
-				result, e = DecodedBitStreamParser_decodeHanziSegment(bits, result, countHanzi)
+				start := len(result)
+				var raw []byte
+				result, raw, e = decodeHanziSegment(bits, result, countHanzi)
 				if e != nil {
 					return nil, e
 				}
+				segments = appendSegment(segments, mode, currentCharacterSetECI, raw, start, len(result))
 			}
 		default:
 			// "Normal" QR code modes:
@@ -97,30 +107,36 @@ func DecodedBitStreamParser_Decode(
 			if e != nil {
 				return nil, gozxing.WrapFormatException(e)
 			}
+			start := len(result)
+			var raw []byte
 			switch mode {
 			case Mode_NUMERIC:
 				result, e = DecodedBitStreamParser_decodeNumericSegment(bits, result, count)
 				if e != nil {
 					return nil, e
 				}
+				raw = result[start:]
 			case Mode_ALPHANUMERIC:
 				result, e = DecodedBitStreamParser_decodeAlphanumericSegment(bits, result, count, fc1InEffect)
 				if e != nil {
 					return nil, e
 				}
+				raw = result[start:]
 			case Mode_BYTE:
 				result, byteSegments, e = DecodedBitStreamParser_decodeByteSegment(bits, result, count, currentCharacterSetECI, byteSegments, hints)
 				if e != nil {
 					return nil, e
 				}
+				raw = byteSegments[len(byteSegments)-1]
 			case Mode_KANJI:
-				result, e = DecodedBitStreamParser_decodeKanjiSegment(bits, result, count)
+				result, raw, e = decodeKanjiSegment(bits, result, count)
 				if e != nil {
 					return nil, e
 				}
 			default:
 				return nil, gozxing.NewFormatException("Unknown mode")
 			}
+			segments = appendSegment(segments, mode, currentCharacterSetECI, raw, start, len(result))
 			break
 		}
 
@@ -150,19 +166,45 @@ func DecodedBitStreamParser_Decode(
 	if len(byteSegments) == 0 {
 		byteSegments = nil
 	}
-	return common.NewDecoderResultWithParams(bytes,
+	decoderResult := common.NewDecoderResultWithParams(bytes,
 		string(result),
 		byteSegments,
 		ecLevel.String(),
 		symbolSequence,
 		parityData,
-		symbologyModifier), nil
+		symbologyModifier)
+	if hasFNC1first {
+		decoderResult.SetFNC1Position(1, -1)
+	} else if hasFNC1second {
+		decoderResult.SetFNC1Position(2, applicationIndicator)
+	}
+	decoderResult.SetSegments(segments)
+	return decoderResult, nil
+}
+
+// appendSegment appends the decoded bytes of the mode to the list of the decoded segments.
+// Each segment header makes its own segment, even if the previous one has the same mode and ECI.
+func appendSegment(segments []*common.DecodedSegment, mode *Mode, eci *common.CharacterSetECI,
+	raw []byte, start, end int) []*common.DecodedSegment {
+	eciValue := -1
+	if eci != nil {
+		eciValue = eci.GetValue()
+	}
+	b := make([]byte, len(raw))
+	copy(b, raw)
+	return append(segments, common.NewDecodedSegment(mode.String(), eciValue, b, start, end))
 }
 
 func DecodedBitStreamParser_decodeHanziSegment(bits *common.BitSource, result []byte, count int) ([]byte, error) {
+	result, _, e := decodeHanziSegment(bits, result, count)
+	return result, e
+}
+
+// decodeHanziSegment returns the result and the GB2312 bytes of the segment
+func decodeHanziSegment(bits *common.BitSource, result []byte, count int) ([]byte, []byte, error) {
 	// Don't crash trying to read more bits than we have available.
 	if count*13 > bits.Available() {
-		return result, gozxing.NewFormatException("bits.Available() = %v", bits.Available())
+		return result, nil, gozxing.NewFormatException("bits.Available() = %v", bits.Available())
 	}
 
 	// Each character will require 2 bytes. Read the characters as 2-byte pairs
@@ -189,15 +231,21 @@ func DecodedBitStreamParser_decodeHanziSegment(bits *common.BitSource, result []
 	dec := common.StringUtils_GB2312_CHARSET.NewDecoder()
 	result, _, e := transform.Append(dec, result, buffer[:offset])
 	if e != nil {
-		return result, gozxing.WrapFormatException(e)
+		return result, nil, gozxing.WrapFormatException(e)
 	}
-	return result, nil
+	return result, buffer[:offset], nil
 }
 
 func DecodedBitStreamParser_decodeKanjiSegment(bits *common.BitSource, result []byte, count int) ([]byte, error) {
+	result, _, e := decodeKanjiSegment(bits, result, count)
+	return result, e
+}
+
+// decodeKanjiSegment returns the result and the Shift_JIS bytes of the segment
+func decodeKanjiSegment(bits *common.BitSource, result []byte, count int) ([]byte, []byte, error) {
 	// Don't crash trying to read more bits than we have available.
 	if count*13 > bits.Available() {
-		return result, gozxing.NewFormatException("bits.Available() = %v", bits.Available())
+		return result, nil, gozxing.NewFormatException("bits.Available() = %v", bits.Available())
 	}
 
 	// Each character will require 2 bytes. Read the characters as 2-byte pairs
@@ -225,9 +273,9 @@ func DecodedBitStreamParser_decodeKanjiSegment(bits *common.BitSource, result []
 	dec := common.StringUtils_SHIFT_JIS_CHARSET.NewDecoder()
 	result, _, e := transform.Append(dec, result, buffer[:offset])
 	if e != nil {
-		return result, gozxing.WrapFormatException(e)
+		return result, nil, gozxing.WrapFormatException(e)
 	}
-	return result, nil
+	return result, buffer[:offset], nil
 }
 
 func DecodedBitStreamParser_decodeByteSegment(bits *common.BitSource,
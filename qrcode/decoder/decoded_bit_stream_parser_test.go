@@ -330,6 +330,34 @@ func TestDecodedBitStreamParsser_DecodeNormalModes(t *testing.T) {
 	if s := result.GetText(); s != "1234ABC餃子邂遘" {
 		t.Fatalf("Decode result = \"%v\", expect \"1234ABC餃子邂遘\"", s)
 	}
+	segments := []*common.DecodedSegment{
+		common.NewDecodedSegment("NUMERIC", -1, []byte("1234"), 0, 4),
+		common.NewDecodedSegment("ALPHANUMERIC", -1, []byte("ABC"), 4, 7),
+		common.NewDecodedSegment("KANJI", -1, []byte{0xe9, 0x4c, 0x8e, 0x71}, 7, 13),
+		common.NewDecodedSegment("BYTE", -1, []byte{0xe7, 0xae, 0xe7, 0xa7}, 13, 19),
+	}
+	if r := result.GetSegments(); !reflect.DeepEqual(r, segments) {
+		t.Fatalf("Decode segments = %v, expect %v", r, segments)
+	}
+
+	// numeric 12     0001, 0000000010, 0001100
+	// numeric 34     0001, 0000000010, 0100010
+	// terminator     0000
+	bytes = []byte{0x10, 0x08, 0x60, 0x80, 0x48, 0x80}
+	result, e = DecodedBitStreamParser_Decode(bytes, ver, ErrorCorrectionLevel_M, nil)
+	if e != nil {
+		t.Fatalf("Decode returns error, %v", e)
+	}
+	if s := result.GetText(); s != "1234" {
+		t.Fatalf("Decode result = \"%v\", expect \"1234\"", s)
+	}
+	segments = []*common.DecodedSegment{
+		common.NewDecodedSegment("NUMERIC", -1, []byte("12"), 0, 2),
+		common.NewDecodedSegment("NUMERIC", -1, []byte("34"), 2, 4),
+	}
+	if r := result.GetSegments(); !reflect.DeepEqual(r, segments) {
+		t.Fatalf("Decode segments = %v, expect %v", r, segments)
+	}
 
 	// numeric 1234   0001, 0000000100, 0001111011 0100
 	// character count bits error
@@ -390,6 +418,13 @@ func TestDecodedBitStreamParser_DecodeWithCharcterSet(t *testing.T) {
 	if s := result.GetText(); s != "Weiß金魚" {
 		t.Fatalf("Decode result = \"%v\", expect \"Weiß金魚\"", s)
 	}
+	segments := []*common.DecodedSegment{
+		common.NewDecodedSegment("BYTE", 1, []byte{0x57, 0x65, 0x69, 0xdf}, 0, 5),
+		common.NewDecodedSegment("BYTE", 20, []byte{0x8b, 0xe0, 0x8b, 0x9b}, 5, 11),
+	}
+	if r := result.GetSegments(); !reflect.DeepEqual(r, segments) {
+		t.Fatalf("Decode segments = %v, expect %v", r, segments)
+	}
 
 	// invalid eci segment  0111, 1111 1111
 	bytes = []byte{0x7f, 0xff}
@@ -495,7 +530,7 @@ func TestDecodedBitStreamPars_decodeGS1(t *testing.T) {
 	var ver, _ = Version_GetVersionForNumber(1)
 
 	// FNC1(1st position) 01049123451234591597033130128%10ABC123
-	// fnc1    1001
+	// fnc1    0101
 	// numeric 0001
 	// count 29 = 0000011101
 	// 010     0000001010
@@ -515,11 +550,11 @@ func TestDecodedBitStreamPars_decodeGS1(t *testing.T) {
 	// BC      00111111011
 	// 12      00000101111
 	// 3       000011
-	// 1001 0001 0000 0111 0100 0000 1010 0111  1010 1100 1110 1010 1000 0000 0001 0101
+	// 0101 0001 0000 0111 0100 0000 1010 0111  1010 1100 1110 1010 1000 0000 0001 0101
 	// 1001 1110 0100 1111 1100 1010 0101 0010  1101 0010 1101 0011 1000 0100 0000 1001
 	// 1101 0101 1110 0000 0010 1000 1111 1101  1000 0010 1111 0000 11
 	bytes = []byte{
-		0x91, 0x07, 0x40, 0xa7, 0xac, 0xea, 0x80, 0x15,
+		0x51, 0x07, 0x40, 0xa7, 0xac, 0xea, 0x80, 0x15,
 		0x9e, 0x4f, 0xca, 0x52, 0xd2, 0xd3, 0x84, 0x09,
 		0xd5, 0xe0, 0x28, 0xfd, 0x82, 0xf0, 0xc0,
 	}
@@ -531,6 +566,49 @@ func TestDecodedBitStreamPars_decodeGS1(t *testing.T) {
 	if text := result.GetText(); text != expect {
 		t.Fatalf("Decode result: \"%v\", expect \"%v\" ", text, expect)
 	}
+	if r := result.GetFNC1Position(); r != 1 {
+		t.Fatalf("FNC1 position = %v, expect 1", r)
+	}
+	if r := result.GetApplicationIndicator(); r != -1 {
+		t.Fatalf("application indicator = %v, expect -1", r)
+	}
+}
+
+func TestDecodedBitStreamParser_decodeFNC1SecondPosition(t *testing.T) {
+	ver, _ := Version_GetVersionForNumber(1)
+
+	// FNC1(2nd position) AI=37, AB%CD
+	// fnc1         1001
+	// indicator    00100101
+	// alphanumeric 0010
+	// count 5 =    000000101
+	// AB           00111001101
+	// %C           11010111010
+	// D            001101
+	// 1001 0010 0101 0010 0000 0010 1001 1100  1101 1101 0111 0100 0110 1000 0
+	bytes := []byte{0x92, 0x52, 0x02, 0x9c, 0xdd, 0x74, 0x68, 0x00}
+	result, e := DecodedBitStreamParser_Decode(bytes, ver, ErrorCorrectionLevel_M, nil)
+	if e != nil {
+		t.Fatalf("Decode returns error, %v", e)
+	}
+	if text, expect := result.GetText(), "AB\x1dCD"; text != expect {
+		t.Fatalf("Decode result: %q, expect %q", text, expect)
+	}
+	if r := result.GetFNC1Position(); r != 2 {
+		t.Fatalf("FNC1 position = %v, expect 2", r)
+	}
+	if r := result.GetApplicationIndicator(); r != 37 {
+		t.Fatalf("application indicator = %v, expect 37", r)
+	}
+	if r := result.GetSymbologyModifier(); r != 5 {
+		t.Fatalf("symbologyModifier = %v, expect 5", r)
+	}
+
+	// no application indicator
+	_, e = DecodedBitStreamParser_Decode([]byte{0x92}, ver, ErrorCorrectionLevel_M, nil)
+	if _, ok := e.(gozxing.FormatException); !ok {
+		t.Fatalf("Decode must be FormatException, %T", e)
+	}
 }
 
 func TestDecodedBitStreamParser_Decode_SymbologyModifier(t *testing.T) {
@@ -538,11 +616,11 @@ func TestDecodedBitStreamParser_Decode_SymbologyModifier(t *testing.T) {
 		symbologyModifier int
 		bytes             []byte
 	}{
-		{4, []byte{0x57, 0x1a, 0x00}}, // FNC1-1stPos, ECI(26), TERM
-		{6, []byte{0x71, 0xa9, 0x00}}, // ECI(26), FNC1-2ndPos, TERM
-		{2, []byte{0x71, 0xa0}},       // ECI(26), TERM
-		{3, []byte{0x50}},             // FNC1-1stPos, TERM
-		{5, []byte{0x90}},             // FNC1-2ndPos, TERM
+		{4, []byte{0x57, 0x1a, 0x00}},       // FNC1-1stPos, ECI(26), TERM
+		{6, []byte{0x71, 0xa9, 0x25, 0x00}}, // ECI(26), FNC1-2ndPos(37), TERM
+		{2, []byte{0x71, 0xa0}},             // ECI(26), TERM
+		{3, []byte{0x50}},                   // FNC1-1stPos, TERM
+		{5, []byte{0x92, 0x50}},             // FNC1-2ndPos(37), TERM
 		{1, []byte{0x00}},
 	}
 	for _, test := range tests {
@@ -84,8 +84,7 @@ func (this *Decoder) Decode(bits *gozxing.BitMatrix, hints map[gozxing.DecodeHin
 	}
 
 	if e == nil {
-		// Success! Notify the caller that the code was mirrored.
-		result.SetOther(NewQRCodeDecoderMetaData(true))
+		// Success! The meta-data of the result notifies the caller that the code was mirrored.
 		return result, nil
 	}
 
@@ -144,7 +143,12 @@ func (this *Decoder) decode(parser *BitMatrixParser, hints map[gozxing.DecodeHin
 	}
 
 	// Decode the contents of that stream of bytes
-	return DecodedBitStreamParser_Decode(resultBytes, version, ecLevel, hints)
+	result, e := DecodedBitStreamParser_Decode(resultBytes, version, ecLevel, hints)
+	if e != nil {
+		return nil, e
+	}
+	result.SetOther(NewQRCodeDecoderMetaDataWithSymbol(parser.mirror, version, int(formatinfo.GetDataMask())))
+	return result, nil
 }
 
 func (this *Decoder) correctErrors(codewordBytes []byte, numDataCodewords int) error {
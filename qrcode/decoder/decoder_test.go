@@ -16,6 +16,23 @@ func unsetRegion(image *gozxing.BitMatrix, x, y, w, h int) {
 	}
 }
 
+func testDecoderMetaData(t testing.TB, result *common.DecoderResult, mirrored bool) {
+	t.Helper()
+	md, ok := result.GetOther().(*QRCodeDecoderMetaData)
+	if !ok {
+		t.Fatalf("Decoder result other must be QRCodeDecoderMetaData, %T", result.GetOther())
+	}
+	if r := md.IsMirrored(); r != mirrored {
+		t.Fatalf("IsMirrored = %v, expect %v", r, mirrored)
+	}
+	if r := md.GetVersion().GetVersionNumber(); r != 1 {
+		t.Fatalf("GetVersion = %v, expect 1", r)
+	}
+	if r := md.GetMaskPattern(); r < 0 || r > 7 {
+		t.Fatalf("GetMaskPattern = %v, expect 0-7", r)
+	}
+}
+
 func TestDecoder_Decode(t *testing.T) {
 	decoder := NewDecoder()
 	var result *common.DecoderResult
@@ -32,6 +49,7 @@ func TestDecoder_Decode(t *testing.T) {
 	if r := result.GetText(); r != "hello" {
 		t.Fatalf("Decoder result text=\"%v\", expect \"hello\"", r)
 	}
+	testDecoderMetaData(t, result, false)
 	// mirrored qrcode
 	result, e = decoder.Decode(rbits, nil)
 	if e != nil {
@@ -40,6 +58,7 @@ func TestDecoder_Decode(t *testing.T) {
 	if r := result.GetText(); r != "hello" {
 		t.Fatalf("Decoder result text=\"%v\", expect \"hello\"", r)
 	}
+	testDecoderMetaData(t, result, true)
 
 	bits, _ = gozxing.NewSquareBitMatrix(1)
 	_, e = decoder.Decode(bits, nil)
@@ -4,18 +4,46 @@ import (
 	"github.com/makiuchi-d/gozxing"
 )
 
+// QRCodeDecoderMetaData Meta-data container for QR Code decoding.
+// Instances of this class may be used to convey information back to the decoding caller.
 type QRCodeDecoderMetaData struct {
-	mirrored bool
+	mirrored    bool
+	version     *Version
+	maskPattern int
 }
 
 func NewQRCodeDecoderMetaData(mirrored bool) *QRCodeDecoderMetaData {
-	return &QRCodeDecoderMetaData{mirrored}
+	return &QRCodeDecoderMetaData{mirrored, nil, -1}
+}
+
+// NewQRCodeDecoderMetaDataWithSymbol creates meta-data with the symbol geometry.
+//
+// @param mirrored whether the symbol was read as a mirror image
+// @param version the version of the symbol
+// @param maskPattern the data mask pattern (0-7) applied to the symbol
+//
+func NewQRCodeDecoderMetaDataWithSymbol(mirrored bool, version *Version, maskPattern int) *QRCodeDecoderMetaData {
+	return &QRCodeDecoderMetaData{mirrored, version, maskPattern}
 }
 
 func (this *QRCodeDecoderMetaData) IsMirrored() bool {
 	return this.mirrored
 }
 
+// GetVersion returns the version of the symbol, or nil if unknown
+func (this *QRCodeDecoderMetaData) GetVersion() *Version {
+	return this.version
+}
+
+// GetMaskPattern returns the data mask pattern of the symbol, or -1 if unknown
+func (this *QRCodeDecoderMetaData) GetMaskPattern() int {
+	return this.maskPattern
+}
+
+// ApplyMirroredCorrection Apply the result points' order correction due to mirroring.
+//
+// @param points Array of points to apply mirror correction to.
+//
 func (this *QRCodeDecoderMetaData) ApplyMirroredCorrection(points []gozxing.ResultPoint) {
 	if !this.mirrored || len(points) < 3 {
 		return
@@ -17,6 +17,12 @@ func TestQRCodeDecoderMetaData(t *testing.T) {
 	if md.IsMirrored() {
 		t.Fatalf("IsMirrored must be false")
 	}
+	if v := md.GetVersion(); v != nil {
+		t.Fatalf("GetVersion = %v, expect nil", v)
+	}
+	if m := md.GetMaskPattern(); m != -1 {
+		t.Fatalf("GetMaskPattern = %v, expect -1", m)
+	}
 	md.ApplyMirroredCorrection(points)
 	if points[0].GetX() != 10 || points[0].GetY() != 50 {
 		t.Fatalf("points[0] = (%v,%v), expect (10,50)", points[0].GetX(), points[0].GetY())
@@ -42,5 +48,18 @@ func TestQRCodeDecoderMetaData(t *testing.T) {
 	if points[2].GetX() != 10 || points[2].GetY() != 50 {
 		t.Fatalf("points[2] = (%v,%v), expect (10,50)", points[2].GetX(), points[2].GetY())
 	}
+}
 
+func TestQRCodeDecoderMetaDataWithSymbol(t *testing.T) {
+	version, _ := Version_GetVersionForNumber(7)
+	md := NewQRCodeDecoderMetaDataWithSymbol(true, version, 3)
+	if !md.IsMirrored() {
+		t.Fatalf("IsMirrored must be true")
+	}
+	if v := md.GetVersion(); v != version {
+		t.Fatalf("GetVersion = %v, expect %v", v, version)
+	}
+	if m := md.GetMaskPattern(); m != 3 {
+		t.Fatalf("GetMaskPattern = %v, expect 3", m)
+	}
 }
@@ -1,6 +1,7 @@
 package detector
 
 import (
+	"context"
 	"math"
 
 	"github.com/makiuchi-d/gozxing"
@@ -31,6 +32,14 @@ func (this *Detector) DetectWithoutHints() (*common.DetectorResult, error) {
 }
 
 func (this *Detector) Detect(hints map[gozxing.DecodeHintType]interface{}) (*common.DetectorResult, error) {
+	return this.DetectContext(context.Background(), hints)
+}
+
+// DetectContext Detects a QR Code as Detect, and stops finding the finder patterns when the context is done.
+//
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *Detector) DetectContext(ctx context.Context, hints map[gozxing.DecodeHintType]interface{}) (*common.DetectorResult, error) {
 	if hints != nil {
 		if cb, ok := hints[gozxing.DecodeHintType_NEED_RESULT_POINT_CALLBACK]; ok {
 			this.resultPointCallback, _ = cb.(gozxing.ResultPointCallback)
@@ -38,7 +47,7 @@ func (this *Detector) Detect(hints map[gozxing.DecodeHintType]interface{}) (*com
 	}
 
 	finder := NewFinderPatternFinder(this.image, this.resultPointCallback)
-	info, e := finder.Find(hints)
+	info, e := finder.FindContext(ctx, hints)
 	if e != nil {
 		return nil, e
 	}
@@ -1,10 +1,13 @@
 package detector
 
 import (
+	"context"
 	"math"
 	"reflect"
 	"testing"
 
+	errors "golang.org/x/xerrors"
+
 	"github.com/makiuchi-d/gozxing"
 )
 
@@ -590,3 +593,24 @@ func TestDetector_DetectWithoutHint(t *testing.T) {
 		}
 	}
 }
+
+func TestDetector_DetectContext(t *testing.T) {
+	// version3 pattern
+	image, _ := gozxing.NewBitMatrix(45, 45)
+	makePattern(image, 10+3, 10+3, 1)
+	makePattern(image, 10+3+22, 10+3, 1)
+	makePattern(image, 10+3, 10+3+22, 1)
+	makeAlignPattern(image, 10+22, 10+22)
+
+	d := NewDetector(image)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, e := d.DetectContext(ctx, nil)
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DetectContext must wrap context.Canceled, %v", e)
+	}
+
+	if _, e = d.DetectContext(context.Background(), nil); e != nil {
+		t.Fatalf("DetectContext returns error, %v", e)
+	}
+}
@@ -1,6 +1,7 @@
 package detector
 
 import (
+	"context"
 	"math"
 	"sort"
 
@@ -39,6 +40,20 @@ func (f *FinderPatternFinder) GetPossibleCenters() []*FinderPattern {
 }
 
 func (f *FinderPatternFinder) Find(hints map[gozxing.DecodeHintType]interface{}) (*FinderPatternInfo, gozxing.NotFoundException) {
+	info, e := f.FindContext(context.Background(), hints)
+	if e != nil {
+		// the background context is never canceled, so that e is always NotFoundException
+		return nil, e.(gozxing.NotFoundException)
+	}
+	return info, nil
+}
+
+// FindContext Finds the three finder patterns as Find, and stops scanning the rows when the context is done.
+//
+// @throws NotFoundException if the finder patterns are not found
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (f *FinderPatternFinder) FindContext(ctx context.Context, hints map[gozxing.DecodeHintType]interface{}) (*FinderPatternInfo, error) {
 	_, tryHarder := hints[gozxing.DecodeHintType_TRY_HARDER]
 	maxI := f.image.GetHeight()
 	maxJ := f.image.GetWidth()
@@ -51,6 +66,9 @@ func (f *FinderPatternFinder) Find(hints map[gozxing.DecodeHintType]interface{})
 	done := false
 	stateCount := make([]int, 5)
 	for i := iSkip - 1; i < maxI && !done; i += iSkip {
+		if e := gozxing.Reader_CheckContext(ctx); e != nil {
+			return nil, e
+		}
 		FinderPatternFinder_doClearCounts(stateCount)
 		currentState := 0
 		for j := 0; j < maxJ; j++ {
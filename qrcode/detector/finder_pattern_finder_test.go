@@ -1,10 +1,13 @@
 package detector
 
 import (
+	"context"
 	"math"
 	"reflect"
 	"testing"
 
+	errors "golang.org/x/xerrors"
+
 	"github.com/makiuchi-d/gozxing"
 )
 
@@ -557,3 +560,27 @@ func TestFinderPatternFinder_Find2(t *testing.T) {
 		t.Fatalf("topRight is %v, expect %v", fi.topRight, expect.topRight)
 	}
 }
+
+func TestFinderPatternFinder_FindContext(t *testing.T) {
+	image, _ := gozxing.NewBitMatrix(40, 40)
+	makePattern(image, 10, 10, 2)
+	makePattern(image, 10, 32, 2)
+	makePattern(image, 32, 32, 2)
+
+	f := NewFinderPatternFinder(image, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, e := f.FindContext(ctx, nil)
+	if _, ok := e.(gozxing.NotFoundException); ok {
+		t.Fatalf("FindContext must not be NotFoundException, %v", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("FindContext must wrap context.Canceled, %v", e)
+	}
+
+	f = NewFinderPatternFinder(image, nil)
+	_, e = f.FindContext(context.Background(), nil)
+	if e != nil {
+		t.Fatalf("FindContext returns error, %v", e)
+	}
+}
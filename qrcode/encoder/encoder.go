@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"unicode/utf8"
 
 	textencoding "golang.org/x/text/encoding"
@@ -28,6 +29,15 @@ var alphanumericTable = []int{
 	25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, -1, -1, -1, -1, -1, // 0x50-0x5f
 }
 
+// dataTooBigException is the WriterException for the content which does not fit in the symbol.
+type dataTooBigException struct {
+	gozxing.WriterException
+}
+
+func newDataTooBigException(args ...interface{}) gozxing.WriterException {
+	return dataTooBigException{gozxing.NewWriterException(args...)}
+}
+
 // calculateMaskPenalty The mask penalty calculation is complicated.
 // See Table 21 of JISX0510:2004 (p.45) for details.
 // Basically it applies four rules and summate all penalties.
@@ -43,106 +53,217 @@ func Encoder_encodeWithoutHint(content string, ecLevel decoder.ErrorCorrectionLe
 }
 
 func Encoder_encode(content string, ecLevel decoder.ErrorCorrectionLevel, hints map[gozxing.EncodeHintType]interface{}) (*QRCode, gozxing.WriterException) {
-	// Determine what character encoding has been specified by the caller, if any
-	encoding := Encoder_DEFAULT_BYTE_MODE_ENCODING
+	return encode(content, ecLevel, hints, nil)
+}
+
+// getEncoding Determine what character encoding has been specified by the caller, if any
+func getEncoding(hints map[gozxing.EncodeHintType]interface{}) (textencoding.Encoding, bool, gozxing.WriterException) {
 	encodingHint, hasEncodingHint := hints[gozxing.EncodeHintType_CHARACTER_SET]
-	if hasEncodingHint {
-		if eci, ok := common.GetCharacterSetECIByName(fmt.Sprintf("%v", encodingHint)); ok {
-			encoding = eci.GetCharset()
-		} else {
-			return nil, gozxing.NewWriterException(encodingHint)
-		}
+	if !hasEncodingHint {
+		return Encoder_DEFAULT_BYTE_MODE_ENCODING, false, nil
 	}
+	eci, ok := common.GetCharacterSetECIByName(fmt.Sprintf("%v", encodingHint))
+	if !ok {
+		return nil, true, gozxing.NewWriterException(encodingHint)
+	}
+	return eci.GetCharset(), true, nil
+}
 
-	// Pick an encoding mode appropriate for the content. Note that this will not attempt to use
-	// multiple modes / segments even if that were more efficient. Twould be nice.
-	mode := chooseMode(content, encoding)
+// encode Encodes the content into a QR Code.
+// If saHeader is not nil, it is written in front of all the other segments.
+func encode(content string, ecLevel decoder.ErrorCorrectionLevel, hints map[gozxing.EncodeHintType]interface{},
+	saHeader *gozxing.BitArray) (*QRCode, gozxing.WriterException) {
 
-	// This will store the header information, like mode and
-	// length, as well as "header" segments like an ECI segment.
-	headerBits := gozxing.NewEmptyBitArray()
+	mode, version, headerAndDataBits, _, e := encodeBits(content, ecLevel, hints, saHeader)
+	if e != nil {
+		return nil, e
+	}
+	return buildQRCode(headerAndDataBits, mode, version, ecLevel, hints)
+}
 
-	// Append ECI segment if applicable
-	if mode == decoder.Mode_BYTE && hasEncodingHint {
-		eci, ok := common.GetCharacterSetECI(encoding)
-		if ok && eci != nil {
-			appendECI(eci, headerBits)
-		}
+// encodeBits Encodes the content into the segments without building the symbol.
+// If saHeader is not nil, it is written in front of all the other segments.
+//
+// @return the mode of the main segment, the version, the bits of all the segments,
+// and the bytes of the data written in the segments
+//
+func encodeBits(content string, ecLevel decoder.ErrorCorrectionLevel, hints map[gozxing.EncodeHintType]interface{},
+	saHeader *gozxing.BitArray) (*decoder.Mode, *decoder.Version, *gozxing.BitArray, []byte, gozxing.WriterException) {
+
+	encoding, hasEncodingHint, e := getEncoding(hints)
+	if e != nil {
+		return nil, nil, nil, nil, e
 	}
 
-	// Append the FNC1 mode header for GS1 formatted data if applicable
-	gs1FormatHint, hasGS1FormatHint := hints[gozxing.EncodeHintType_GS1_FORMAT]
-	if hasGS1FormatHint {
-		appendGS1, ok := gs1FormatHint.(bool)
-		if !ok {
-			s, ok := gs1FormatHint.(string)
-			if ok {
-				appendGS1, _ = strconv.ParseBool(s)
+	// Determine if the GS1 format is requested
+	hasGS1FormatHint := getBoolHint(hints, gozxing.EncodeHintType_GS1_FORMAT)
+
+	// Determine if the AIM application indicator is requested
+	applicationIndicator, e := getApplicationIndicatorHint(hints)
+	if e != nil {
+		return nil, nil, nil, nil, e
+	}
+	if hasGS1FormatHint && applicationIndicator >= 0 {
+		return nil, nil, nil, nil, gozxing.NewWriterException(
+			"IllegalArgumentException: GS1_FORMAT and QR_APPLICATION_INDICATOR are mutually exclusive")
+	}
+
+	requestedVersion, e := getVersionHint(hints)
+	if e != nil {
+		return nil, nil, nil, nil, e
+	}
+
+	var mode *decoder.Mode
+	var version *decoder.Version
+	var headerAndDataBits *gozxing.BitArray
+	var data []byte
+
+	if getBoolHint(hints, gozxing.EncodeHintType_QR_COMPACT) {
+		mode = decoder.Mode_BYTE
+
+		var priorityEncoding *common.CharacterSetECI
+		if hasEncodingHint {
+			priorityEncoding, _ = common.GetCharacterSetECI(encoding)
+		}
+		version, headerAndDataBits, data, e = encodeCompact(
+			content, ecLevel, requestedVersion, priorityEncoding, hasGS1FormatHint, applicationIndicator, saHeader)
+		if e != nil {
+			return nil, nil, nil, nil, e
+		}
+	} else {
+		// Pick an encoding mode appropriate for the content. Note that this will not attempt to use
+		// multiple modes / segments even if that were more efficient.
+		// With the application indicator, '%' and GS are escaped in alphanumeric mode.
+		// GS1 contents are written as is, where '%' is read as the GS separator.
+		escapesPercent := applicationIndicator >= 0
+		if escapesPercent && !strings.Contains(content, "\x1d%") && !strings.Contains(content, "\x1d\x1d") {
+			// GS is written as '%' in alphanumeric mode, unless followed by '%' or GS which makes it read as "%"
+			mode = chooseMode(strings.ReplaceAll(content, "\x1d", "%"), encoding)
+		} else {
+			mode = chooseMode(content, encoding)
+		}
+		dataContent := content
+		if escapesPercent && mode == decoder.Mode_ALPHANUMERIC {
+			dataContent = escapeFNC1Alphanumeric(content)
+		}
+
+		// This will store the header information, like mode and
+		// length, as well as "header" segments like an ECI segment.
+		headerBits := gozxing.NewEmptyBitArray()
+
+		// Structured append header must be the first segment of the symbol
+		if saHeader != nil {
+			headerBits.AppendBitArray(saHeader)
+		}
+
+		// Append ECI segment if applicable
+		if mode == decoder.Mode_BYTE && hasEncodingHint {
+			eci, ok := common.GetCharacterSetECI(encoding)
+			if ok && eci != nil {
+				appendECI(eci, headerBits)
 			}
 		}
-		if appendGS1 {
+
+		// Append the FNC1 mode header for GS1 formatted data if applicable
+		if hasGS1FormatHint {
 			// GS1 formatted codes are prefixed with a FNC1 in first position mode header
 			appendModeInfo(decoder.Mode_FNC1_FIRST_POSITION, headerBits)
 		}
-	}
 
-	// (With ECI in place,) Write the mode marker
-	appendModeInfo(mode, headerBits)
+		// Append the FNC1 mode header and the application indicator if applicable
+		if applicationIndicator >= 0 {
+			appendFNC1SecondPositionInfo(applicationIndicator, headerBits)
+		}
 
-	// Collect data within the main segment, separately, to count its size if needed. Don't add it to
-	// main payload yet.
-	dataBits := gozxing.NewEmptyBitArray()
-	e := appendBytes(content, mode, dataBits, encoding)
-	if e != nil {
-		return nil, e
-	}
+		// (With ECI in place,) Write the mode marker
+		appendModeInfo(mode, headerBits)
+		if mode == decoder.Mode_HANZI {
+			appendHanziSubsetInfo(headerBits)
+		}
 
-	var version *decoder.Version
-	if versionHint, ok := hints[gozxing.EncodeHintType_QR_VERSION]; ok {
-		versionNumber, ok := versionHint.(int)
-		if !ok {
-			if s, ok := versionHint.(string); ok {
-				versionNumber, _ = strconv.Atoi(s)
+		// Collect data within the main segment, separately, to count its size if needed. Don't add it to
+		// main payload yet.
+		dataBits := gozxing.NewEmptyBitArray()
+		e = appendBytes(dataContent, mode, dataBits, encoding)
+		if e != nil {
+			return nil, nil, nil, nil, e
+		}
+
+		if requestedVersion != nil {
+			version = requestedVersion
+			bitsNeeded := calculateBitsNeeded(mode, headerBits, dataBits, version)
+			if !willFit(bitsNeeded, version, ecLevel) {
+				return nil, nil, nil, nil, newDataTooBigException("Data too big for requested version")
+			}
+		} else {
+			version, e = recommendVersion(ecLevel, mode, headerBits, dataBits)
+			if e != nil {
+				return nil, nil, nil, nil, e
 			}
 		}
-		var e error
-		version, e = decoder.Version_GetVersionForNumber(versionNumber)
-		if e != nil {
-			return nil, gozxing.WrapWriterException(e)
+
+		headerAndDataBits = gozxing.NewEmptyBitArray()
+		headerAndDataBits.AppendBitArray(headerBits)
+		// Find "length" of main segment and write it
+		numLetters := len(dataContent)
+		if mode == decoder.Mode_BYTE {
+			numLetters = dataBits.GetSizeInBytes()
+		} else if mode == decoder.Mode_KANJI || mode == decoder.Mode_HANZI {
+			numLetters = utf8.RuneCountInString(content)
 		}
-		bitsNeeded := calculateBitsNeeded(mode, headerBits, dataBits, version)
-		if !willFit(bitsNeeded, version, ecLevel) {
-			return nil, gozxing.NewWriterException("Data too big for requested version")
+
+		e = appendLengthInfo(numLetters, version, mode, headerAndDataBits)
+		if e != nil {
+			return nil, nil, nil, nil, e
 		}
-	} else {
-		version, e = recommendVersion(ecLevel, mode, headerBits, dataBits)
+		// Put data together into the overall payload
+		headerAndDataBits.AppendBitArray(dataBits)
+
+		data, e = getDataBytes(content, mode, encoding)
 		if e != nil {
-			return nil, e
+			return nil, nil, nil, nil, e
 		}
 	}
 
-	headerAndDataBits := gozxing.NewEmptyBitArray()
-	headerAndDataBits.AppendBitArray(headerBits)
-	// Find "length" of main segment and write it
-	numLetters := len(content)
-	if mode == decoder.Mode_BYTE {
-		numLetters = dataBits.GetSizeInBytes()
-	} else if mode == decoder.Mode_KANJI {
-		numLetters = utf8.RuneCountInString(content)
-	}
+	return mode, version, headerAndDataBits, data, nil
+}
 
-	e = appendLengthInfo(numLetters, version, mode, headerAndDataBits)
+// getVersionHint returns the version specified by the QR_VERSION hint, or nil if the hint is absent.
+func getVersionHint(hints map[gozxing.EncodeHintType]interface{}) (*decoder.Version, gozxing.WriterException) {
+	versionHint, ok := hints[gozxing.EncodeHintType_QR_VERSION]
+	if !ok {
+		return nil, nil
+	}
+	versionNumber, ok := versionHint.(int)
+	if !ok {
+		if s, ok := versionHint.(string); ok {
+			versionNumber, _ = strconv.Atoi(s)
+		}
+	}
+	version, e := decoder.Version_GetVersionForNumber(versionNumber)
 	if e != nil {
-		return nil, e
+		return nil, gozxing.WrapWriterException(e)
 	}
-	// Put data together into the overall payload
-	headerAndDataBits.AppendBitArray(dataBits)
+	return version, nil
+}
+
+// buildQRCode Terminates the bits, adds the error correction codewords and builds the matrix.
+//
+// @param headerAndDataBits all the segments of the symbol
+// @param mode the mode to be set to the QRCode
+// @param version the version of the symbol
+// @param ecLevel the error correction level
+// @param hints the encode hints (QR_MASK_PATTERN is used)
+// @return the QR Code
+//
+func buildQRCode(headerAndDataBits *gozxing.BitArray, mode *decoder.Mode, version *decoder.Version,
+	ecLevel decoder.ErrorCorrectionLevel, hints map[gozxing.EncodeHintType]interface{}) (*QRCode, gozxing.WriterException) {
 
 	ecBlocks := version.GetECBlocksForLevel(ecLevel)
 	numDataBytes := version.GetTotalCodewords() - ecBlocks.GetTotalECCodewords()
 
 	// Terminate the bits properly.
-	e = terminateBits(numDataBytes, headerAndDataBits)
+	e := terminateBits(numDataBytes, headerAndDataBits)
 	if e != nil {
 		return nil, e
 	}
@@ -195,6 +316,102 @@ func Encoder_encode(content string, ecLevel decoder.ErrorCorrectionLevel, hints
 	return qrCode, nil
 }
 
+// getApplicationIndicatorHint returns the application indicator specified by
+// the QR_APPLICATION_INDICATOR hint, or -1 if the hint is absent.
+func getApplicationIndicatorHint(hints map[gozxing.EncodeHintType]interface{}) (int, gozxing.WriterException) {
+	hint, ok := hints[gozxing.EncodeHintType_QR_APPLICATION_INDICATOR]
+	if !ok {
+		return -1, nil
+	}
+	switch ai := hint.(type) {
+	case int:
+		if ai >= 0 && ai <= 255 {
+			return ai, nil
+		}
+	case string:
+		if len(ai) == 2 && ai[0] >= '0' && ai[0] <= '9' && ai[1] >= '0' && ai[1] <= '9' {
+			// two digits are encoded as the number
+			return int(ai[0]-'0')*10 + int(ai[1]-'0'), nil
+		}
+		if len(ai) == 1 && (ai[0] >= 'a' && ai[0] <= 'z' || ai[0] >= 'A' && ai[0] <= 'Z') {
+			// a letter is encoded as the ASCII value + 100
+			return int(ai[0]) + 100, nil
+		}
+	}
+	return -1, gozxing.NewWriterException(
+		"IllegalArgumentException: Invalid application indicator: %v", hint)
+}
+
+// getBoolHint returns the value of the boolean hint (type bool, or "true" or "false" string).
+func getBoolHint(hints map[gozxing.EncodeHintType]interface{}, hintType gozxing.EncodeHintType) bool {
+	hint, ok := hints[hintType]
+	if !ok {
+		return false
+	}
+	b, ok := hint.(bool)
+	if !ok {
+		if s, ok := hint.(string); ok {
+			b, _ = strconv.ParseBool(s)
+		}
+	}
+	return b
+}
+
+// encodeCompact Encodes the content minimally using multiple segments.
+//
+// @param content the content to encode
+// @param ecLevel the error correction level
+// @param requestedVersion the version to use, or nil to choose the smallest one
+// @param priorityEncoding the preferred character set, or nil
+// @param isGS1 true if FNC1 in first position is to be prepended
+// @param applicationIndicator the application indicator for FNC1 in second position, or -1
+// @param saHeader structured append header written in front of the segments, or nil
+// @return the version, the bits of all the segments and the bytes of the data written in the segments
+//
+func encodeCompact(content string, ecLevel decoder.ErrorCorrectionLevel, requestedVersion *decoder.Version,
+	priorityEncoding *common.CharacterSetECI, isGS1 bool, applicationIndicator int, saHeader *gozxing.BitArray,
+) (*decoder.Version, *gozxing.BitArray, []byte, gozxing.WriterException) {
+
+	minimal := newMinimalEncoder(content, priorityEncoding, isGS1, ecLevel)
+	minimal.applicationIndicator = applicationIndicator
+	rn, e := minimal.encode(requestedVersion)
+	if e != nil {
+		return nil, nil, nil, e
+	}
+	version := rn.GetVersion()
+	if requestedVersion != nil {
+		version = requestedVersion
+		rn.version = version
+	}
+
+	if requestedVersion != nil && !willFit(rn.GetSize(), version, ecLevel) {
+		return nil, nil, nil, newDataTooBigException("Data too big for requested version")
+	}
+	if saHeader != nil {
+		// The minimal encoder does not know about the structured append header, enlarge the version if needed.
+		for !willFit(saHeader.GetSize()+rn.GetSize(), version, ecLevel) {
+			if requestedVersion != nil || version.GetVersionNumber() >= 40 {
+				return nil, nil, nil, newDataTooBigException("Data too big")
+			}
+			version = minimalVersionForNumber(version.GetVersionNumber() + 1)
+			rn.version = version
+		}
+	}
+
+	bits := gozxing.NewEmptyBitArray()
+	if saHeader != nil {
+		bits.AppendBitArray(saHeader)
+	}
+	if e = rn.GetBits(bits); e != nil {
+		return nil, nil, nil, e
+	}
+	data, e := rn.getDataBytes()
+	if e != nil {
+		return nil, nil, nil, e
+	}
+	return version, bits, data, nil
+}
+
 // recommendVersion  Decides the smallest version of QR code that will contain all of the provided data.
 // @throws WriterException if the data cannot fit in any version
 func recommendVersion(ecLevel decoder.ErrorCorrectionLevel, mode *decoder.Mode,
@@ -233,11 +450,16 @@ func getAlphanumericCode(code uint8) int {
 
 // chooseMode Choose the best mode by examining the content. Note that 'encoding' is used as a hint;
 // if it is Shift_JIS, and the input is only double-byte Kanji, then we return {@link Mode#KANJI}.
+// Likewise, if it is GB2312 (GB18030) and the input is only double-byte Hanzi, then we return {@link Mode#HANZI}.
 func chooseMode(content string, encoding textencoding.Encoding) *decoder.Mode {
 	if common.StringUtils_SHIFT_JIS_CHARSET == encoding && isOnlyDoubleByteKanji(content) {
 		// Choose Kanji mode if all input are double-byte characters
 		return decoder.Mode_KANJI
 	}
+	if common.StringUtils_GB2312_CHARSET == encoding && isOnlyDoubleByteHanzi(content) {
+		// Choose Hanzi mode if all input are double-byte GB2312 characters
+		return decoder.Mode_HANZI
+	}
 	hasNumeric := false
 	hasAlphanumeric := false
 	for i := 0; i < len(content); i++ {
@@ -279,6 +501,42 @@ func isOnlyDoubleByteKanji(content string) bool {
 	return true
 }
 
+func isOnlyDoubleByteHanzi(content string) bool {
+	bytes, e := common.StringUtils_GB2312_CHARSET.NewEncoder().Bytes([]byte(content))
+	if e != nil {
+		return false
+	}
+
+	length := len(bytes)
+	if length == 0 || length%2 != 0 {
+		return false
+	}
+	for i := 0; i < length; i += 2 {
+		if hanziSubtractedCode(bytes[i], bytes[i+1]) == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// hanziSubtractedCode returns the code of GB2312 character subtracted the offset of its range,
+// or -1 if the bytes are out of the range of the Hanzi mode.
+func hanziSubtractedCode(byte1, byte2 byte) int {
+	if byte2 < 0xa1 || byte2 > 0xfe {
+		return -1
+	}
+	code := (int(byte1) << 8) | int(byte2)
+	if code >= 0xa1a1 && code <= 0xaafe {
+		// In the 0xA1A1 to 0xAAFE range
+		return code - 0xa1a1
+	}
+	if code >= 0xb0a1 && code <= 0xfafe {
+		// In the 0xB0A1 to 0xFAFE range
+		return code - 0xa6a1
+	}
+	return -1
+}
+
 func chooseMaskPattern(bits *gozxing.BitArray, ecLevel decoder.ErrorCorrectionLevel,
 	version *decoder.Version, matrix *ByteMatrix) (int, gozxing.WriterException) {
 
@@ -306,7 +564,7 @@ func chooseVersion(numInputBits int, ecLevel decoder.ErrorCorrectionLevel) (*dec
 			return version, nil
 		}
 	}
-	return nil, gozxing.NewWriterException("Data too big")
+	return nil, newDataTooBigException("Data too big")
 }
 
 // willFit returns true if the number of input bits will fit in a code with the specified version and
@@ -525,11 +783,31 @@ func appendBytes(content string, mode *decoder.Mode, bits *gozxing.BitArray, enc
 		return append8BitBytes(content, bits, encoding)
 	case decoder.Mode_KANJI:
 		return appendKanjiBytes(content, bits)
+	case decoder.Mode_HANZI:
+		return appendHanziBytes(content, bits)
 	default:
 		return gozxing.NewWriterException("Invalid mode: %v", mode)
 	}
 }
 
+// getDataBytes returns the bytes of the data written in the segment of the mode.
+func getDataBytes(content string, mode *decoder.Mode, encoding textencoding.Encoding) ([]byte, gozxing.WriterException) {
+	switch mode {
+	case decoder.Mode_BYTE:
+	case decoder.Mode_KANJI:
+		encoding = common.StringUtils_SHIFT_JIS_CHARSET
+	case decoder.Mode_HANZI:
+		encoding = common.StringUtils_GB2312_CHARSET
+	default:
+		return []byte(content), nil
+	}
+	bytes, e := encoding.NewEncoder().Bytes([]byte(content))
+	if e != nil {
+		return nil, gozxing.WrapWriterException(e)
+	}
+	return bytes, nil
+}
+
 func appendNumericBytes(content string, bits *gozxing.BitArray) {
 	length := len(content)
 	i := 0
@@ -554,6 +832,12 @@ func appendNumericBytes(content string, bits *gozxing.BitArray) {
 	}
 }
 
+// escapeFNC1Alphanumeric returns the content to be written in alphanumeric mode with FNC1 in second position.
+// The reader reads '%' as GS and "%%" as '%', so '%' is doubled and GS is replaced with '%'.
+func escapeFNC1Alphanumeric(content string) string {
+	return strings.NewReplacer("%", "%%", "\x1d", "%").Replace(content)
+}
+
 func appendAlphanumericBytes(content string, bits *gozxing.BitArray) gozxing.WriterException {
 	length := len(content)
 	i := 0
@@ -623,6 +907,37 @@ func appendKanjiBytes(content string, bits *gozxing.BitArray) gozxing.WriterExce
 	return nil
 }
 
+// appendHanziSubsetInfo Append the subset indicator of Hanzi mode (GB2312).
+func appendHanziSubsetInfo(bits *gozxing.BitArray) {
+	_ = bits.AppendBits(decoder.GB2312_SUBSET, 4)
+}
+
+func appendHanziBytes(content string, bits *gozxing.BitArray) gozxing.WriterException {
+	bytes, e := common.StringUtils_GB2312_CHARSET.NewEncoder().Bytes([]byte(content))
+	if e != nil {
+		return gozxing.WrapWriterException(e)
+	}
+	if len(bytes)%2 != 0 {
+		return gozxing.NewWriterException("Hanzi byte size not even")
+	}
+	maxI := len(bytes) - 1 // bytes.length must be even
+	for i := 0; i < maxI; i += 2 {
+		subtracted := hanziSubtractedCode(bytes[i], bytes[i+1])
+		if subtracted == -1 {
+			return gozxing.NewWriterException("Invalid byte sequence")
+		}
+		encoded := ((subtracted >> 8) * 0x60) + (subtracted & 0xff)
+		_ = bits.AppendBits(encoded, 13)
+	}
+	return nil
+}
+
+// appendFNC1SecondPositionInfo Append FNC1 in second position mode header and the application indicator.
+func appendFNC1SecondPositionInfo(applicationIndicator int, bits *gozxing.BitArray) {
+	appendModeInfo(decoder.Mode_FNC1_SECOND_POSITION, bits)
+	_ = bits.AppendBits(applicationIndicator, 8)
+}
+
 func appendECI(eci *common.CharacterSetECI, bits *gozxing.BitArray) {
 	_ = bits.AppendBits(decoder.Mode_ECI.GetBits(), 4)
 	// This is correct for values up to 127, which is all we need now.
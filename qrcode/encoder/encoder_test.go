@@ -1,14 +1,19 @@
 package encoder
 
 import (
+	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/common"
 	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+	textencoding "golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
 	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
 )
 
 func TestEncoder_calculateMaskPenalty(t *testing.T) {
@@ -91,6 +96,27 @@ func TestEncoder_isOnlyDoubleByteKanji(t *testing.T) {
 	}
 }
 
+func TestEncoder_isOnlyDoubleByteHanzi(t *testing.T) {
+	if isOnlyDoubleByteHanzi("") {
+		t.Fatalf("isOnlyDoubleByteHanzi(\"\") must be false")
+	}
+	if isOnlyDoubleByteHanzi("中文!") {
+		t.Fatalf("isOnlyDoubleByteHanzi(中文!) must be false")
+	}
+	if isOnlyDoubleByteHanzi("€") {
+		t.Fatalf("isOnlyDoubleByteHanzi(€) must be false")
+	}
+	if isOnlyDoubleByteHanzi("丂") {
+		t.Fatalf("isOnlyDoubleByteHanzi(丂) must be false")
+	}
+	if isOnlyDoubleByteHanzi("漢語") {
+		t.Fatalf("isOnlyDoubleByteHanzi(漢語) must be false")
+	}
+	if !isOnlyDoubleByteHanzi("、中文") {
+		t.Fatalf("isOnlyDoubleByteHanzi(、中文) must be true")
+	}
+}
+
 func TestEncoder_chooseMode(t *testing.T) {
 	content := "漢字モード"
 	expect := decoder.Mode_KANJI
@@ -98,6 +124,15 @@ func TestEncoder_chooseMode(t *testing.T) {
 		t.Fatalf("chooseMode(%v, Shift_JIS) = %v mode, expect %v mode", content, m, expect)
 	}
 
+	content = "中文模式"
+	expect = decoder.Mode_HANZI
+	if m := chooseMode(content, simplifiedchinese.GB18030); m != expect {
+		t.Fatalf("chooseMode(%v, GB18030) = %v mode, expect %v mode", content, m, expect)
+	}
+	if m := chooseMode(content, nil); m != decoder.Mode_BYTE {
+		t.Fatalf("chooseMode(%v) = %v mode, expect %v mode", content, m, decoder.Mode_BYTE)
+	}
+
 	content = "12345"
 	expect = decoder.Mode_NUMERIC
 	if m := chooseMode(content, nil); m != expect {
@@ -388,6 +423,43 @@ func TestEncoder_appendKanjiBytes(t *testing.T) {
 	}
 }
 
+func TestEncoder_appendHanziBytes(t *testing.T) {
+	bits := gozxing.NewEmptyBitArray()
+
+	e := appendHanziBytes("中文!", bits)
+	if _, ok := e.(gozxing.WriterException); !ok {
+		t.Fatalf("appendHanziBytes must be WriterException, %T", e)
+	}
+
+	e = appendHanziBytes("丂", bits)
+	if _, ok := e.(gozxing.WriterException); !ok {
+		t.Fatalf("appendHanziBytes must be WriterException, %T", e)
+	}
+
+	e = appendHanziBytes("\xff", bits)
+	if _, ok := e.(gozxing.WriterException); !ok {
+		t.Fatalf("appendHanziBytes must be WriterException, %T", e)
+	}
+
+	bits = gozxing.NewEmptyBitArray()
+	e = appendHanziBytes("、中文", bits)
+	if e != nil {
+		t.Fatalf("appendHanziBytes returns error, %v", e)
+	}
+	// 0xa1a2 - 0xa1a1 = 0x0001, 0x00*0x60 + 0x01 = 0x0001 = 0000000000001
+	// 0xd6d0 - 0xa6a1 = 0x302f, 0x30*0x60 + 0x2f = 0x122f = 1001000101111
+	// 0xcec4 - 0xa6a1 = 0x2823, 0x28*0x60 + 0x23 = 0x0f23 = 0111100100011
+	expects := "000000000000110010001011110111100100011"
+	if r := bits.GetSize(); r != len(expects) {
+		t.Fatalf("appendHanziBytes result size = %v, expect %v", r, len(expects))
+	}
+	for i := 0; i < len(expects); i++ {
+		if r, expect := bits.Get(i), expects[i] == '1'; r != expect {
+			t.Fatalf("appendHanziBytes result[%v] = %v, expect %v", i, r, expect)
+		}
+	}
+}
+
 func TestEncoder_appendLengthInfo(t *testing.T) {
 	// kanji-mode version1: 8bits for length
 	ver, _ := decoder.Version_GetVersionForNumber(1)
@@ -496,6 +568,18 @@ func TestEncoder_encode(t *testing.T) {
 	}
 	testDecode(t, qr, "漢字モード")
 
+	for _, charset := range []string{"GB2312", "GB18030"} {
+		hints = map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_CHARACTER_SET: charset}
+		qr, e = Encoder_encode("中文模式", decoder.ErrorCorrectionLevel_H, hints)
+		if e != nil {
+			t.Fatalf("encode returns error, %v", e)
+		}
+		if r := qr.GetMode(); r != decoder.Mode_HANZI {
+			t.Fatalf("encoded mode = %v, expect %v", r, decoder.Mode_HANZI)
+		}
+		testDecode(t, qr, "中文模式")
+	}
+
 	hints = map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_CHARACTER_SET: "UTF-8"}
 	qr, e = Encoder_encode("8Byteモード", decoder.ErrorCorrectionLevel_M, hints)
 	if e != nil {
@@ -612,3 +696,291 @@ func TestEncoder_encodeFail(t *testing.T) {
 		t.Fatalf("encode must be error")
 	}
 }
+
+func TestEncoder_encodeCompact(t *testing.T) {
+	content := "http://example.com/ABCDEFGHIJ0123456789"
+	hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_COMPACT: true}
+	qr, e := Encoder_encode(content, decoder.ErrorCorrectionLevel_L, hints)
+	if e != nil {
+		t.Fatalf("encode returns error, %v", e)
+	}
+	if r := qr.GetVersion().GetVersionNumber(); r != 3 {
+		t.Fatalf("encoded version = %v, expect 3", r)
+	}
+	testDecode(t, qr, content)
+
+	content = "serial:" + strings.Repeat("0123456789", 6)
+	qr, e = Encoder_encode(content, decoder.ErrorCorrectionLevel_L, hints)
+	if e != nil {
+		t.Fatalf("encode returns error, %v", e)
+	}
+	testDecode(t, qr, content)
+	normal, _ := Encoder_encode(content, decoder.ErrorCorrectionLevel_L, nil)
+	if r, n := qr.GetVersion().GetVersionNumber(), normal.GetVersion().GetVersionNumber(); r >= n {
+		t.Fatalf("compact version = %v, must be smaller than %v", r, n)
+	}
+
+	contents := []string{
+		"AAAAAAa",
+		"ŐŐŜ",
+		"abc漢字123456789012345",
+		"Ελληνικά English 日本語 🍣",
+		"ßßéßé",
+		"ßßéßéßßéßé漢字漢字ÀÉÎÕÜ",
+		"日本語のテキストとFrançais àâæçéèêëîïôœùûüÿ",
+	}
+	for _, content := range contents {
+		qr, e = Encoder_encode(content, decoder.ErrorCorrectionLevel_M, hints)
+		if e != nil {
+			t.Fatalf("encode(%q) returns error, %v", content, e)
+		}
+		testDecode(t, qr, content)
+	}
+
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_COMPACT:    "true",
+		gozxing.EncodeHintType_CHARACTER_SET: "UTF-8",
+		gozxing.EncodeHintType_QR_VERSION:    5,
+	}
+	qr, e = Encoder_encode("ŐŐŜ", decoder.ErrorCorrectionLevel_M, hints)
+	if e != nil {
+		t.Fatalf("encode returns error, %v", e)
+	}
+	if r := qr.GetVersion().GetVersionNumber(); r != 5 {
+		t.Fatalf("encoded version = %v, expect 5", r)
+	}
+	testDecode(t, qr, "ŐŐŜ")
+
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_COMPACT: true,
+		gozxing.EncodeHintType_GS1_FORMAT: true,
+	}
+	qr, e = Encoder_encode("01049123451234591597033130128%10ABC123", decoder.ErrorCorrectionLevel_Q, hints)
+	if e != nil {
+		t.Fatalf("encode returns error, %v", e)
+	}
+	testDecode(t, qr, "01049123451234591597033130128\x1d10ABC123")
+
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_COMPACT: true,
+		gozxing.EncodeHintType_QR_VERSION: 1,
+	}
+	_, e = Encoder_encode("01234567890123456789012345678901234567890", decoder.ErrorCorrectionLevel_L, hints)
+	if e != nil {
+		t.Fatalf("encode returns error, %v", e)
+	}
+	_, e = Encoder_encode("012345678901234567890123456789012345678901", decoder.ErrorCorrectionLevel_L, hints)
+	if e == nil {
+		t.Fatalf("encode must be error")
+	}
+
+	hints = map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_COMPACT: true}
+	_, e = Encoder_encode(strings.Repeat("0123456789", 800), decoder.ErrorCorrectionLevel_L, hints)
+	if e == nil {
+		t.Fatalf("encode must be error")
+	}
+}
+
+func TestEncoder_encodeCompactNotLarger(t *testing.T) {
+	chars := []rune("0123456789ABCDEFabcdef $%:/ßéÀŐŜΕλ漢字日本🍣")
+	hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_COMPACT: true}
+	random := rand.New(rand.NewSource(0))
+	for i := 0; i < 300; i++ {
+		content := make([]rune, 1+random.Intn(40))
+		for j := range content {
+			content[j] = chars[random.Intn(len(chars))]
+		}
+		contents := string(content)
+
+		normal, e := Encoder_encode(contents, decoder.ErrorCorrectionLevel_L, nil)
+		if e != nil {
+			t.Fatalf("encode(%q) returns error, %v", contents, e)
+		}
+		compact, e := Encoder_encode(contents, decoder.ErrorCorrectionLevel_L, hints)
+		if e != nil {
+			t.Fatalf("encode(%q) compact returns error, %v", contents, e)
+		}
+		if r, n := compact.GetVersion().GetVersionNumber(), normal.GetVersion().GetVersionNumber(); r > n {
+			t.Fatalf("encode(%q) compact version = %v, must not be larger than %v", contents, r, n)
+		}
+		testDecode(t, compact, contents)
+	}
+}
+
+func TestEncoder_encodeCompactStructuredAppend(t *testing.T) {
+	header := gozxing.NewEmptyBitArray()
+	appendStructuredAppendHeader(0, 2, 0, header)
+
+	// 41 digits fill version 1-L exactly, the structured append header requires version 2
+	content := "01234567890123456789012345678901234567890"
+	version, bits, data, e := encodeCompact(content, decoder.ErrorCorrectionLevel_L, nil, nil, false, -1, header)
+	if e != nil {
+		t.Fatalf("encodeCompact returns error: %v", e)
+	}
+	if r := version.GetVersionNumber(); r != 2 {
+		t.Fatalf("encodeCompact version = %v, expect 2", r)
+	}
+	if r, expect := bits.GetSize(), 20+4+10+13*10+7; r != expect {
+		t.Fatalf("encodeCompact bits = %v, expect %v", r, expect)
+	}
+	if string(data) != content {
+		t.Fatalf("encodeCompact data = %q, expect %q", data, content)
+	}
+
+	version, _ = decoder.Version_GetVersionForNumber(1)
+	_, _, _, e = encodeCompact(content, decoder.ErrorCorrectionLevel_L, version, nil, false, -1, header)
+	if _, ok := e.(dataTooBigException); !ok {
+		t.Fatalf("encodeCompact must be dataTooBigException, %T", e)
+	}
+}
+
+func TestGetDataBytes(t *testing.T) {
+	tests := []struct {
+		content  string
+		mode     *decoder.Mode
+		encoding textencoding.Encoding
+		expect   []byte
+	}{
+		{"0123", decoder.Mode_NUMERIC, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), []byte("0123")},
+		{"AB%", decoder.Mode_ALPHANUMERIC, unicode.UTF8, []byte("AB%")},
+		{"ß", decoder.Mode_BYTE, unicode.UTF8, []byte{0xc3, 0x9f}},
+		{"ß", decoder.Mode_BYTE, charmap.ISO8859_1, []byte{0xdf}},
+		{"点", decoder.Mode_KANJI, unicode.UTF8, []byte{0x93, 0x5f}},
+		{"点", decoder.Mode_HANZI, unicode.UTF8, []byte{0xb5, 0xe3}},
+	}
+	for _, test := range tests {
+		r, e := getDataBytes(test.content, test.mode, test.encoding)
+		if e != nil {
+			t.Fatalf("getDataBytes(%q, %v) returns error: %v", test.content, test.mode, e)
+		}
+		if !reflect.DeepEqual(r, test.expect) {
+			t.Fatalf("getDataBytes(%q, %v) = %x, expect %x", test.content, test.mode, r, test.expect)
+		}
+	}
+
+	_, e := getDataBytes("点", decoder.Mode_BYTE, charmap.ISO8859_1)
+	if e == nil {
+		t.Fatalf("getDataBytes must be error")
+	}
+}
+
+func TestEncoder_encodeFNC1Percent(t *testing.T) {
+	contents := []string{
+		"AB%CD",
+		"AB\x1dCD",
+		"100%",
+		"%%%",
+		"%\x1d%",
+		"10ABC%\x1d21XYZ",
+		"abc%def\x1dghi",
+		"A%Bc%D1234567890%%",
+	}
+	hintsList := []map[gozxing.EncodeHintType]interface{}{
+		{gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: 37},
+		{gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: 37, gozxing.EncodeHintType_QR_COMPACT: true},
+	}
+	chars := []rune("AB12%%\x1d\x1da")
+	random := rand.New(rand.NewSource(0))
+	for i := 0; i < 100; i++ {
+		content := make([]rune, 1+random.Intn(20))
+		for j := range content {
+			content[j] = chars[random.Intn(len(chars))]
+		}
+		contents = append(contents, string(content))
+	}
+	for _, hints := range hintsList {
+		for _, content := range contents {
+			qr, e := Encoder_encode(content, decoder.ErrorCorrectionLevel_L, hints)
+			if e != nil {
+				t.Fatalf("encode(%q, %v) returns error, %v", content, hints, e)
+			}
+			testDecode(t, qr, content)
+		}
+	}
+
+	hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: 37}
+	qr, _ := Encoder_encode("10ABC%\x1d21XYZ", decoder.ErrorCorrectionLevel_L, hints)
+	if r := qr.GetMode(); r != decoder.Mode_ALPHANUMERIC {
+		t.Fatalf("encoded mode = %v, expect %v", r, decoder.Mode_ALPHANUMERIC)
+	}
+
+	// GS1 contents are not escaped, '%' is the GS separator
+	for _, compact := range []bool{false, true} {
+		hints = map[gozxing.EncodeHintType]interface{}{
+			gozxing.EncodeHintType_GS1_FORMAT: true,
+			gozxing.EncodeHintType_QR_COMPACT: compact,
+		}
+		qr, e := Encoder_encode("10ABC123%21XYZ", decoder.ErrorCorrectionLevel_L, hints)
+		if e != nil {
+			t.Fatalf("encode returns error, %v", e)
+		}
+		testDecode(t, qr, "10ABC123\x1d21XYZ")
+	}
+}
+
+func TestEncoder_getApplicationIndicatorHint(t *testing.T) {
+	tests := []struct {
+		hint   interface{}
+		expect int
+	}{
+		{0, 0},
+		{37, 37},
+		{255, 255},
+		{"00", 0},
+		{"37", 37},
+		{"A", 165},
+		{"z", 222},
+	}
+	for _, test := range tests {
+		hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: test.hint}
+		ai, e := getApplicationIndicatorHint(hints)
+		if e != nil {
+			t.Fatalf("getApplicationIndicatorHint(%v) returns error: %v", test.hint, e)
+		}
+		if ai != test.expect {
+			t.Fatalf("getApplicationIndicatorHint(%v) = %v, expect %v", test.hint, ai, test.expect)
+		}
+	}
+
+	if ai, e := getApplicationIndicatorHint(nil); e != nil || ai != -1 {
+		t.Fatalf("getApplicationIndicatorHint(nil) = %v, %v, expect -1, nil", ai, e)
+	}
+
+	for _, hint := range []interface{}{-1, 256, "3", "123", "3A", "!", 1.5} {
+		hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: hint}
+		if _, e := getApplicationIndicatorHint(hints); e == nil {
+			t.Fatalf("getApplicationIndicatorHint(%v) must be error", hint)
+		}
+	}
+}
+
+func TestEncoder_encodeFNC1SecondPosition(t *testing.T) {
+	hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: "37"}
+	qr, e := Encoder_encode("AB%CD", decoder.ErrorCorrectionLevel_L, hints)
+	if e != nil {
+		t.Fatalf("encode returns error, %v", e)
+	}
+	testDecode(t, qr, "AB%CD")
+
+	hints[gozxing.EncodeHintType_QR_COMPACT] = true
+	qr, e = Encoder_encode("ŐŜ", decoder.ErrorCorrectionLevel_L, hints)
+	if e != nil {
+		t.Fatalf("encode returns error, %v", e)
+	}
+	testDecode(t, qr, "ŐŜ")
+
+	hints[gozxing.EncodeHintType_QR_APPLICATION_INDICATOR] = "37A"
+	_, e = Encoder_encode("AB%CD", decoder.ErrorCorrectionLevel_L, hints)
+	if e == nil {
+		t.Fatalf("encode must be error")
+	}
+
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: 37,
+		gozxing.EncodeHintType_GS1_FORMAT:               true,
+	}
+	_, e = Encoder_encode("AB%CD", decoder.ErrorCorrectionLevel_L, hints)
+	if e == nil {
+		t.Fatalf("encode must be error")
+	}
+}
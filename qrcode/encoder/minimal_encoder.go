@@ -0,0 +1,738 @@
+package encoder
+
+import (
+	"math"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+// Encoder that encodes minimally
+//
+// Algorithm:
+//
+// The eleventh commandment was "Thou Shalt Compute" or "Thou Shalt Not Compute" - I forget which (Alan Perilis).
+//
+// This implementation computes. As an alternative, the QR-Code specification suggests heuristics like this one:
+//
+// If initial input data is in the exclusive subset of the Alphanumeric character set AND if there are less than
+// [6,7,8] characters followed by data from the remainder of the 8-bit byte character set, THEN select the 8-
+// bit byte mode ELSE select Alphanumeric mode;
+//
+// This is probably right for 99.99% of cases but there is at least this one counter example: The string "AAAAAAa"
+// encodes 2 bits smaller as ALPHANUMERIC(AAAAAA), BYTE(a) than by encoding it as BYTE(AAAAAAa).
+// Perhaps that is the only counter example but without having proof, it remains unclear.
+//
+// ECI switching:
+//
+// In multi language content the algorithm selects the most compact representation using ECI modes.
+// For example the most compact representation of the string "ŐŜ" (O-double-acute, S-circumflex) is
+// ECI(UTF-8), BYTE(ŐŜ) while prepending one or more times the same leading character as in
+// "ŐŐŜ", the most compact representation uses two ECIs so that the string is encoded as
+// ECI(ISO-8859-2), BYTE(ŐŐ), ECI(ISO-8859-3), BYTE(Ŝ).
+
+type minimalVersionSize int
+
+const (
+	minimalVersionSize_SMALL  = minimalVersionSize(iota) // version 1-9
+	minimalVersionSize_MEDIUM                            // version 10-26
+	minimalVersionSize_LARGE                             // version 27-40
+)
+
+type minimalEncoder struct {
+	stringToEncode       []rune
+	isGS1                bool
+	applicationIndicator int // FNC1 in second position is prepended if not -1
+	encoders             *common.ECIEncoderSet
+	defaultEncoderIndex  int // the encoder of the charset which the reader assumes without ECI
+	ecLevel              decoder.ErrorCorrectionLevel
+}
+
+// newMinimalEncoder Creates a MinimalEncoder
+//
+// @param stringToEncode The string to encode
+// @param priorityCharset The preferred charset. When the value of the argument is nil, the algorithm
+// chooses charsets that leads to a minimal representation. Otherwise the algorithm will use the priority
+// charset to encode any character in the input that can be encoded by it if the charset is among the
+// supported charsets.
+// @param isGS1 true if a FNC1 is to be prepended; false otherwise
+// @param ecLevel The error correction level.
+//
+func newMinimalEncoder(stringToEncode string, priorityCharset *common.CharacterSetECI, isGS1 bool,
+	ecLevel decoder.ErrorCorrectionLevel) *minimalEncoder {
+	// The byte segments without ECI are written in UTF-8 as Encoder_DEFAULT_BYTE_MODE_ENCODING,
+	// so ISO-8859-1 is also designated by ECI when the content contains any non-ASCII character.
+	encoders := common.NewECIEncoderSetWithUnicode(stringToEncode, priorityCharset, -1)
+	defaultEncoderIndex := 0
+	for i := 0; i < encoders.Length(); i++ {
+		if encoders.GetCharset(i) == common.CharacterSetECI_UTF8 {
+			defaultEncoderIndex = i
+			break
+		}
+	}
+	return &minimalEncoder{
+		stringToEncode:       []rune(stringToEncode),
+		isGS1:                isGS1,
+		applicationIndicator: -1,
+		encoders:             encoders,
+		defaultEncoderIndex:  defaultEncoderIndex,
+		ecLevel:              ecLevel,
+	}
+}
+
+// MinimalEncoder_encode Encodes the string minimally
+//
+// @param stringToEncode The string to encode
+// @param version The preferred version or nil to compute the smallest possible version
+// @param priorityCharset The preferred charset or nil
+// @param isGS1 true if a FNC1 is to be prepended; false otherwise
+// @param ecLevel The error correction level.
+// @return An instance of minimalResultList representing the minimal solution.
+// @throws WriterException if the content does not fit in the symbol.
+//
+func MinimalEncoder_encode(stringToEncode string, version *decoder.Version, priorityCharset *common.CharacterSetECI,
+	isGS1 bool, ecLevel decoder.ErrorCorrectionLevel) (*minimalResultList, gozxing.WriterException) {
+	return newMinimalEncoder(stringToEncode, priorityCharset, isGS1, ecLevel).encode(version)
+}
+
+func (this *minimalEncoder) encode(version *decoder.Version) (*minimalResultList, gozxing.WriterException) {
+	if version == nil { // compute minimal encoding trying the three version sizes.
+		versions := []*decoder.Version{
+			minimalGetVersion(minimalVersionSize_SMALL),
+			minimalGetVersion(minimalVersionSize_MEDIUM),
+			minimalGetVersion(minimalVersionSize_LARGE),
+		}
+		smallestSize := math.MaxInt32
+		var smallestResult *minimalResultList
+		for _, v := range versions {
+			result, e := this.encodeSpecificVersion(v)
+			if e != nil {
+				return nil, e
+			}
+			size := result.GetSize()
+			if willFit(size, v, this.ecLevel) && size < smallestSize {
+				smallestSize = size
+				smallestResult = result
+			}
+		}
+		if smallestResult == nil {
+			return nil, newDataTooBigException("Data too big for any version")
+		}
+		return smallestResult, nil
+	}
+
+	// compute minimal encoding for a given version
+	result, e := this.encodeSpecificVersion(version)
+	if e != nil {
+		return nil, e
+	}
+	if !willFit(result.GetSize(), minimalGetVersion(minimalGetVersionSize(result.GetVersion())), this.ecLevel) {
+		return nil, newDataTooBigException("Data too big for version %v", version)
+	}
+	return result, nil
+}
+
+func minimalGetVersionSize(version *decoder.Version) minimalVersionSize {
+	if version.GetVersionNumber() <= 9 {
+		return minimalVersionSize_SMALL
+	}
+	if version.GetVersionNumber() <= 26 {
+		return minimalVersionSize_MEDIUM
+	}
+	return minimalVersionSize_LARGE
+}
+
+func minimalGetVersion(versionSize minimalVersionSize) *decoder.Version {
+	var version *decoder.Version
+	switch versionSize {
+	case minimalVersionSize_SMALL:
+		version, _ = decoder.Version_GetVersionForNumber(9)
+	case minimalVersionSize_MEDIUM:
+		version, _ = decoder.Version_GetVersionForNumber(26)
+	default:
+		version, _ = decoder.Version_GetVersionForNumber(40)
+	}
+	return version
+}
+
+func minimalIsNumeric(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+func minimalIsDoubleByteKanji(c rune) bool {
+	return isOnlyDoubleByteKanji(string(c))
+}
+
+func minimalIsAlphanumeric(c rune) bool {
+	return c < 0x80 && getAlphanumericCode(uint8(c)) != -1
+}
+
+func minimalCanEncode(mode *decoder.Mode, c rune) bool {
+	switch mode {
+	case decoder.Mode_KANJI:
+		return minimalIsDoubleByteKanji(c)
+	case decoder.Mode_ALPHANUMERIC:
+		return minimalIsAlphanumeric(c)
+	case decoder.Mode_NUMERIC:
+		return minimalIsNumeric(c)
+	case decoder.Mode_BYTE:
+		// any character can be encoded as byte(s). Up to the caller to manage splitting into
+		// multiple bytes when the character is encoded to more than one byte.
+		return true
+	default:
+		return false
+	}
+}
+
+// canEncodeAlphanumeric returns true if the character at the position can be encoded in alphanumeric mode.
+// With the application indicator, GS is encoded as '%' unless it is followed by '%' or GS, since "%%" is read as '%'.
+func (this *minimalEncoder) canEncodeAlphanumeric(position int) bool {
+	c := this.stringToEncode[position]
+	if c == 0x1d {
+		if position+1 < len(this.stringToEncode) {
+			if next := this.stringToEncode[position+1]; next == '%' || next == 0x1d {
+				return false
+			}
+		}
+		return this.escapesPercent()
+	}
+	return minimalIsAlphanumeric(c)
+}
+
+// isDoubledPercent returns true if the character is '%' which is written as "%%" in alphanumeric mode.
+func (this *minimalEncoder) isDoubledPercent(c rune) bool {
+	return c == '%' && this.escapesPercent()
+}
+
+// escapesPercent returns true if '%' and GS are escaped in alphanumeric mode, that is FNC1 in second position.
+// With GS1 (FNC1 in first position), '%' in the content is written as is and read as the GS separator.
+func (this *minimalEncoder) escapesPercent() bool {
+	return this.applicationIndicator >= 0
+}
+
+func minimalGetCompactedOrdinal(mode *decoder.Mode) int {
+	switch mode {
+	case decoder.Mode_KANJI:
+		return 0
+	case decoder.Mode_ALPHANUMERIC:
+		return 1
+	case decoder.Mode_NUMERIC:
+		return 2
+	case decoder.Mode_BYTE:
+		return 3
+	default:
+		panic("IllegalStateException: Illegal mode " + mode.String())
+	}
+}
+
+func (this *minimalEncoder) addEdge(edges [][][]*minimalEdge, position int, edge *minimalEdge) {
+	vertexIndex := position + edge.characterLength
+	modeEdges := edges[vertexIndex][edge.charsetEncoderIndex]
+	modeOrdinal := minimalGetCompactedOrdinal(edge.mode)
+	if modeEdges[modeOrdinal] == nil || modeEdges[modeOrdinal].cachedTotalSize > edge.cachedTotalSize {
+		modeEdges[modeOrdinal] = edge
+	}
+}
+
+func (this *minimalEncoder) addEdges(version *decoder.Version, edges [][][]*minimalEdge, from int,
+	previous *minimalEdge) gozxing.WriterException {
+
+	c := this.stringToEncode[from]
+	start := 0
+	end := this.encoders.Length()
+	priorityEncoderIndex := this.encoders.GetPriorityEncoderIndex()
+	if priorityEncoderIndex >= 0 && this.encoders.CanEncode(c, priorityEncoderIndex) {
+		start = priorityEncoderIndex
+		end = priorityEncoderIndex + 1
+	}
+
+	for i := start; i < end; i++ {
+		if this.encoders.CanEncode(c, i) {
+			edge, e := this.newMinimalEdge(decoder.Mode_BYTE, from, i, 1, previous, version)
+			if e != nil {
+				return e
+			}
+			this.addEdge(edges, from, edge)
+		}
+	}
+
+	if minimalCanEncode(decoder.Mode_KANJI, c) {
+		edge, _ := this.newMinimalEdge(decoder.Mode_KANJI, from, 0, 1, previous, version)
+		this.addEdge(edges, from, edge)
+	}
+
+	inputLength := len(this.stringToEncode)
+	if this.canEncodeAlphanumeric(from) {
+		// a doubled '%' occupies a pair of alphanumeric characters by itself
+		length := 2
+		if from+1 >= inputLength || !this.canEncodeAlphanumeric(from+1) ||
+			this.isDoubledPercent(c) || this.isDoubledPercent(this.stringToEncode[from+1]) {
+			length = 1
+		}
+		edge, _ := this.newMinimalEdge(decoder.Mode_ALPHANUMERIC, from, 0, length, previous, version)
+		this.addEdge(edges, from, edge)
+	}
+
+	if minimalCanEncode(decoder.Mode_NUMERIC, c) {
+		length := 3
+		if from+1 >= inputLength || !minimalCanEncode(decoder.Mode_NUMERIC, this.stringToEncode[from+1]) {
+			length = 1
+		} else if from+2 >= inputLength || !minimalCanEncode(decoder.Mode_NUMERIC, this.stringToEncode[from+2]) {
+			length = 2
+		}
+		edge, _ := this.newMinimalEdge(decoder.Mode_NUMERIC, from, 0, length, previous, version)
+		this.addEdge(edges, from, edge)
+	}
+	return nil
+}
+
+func (this *minimalEncoder) encodeSpecificVersion(version *decoder.Version) (*minimalResultList, gozxing.WriterException) {
+
+	// A vertex represents a tuple of a position in the input, a mode and a character encoding where position 0
+	// denotes the position left of the first character, 1 the position left of the second character and so on.
+	// Likewise the mode and the character encoding are the modes and encodings *left* of the position.
+	//
+	// An edge leading to such a vertex encodes one or more of the characters left of the position that the vertex
+	// represents and encodes it in the same encoding and mode as the vertex on which the edge ends. In other words,
+	// all edges leading to a particular vertex encode the same characters in the same mode with the same character
+	// encoding. They differ only by their source vertices who are all located at i+1 minus the number of encoded
+	// characters.
+	//
+	// The edges leading to a vertex are stored in such a way that there is a fast way to enumerate the edges ending
+	// on a particular vertex.
+	//
+	// The algorithm processes the vertices in order of their position thereby performing the following:
+	//
+	// For every vertex at position i the algorithm enumerates the edges ending on the vertex and removes all but the
+	// shortest from that list.
+	// Then it processes the vertices for the position i+1. If i+1 == inputLength then the algorithm ends
+	// and chooses the the edge with the smallest size from any of the edges leading to vertices at this position.
+	// Otherwise the algorithm computes all possible outgoing edges for the vertices at the position i+1
+	//
+	// Examples:
+	// The process is illustrated by showing the graph (edges) after each iteration from left to right over the input:
+	// An edge is drawn as follows "(" + fromVertex + ") -- " + encodingMode + "(" + encodedInput + ") (" +
+	// accumulatedSize + ") --> (" + toVertex + ")"
+	//
+	// The minimal solution is the path from the vertex at position 0 to a vertex at the last position
+	// with the smallest accumulated size.
+
+	inputLength := len(this.stringToEncode)
+
+	// Array that represents vertices. There is a vertex for every character, encoding and mode. The vertex contains
+	// a list of all edges that lead to it that have the same encoding and mode.
+	// The lists are created lazily
+
+	// The last dimension in the array below encodes the 4 modes KANJI, ALPHANUMERIC, NUMERIC and BYTE via the
+	// function minimalGetCompactedOrdinal(Mode)
+	edges := make([][][]*minimalEdge, inputLength+1)
+	for i := range edges {
+		edges[i] = make([][]*minimalEdge, this.encoders.Length())
+		for j := range edges[i] {
+			edges[i][j] = make([]*minimalEdge, 4)
+		}
+	}
+	if inputLength > 0 {
+		if e := this.addEdges(version, edges, 0, nil); e != nil {
+			return nil, e
+		}
+	}
+
+	for i := 1; i <= inputLength; i++ {
+		for j := 0; j < this.encoders.Length(); j++ {
+			for k := 0; k < 4; k++ {
+				if edges[i][j][k] != nil && i < inputLength {
+					if e := this.addEdges(version, edges, i, edges[i][j][k]); e != nil {
+						return nil, e
+					}
+				}
+			}
+		}
+	}
+
+	minimalJ := -1
+	minimalK := -1
+	minimalSize := math.MaxInt32
+	for j := 0; j < this.encoders.Length(); j++ {
+		for k := 0; k < 4; k++ {
+			if edge := edges[inputLength][j][k]; edge != nil {
+				if edge.cachedTotalSize < minimalSize {
+					minimalSize = edge.cachedTotalSize
+					minimalJ = j
+					minimalK = k
+				}
+			}
+		}
+	}
+	if inputLength > 0 && minimalJ < 0 {
+		return nil, gozxing.NewWriterException(
+			"Internal error: failed to encode \"%v\"", string(this.stringToEncode))
+	}
+	var solution *minimalEdge
+	if minimalJ >= 0 {
+		solution = edges[inputLength][minimalJ][minimalK]
+	}
+	return this.newMinimalResultList(version, solution)
+}
+
+type minimalEdge struct {
+	mode                *decoder.Mode
+	fromPosition        int
+	charsetEncoderIndex int
+	characterLength     int
+	previous            *minimalEdge
+	cachedTotalSize     int
+}
+
+func (this *minimalEncoder) newMinimalEdge(mode *decoder.Mode, fromPosition, charsetEncoderIndex, characterLength int,
+	previous *minimalEdge, version *decoder.Version) (*minimalEdge, gozxing.WriterException) {
+
+	edge := &minimalEdge{
+		mode:                mode,
+		fromPosition:        fromPosition,
+		charsetEncoderIndex: charsetEncoderIndex,
+		characterLength:     characterLength,
+		previous:            previous,
+	}
+	if mode != decoder.Mode_BYTE {
+		// inherit the encoding if not of type BYTE
+		if previous != nil {
+			edge.charsetEncoderIndex = previous.charsetEncoderIndex
+		} else {
+			edge.charsetEncoderIndex = this.defaultEncoderIndex
+		}
+	}
+
+	size := 0
+	if previous != nil {
+		size = previous.cachedTotalSize
+	}
+
+	needECI := this.needECI(mode, edge.charsetEncoderIndex, previous)
+
+	if previous == nil || mode != previous.mode || needECI {
+		size += 4 + mode.GetCharacterCountBits(version)
+	}
+	switch mode {
+	case decoder.Mode_KANJI:
+		size += 13
+	case decoder.Mode_ALPHANUMERIC:
+		if characterLength == 1 && !this.isDoubledPercent(this.stringToEncode[fromPosition]) {
+			size += 6
+		} else {
+			size += 11
+		}
+	case decoder.Mode_NUMERIC:
+		if characterLength == 1 {
+			size += 4
+		} else if characterLength == 2 {
+			size += 7
+		} else {
+			size += 10
+		}
+	case decoder.Mode_BYTE:
+		bytes, e := this.encoders.Encode(
+			string(this.stringToEncode[fromPosition:fromPosition+characterLength]), edge.charsetEncoderIndex)
+		if e != nil {
+			return nil, gozxing.WrapWriterException(e)
+		}
+		size += 8 * len(bytes)
+		if needECI {
+			size += 4 + 8 // the ECI assignment numbers for ISO-8859-x, UTF-8 and UTF-16 are all 8 bit long
+		}
+	}
+	edge.cachedTotalSize = size
+	return edge, nil
+}
+
+// needECI returns true if the BYTE edge requires an ECI, that is
+// the edge is at the beginning and the charset is not the default one, or the charset is changed.
+func (this *minimalEncoder) needECI(mode *decoder.Mode, charsetEncoderIndex int, previous *minimalEdge) bool {
+	if mode != decoder.Mode_BYTE {
+		return false
+	}
+	if previous == nil {
+		return charsetEncoderIndex != this.defaultEncoderIndex
+	}
+	return charsetEncoderIndex != previous.charsetEncoderIndex
+}
+
+type minimalResultList struct {
+	encoder *minimalEncoder
+	list    []*minimalResultNode
+	version *decoder.Version
+}
+
+func (this *minimalEncoder) newMinimalResultList(version *decoder.Version, solution *minimalEdge) (*minimalResultList, gozxing.WriterException) {
+	rl := &minimalResultList{
+		encoder: this,
+	}
+
+	length := 0
+	current := solution
+	containsECI := false
+
+	for current != nil {
+		length += current.characterLength
+		previous := current.previous
+
+		needECI := this.needECI(current.mode, current.charsetEncoderIndex, previous)
+		if needECI {
+			containsECI = true
+		}
+
+		if previous == nil || previous.mode != current.mode || needECI {
+			rl.prepend(rl.newNode(current.mode, current.fromPosition, current.charsetEncoderIndex, length))
+			length = 0
+		}
+
+		if needECI {
+			rl.prepend(rl.newNode(decoder.Mode_ECI, current.fromPosition, current.charsetEncoderIndex, 0))
+		}
+		current = previous
+	}
+
+	// prepend FNC1 if needed. If the bits contain an ECI then the FNC1 must be preceeded by an ECI.
+	// If there is no ECI at the beginning then we put an ECI to the default charset
+	if this.isGS1 || this.applicationIndicator >= 0 {
+		if len(rl.list) > 0 && rl.list[0].mode != decoder.Mode_ECI && containsECI {
+			// prepend a default character set ECI
+			rl.prepend(rl.newNode(decoder.Mode_ECI, 0, this.defaultEncoderIndex, 0))
+		}
+		// prepend or insert a FNC1_FIRST_POSITION (or FNC1_SECOND_POSITION) after the ECI (if any)
+		fnc1 := rl.newNode(decoder.Mode_FNC1_FIRST_POSITION, 0, 0, 0)
+		if !this.isGS1 {
+			fnc1 = rl.newNode(decoder.Mode_FNC1_SECOND_POSITION, 0, 0, 0)
+		}
+		if len(rl.list) > 0 && rl.list[0].mode == decoder.Mode_ECI {
+			rl.list = append(rl.list[:1], append([]*minimalResultNode{fnc1}, rl.list[1:]...)...)
+		} else {
+			rl.prepend(fnc1)
+		}
+	}
+
+	// set version to smallest version into which the bits fit.
+	versionNumber := version.GetVersionNumber()
+	var lowerLimit, upperLimit int
+	switch minimalGetVersionSize(version) {
+	case minimalVersionSize_SMALL:
+		lowerLimit, upperLimit = 1, 9
+	case minimalVersionSize_MEDIUM:
+		lowerLimit, upperLimit = 10, 26
+	default:
+		lowerLimit, upperLimit = 27, 40
+	}
+	size, e := rl.getSize(version)
+	if e != nil {
+		return nil, e
+	}
+	// increase version if needed
+	for versionNumber < upperLimit && !willFit(size, minimalVersionForNumber(versionNumber), this.ecLevel) {
+		versionNumber++
+	}
+	// shrink version if possible
+	for versionNumber > lowerLimit && willFit(size, minimalVersionForNumber(versionNumber-1), this.ecLevel) {
+		versionNumber--
+	}
+	rl.version = minimalVersionForNumber(versionNumber)
+	return rl, nil
+}
+
+func minimalVersionForNumber(versionNumber int) *decoder.Version {
+	version, _ := decoder.Version_GetVersionForNumber(versionNumber)
+	return version
+}
+
+func (this *minimalResultList) prepend(node *minimalResultNode) {
+	this.list = append([]*minimalResultNode{node}, this.list...)
+}
+
+// GetSize returns the size in bits
+func (this *minimalResultList) GetSize() int {
+	size, _ := this.getSize(this.version)
+	return size
+}
+
+func (this *minimalResultList) getSize(version *decoder.Version) (int, gozxing.WriterException) {
+	result := 0
+	for _, resultNode := range this.list {
+		size, e := resultNode.getSize(version)
+		if e != nil {
+			return 0, e
+		}
+		result += size
+	}
+	return result, nil
+}
+
+// GetBits appends the bits
+func (this *minimalResultList) GetBits(bits *gozxing.BitArray) gozxing.WriterException {
+	for _, resultNode := range this.list {
+		if e := resultNode.getBits(bits); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// getDataBytes returns the bytes of the data written in the segments
+func (this *minimalResultList) getDataBytes() ([]byte, gozxing.WriterException) {
+	var data []byte
+	for _, resultNode := range this.list {
+		if resultNode.characterLength == 0 {
+			continue
+		}
+		encoding := this.encoder.encoders.GetCharset(resultNode.charsetEncoderIndex).GetCharset()
+		bytes, e := getDataBytes(resultNode.content(), resultNode.mode, encoding)
+		if e != nil {
+			return nil, e
+		}
+		data = append(data, bytes...)
+	}
+	return data, nil
+}
+
+func (this *minimalResultList) GetVersion() *decoder.Version {
+	return this.version
+}
+
+func (this *minimalResultList) String() string {
+	result := ""
+	var previous *minimalResultNode
+	for _, current := range this.list {
+		if previous != nil {
+			result += ","
+		}
+		result += current.String()
+		previous = current
+	}
+	return result
+}
+
+type minimalResultNode struct {
+	resultList          *minimalResultList
+	mode                *decoder.Mode
+	fromPosition        int
+	charsetEncoderIndex int
+	characterLength     int
+}
+
+func (this *minimalResultList) newNode(mode *decoder.Mode, fromPosition, charsetEncoderIndex, characterLength int) *minimalResultNode {
+	return &minimalResultNode{
+		resultList:          this,
+		mode:                mode,
+		fromPosition:        fromPosition,
+		charsetEncoderIndex: charsetEncoderIndex,
+		characterLength:     characterLength,
+	}
+}
+
+// getSize returns the size in bits
+func (this *minimalResultNode) getSize(version *decoder.Version) (int, gozxing.WriterException) {
+	size := 4 + this.mode.GetCharacterCountBits(version)
+	switch this.mode {
+	case decoder.Mode_KANJI:
+		size += 13 * this.characterLength
+	case decoder.Mode_ALPHANUMERIC:
+		count := len(this.alphanumericContent())
+		size += (count / 2) * 11
+		if count%2 == 1 {
+			size += 6
+		}
+	case decoder.Mode_NUMERIC:
+		size += (this.characterLength / 3) * 10
+		switch this.characterLength % 3 {
+		case 1:
+			size += 4
+		case 2:
+			size += 7
+		}
+	case decoder.Mode_BYTE:
+		count, e := this.getCharacterCountIndicator()
+		if e != nil {
+			return 0, e
+		}
+		size += 8 * count
+	case decoder.Mode_ECI:
+		size += 8 // the ECI assignment numbers for ISO-8859-x, UTF-8 and UTF-16 are all 8 bit long
+	case decoder.Mode_FNC1_SECOND_POSITION:
+		size += 8 // application indicator
+	}
+	return size, nil
+}
+
+// getCharacterCountIndicator returns the length in characters according to the specification
+// (differs from getCharacterLength() in BYTE mode for multi byte encoded characters)
+func (this *minimalResultNode) getCharacterCountIndicator() (int, gozxing.WriterException) {
+	if this.mode == decoder.Mode_ALPHANUMERIC {
+		return len(this.alphanumericContent()), nil
+	}
+	if this.mode != decoder.Mode_BYTE {
+		return this.characterLength, nil
+	}
+	bytes, e := this.resultList.encoder.encoders.Encode(this.content(), this.charsetEncoderIndex)
+	if e != nil {
+		return 0, gozxing.WrapWriterException(e)
+	}
+	return len(bytes), nil
+}
+
+func (this *minimalResultNode) content() string {
+	input := this.resultList.encoder.stringToEncode
+	return string(input[this.fromPosition : this.fromPosition+this.characterLength])
+}
+
+// alphanumericContent returns the content written in alphanumeric mode, which is escaped with the application indicator.
+func (this *minimalResultNode) alphanumericContent() string {
+	if this.resultList.encoder.escapesPercent() {
+		return escapeFNC1Alphanumeric(this.content())
+	}
+	return this.content()
+}
+
+// getBits appends the bits
+func (this *minimalResultNode) getBits(bits *gozxing.BitArray) gozxing.WriterException {
+	encoders := this.resultList.encoder.encoders
+	appendModeInfo(this.mode, bits)
+	if this.characterLength > 0 {
+		length, e := this.getCharacterCountIndicator()
+		if e != nil {
+			return e
+		}
+		if e = appendLengthInfo(length, this.resultList.version, this.mode, bits); e != nil {
+			return e
+		}
+	}
+	if this.mode == decoder.Mode_ECI {
+		_ = bits.AppendBits(encoders.GetECIValue(this.charsetEncoderIndex), 8)
+	} else if this.mode == decoder.Mode_FNC1_SECOND_POSITION {
+		_ = bits.AppendBits(this.resultList.encoder.applicationIndicator, 8)
+	} else if this.mode == decoder.Mode_ALPHANUMERIC {
+		return appendAlphanumericBytes(this.alphanumericContent(), bits)
+	} else if this.characterLength > 0 {
+		// append data
+		return appendBytes(this.content(), this.mode, bits, encoders.GetCharset(this.charsetEncoderIndex).GetCharset())
+	}
+	return nil
+}
+
+func (this *minimalResultNode) String() string {
+	result := this.mode.String() + "("
+	if this.mode == decoder.Mode_ECI {
+		result += this.resultList.encoder.encoders.GetCharsetName(this.charsetEncoderIndex)
+	} else {
+		result += makePrintable(this.content())
+	}
+	return result + ")"
+}
+
+func makePrintable(s string) string {
+	result := []rune(s)
+	for i, c := range result {
+		if c < 32 || c > 126 {
+			result[i] = '.'
+		}
+	}
+	return string(result)
+}
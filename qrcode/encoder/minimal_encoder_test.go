@@ -0,0 +1,196 @@
+package encoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+func TestMinimalGetVersionSize(t *testing.T) {
+	tests := []struct {
+		version int
+		expect  minimalVersionSize
+	}{
+		{1, minimalVersionSize_SMALL},
+		{9, minimalVersionSize_SMALL},
+		{10, minimalVersionSize_MEDIUM},
+		{26, minimalVersionSize_MEDIUM},
+		{27, minimalVersionSize_LARGE},
+		{40, minimalVersionSize_LARGE},
+	}
+	for _, test := range tests {
+		version, _ := decoder.Version_GetVersionForNumber(test.version)
+		if r := minimalGetVersionSize(version); r != test.expect {
+			t.Fatalf("minimalGetVersionSize(%v) = %v, expect %v", test.version, r, test.expect)
+		}
+	}
+	for size, expect := range []int{9, 26, 40} {
+		if r := minimalGetVersion(minimalVersionSize(size)).GetVersionNumber(); r != expect {
+			t.Fatalf("minimalGetVersion(%v) = %v, expect %v", size, r, expect)
+		}
+	}
+}
+
+func TestMinimalCanEncode(t *testing.T) {
+	tests := []struct {
+		mode   *decoder.Mode
+		c      rune
+		expect bool
+	}{
+		{decoder.Mode_NUMERIC, '0', true},
+		{decoder.Mode_NUMERIC, 'A', false},
+		{decoder.Mode_ALPHANUMERIC, 'A', true},
+		{decoder.Mode_ALPHANUMERIC, 'a', false},
+		{decoder.Mode_ALPHANUMERIC, 'Ā', false},
+		{decoder.Mode_KANJI, '漢', true},
+		{decoder.Mode_KANJI, 'A', false},
+		{decoder.Mode_BYTE, '🍣', true},
+		{decoder.Mode_ECI, 'A', false},
+	}
+	for _, test := range tests {
+		if r := minimalCanEncode(test.mode, test.c); r != test.expect {
+			t.Fatalf("minimalCanEncode(%v, %q) = %v, expect %v", test.mode, test.c, r, test.expect)
+		}
+	}
+}
+
+func TestMinimalGetCompactedOrdinal(t *testing.T) {
+	modes := []*decoder.Mode{decoder.Mode_KANJI, decoder.Mode_ALPHANUMERIC, decoder.Mode_NUMERIC, decoder.Mode_BYTE}
+	for expect, mode := range modes {
+		if r := minimalGetCompactedOrdinal(mode); r != expect {
+			t.Fatalf("minimalGetCompactedOrdinal(%v) = %v, expect %v", mode, r, expect)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("minimalGetCompactedOrdinal(ECI) must panic")
+		}
+	}()
+	minimalGetCompactedOrdinal(decoder.Mode_ECI)
+}
+
+func TestMinimalEncoder_encode(t *testing.T) {
+	tests := []struct {
+		content string
+		charset *common.CharacterSetECI
+		isGS1   bool
+		expect  string
+		bits    int
+		version int
+	}{
+		{"", nil, false, "", 0, 1},
+		{"A", nil, false, "ALPHANUMERIC(A)", 19, 1},
+		{"AAAAAAa", nil, false, "ALPHANUMERIC(AAAAAA),BYTE(a)", 66, 1},
+		{"1234567890", nil, false, "NUMERIC(1234567890)", 48, 1},
+		{"ŐŜ", nil, false, "BYTE(..)", 44, 1},
+		{"ŐŐŜ", nil, false, "BYTE(...)", 60, 1},
+		{"ŐŐŜ", common.CharacterSetECI_UTF8, false, "BYTE(...)", 60, 1},
+		{"ßßéßé", nil, false, "ECI(ISO-8859-1),BYTE(.....)", 64, 1},
+		{"ßßéßé", common.CharacterSetECI_UTF8, false, "BYTE(.....)", 92, 1},
+		{"日本語テキスト", nil, false, "KANJI(.......)", 103, 1},
+		{"abc漢字123456789012345", nil, false, "BYTE(abc),KANJI(..),NUMERIC(123456789012345)", 138, 1},
+		{"http://example.com/ABCDEFGHIJ0123456789", nil, false,
+			"BYTE(http://example.com),ALPHANUMERIC(/ABCDEFGHIJ0),NUMERIC(123456789)", 279, 3},
+		{"0104912345123459", nil, true, "FNC1_FIRST_POSITION(),NUMERIC(0104912345123459)", 72, 1},
+		{"ŐŜ", nil, true, "FNC1_FIRST_POSITION(),BYTE(..)", 48, 1},
+		{"aŐ", nil, true, "FNC1_FIRST_POSITION(),BYTE(a.)", 40, 1},
+		{"aŐŐŐ", nil, true, "ECI(ISO-8859-2),FNC1_FIRST_POSITION(),BYTE(a...)", 60, 1},
+		{"123456789ŐŜ", nil, true, "FNC1_FIRST_POSITION(),NUMERIC(123456789),BYTE(..)", 92, 1},
+		{"123456789ßßßßß", nil, true,
+			"ECI(UTF-8),FNC1_FIRST_POSITION(),NUMERIC(123456789),ECI(ISO-8859-1),BYTE(.....)", 124, 1},
+	}
+	for _, test := range tests {
+		rn, e := MinimalEncoder_encode(test.content, nil, test.charset, test.isGS1, decoder.ErrorCorrectionLevel_L)
+		if e != nil {
+			t.Fatalf("MinimalEncoder_encode(%q) returns error: %v", test.content, e)
+		}
+		if r := rn.String(); r != test.expect {
+			t.Fatalf("MinimalEncoder_encode(%q) = %v, expect %v", test.content, r, test.expect)
+		}
+		if r := rn.GetSize(); r != test.bits {
+			t.Fatalf("MinimalEncoder_encode(%q) size = %v, expect %v", test.content, r, test.bits)
+		}
+		if r := rn.GetVersion().GetVersionNumber(); r != test.version {
+			t.Fatalf("MinimalEncoder_encode(%q) version = %v, expect %v", test.content, r, test.version)
+		}
+		bits := gozxing.NewEmptyBitArray()
+		if e = rn.GetBits(bits); e != nil {
+			t.Fatalf("GetBits(%q) returns error: %v", test.content, e)
+		}
+		if r := bits.GetSize(); r != test.bits {
+			t.Fatalf("GetBits(%q) size = %v, expect %v", test.content, r, test.bits)
+		}
+	}
+}
+
+func TestMinimalEncoder_encodeFNC1SecondPosition(t *testing.T) {
+	tests := []struct {
+		content string
+		expect  string
+		bits    int
+	}{
+		{"AB\x1dCD", "FNC1_SECOND_POSITION(),ALPHANUMERIC(AB.CD)", 12 + 4 + 9 + 28},
+		{"AB%CD", "FNC1_SECOND_POSITION(),ALPHANUMERIC(AB%CD)", 12 + 4 + 9 + 33},
+		{"ABC%", "FNC1_SECOND_POSITION(),ALPHANUMERIC(ABC%)", 12 + 4 + 9 + 28},
+		{"ABCDEFGHIJ\x1d%KLMNOPQRST", "FNC1_SECOND_POSITION(),ALPHANUMERIC(ABCDEFGHIJ),BYTE(.%),ALPHANUMERIC(KLMNOPQRST)",
+			12 + (4 + 9 + 55) + (4 + 8 + 16) + (4 + 9 + 55)},
+		{"ŐŜ", "FNC1_SECOND_POSITION(),BYTE(..)", 12 + 4 + 8 + 32},
+	}
+	for _, test := range tests {
+		minimal := newMinimalEncoder(test.content, nil, false, decoder.ErrorCorrectionLevel_L)
+		minimal.applicationIndicator = 37
+		rn, e := minimal.encode(nil)
+		if e != nil {
+			t.Fatalf("encode(%q) returns error: %v", test.content, e)
+		}
+		if r := rn.String(); r != test.expect {
+			t.Fatalf("encode(%q) = %v, expect %v", test.content, r, test.expect)
+		}
+		if r := rn.GetSize(); r != test.bits {
+			t.Fatalf("encode(%q) size = %v, expect %v", test.content, r, test.bits)
+		}
+		bits := gozxing.NewEmptyBitArray()
+		_ = rn.GetBits(bits)
+		if r := bits.GetSize(); r != test.bits {
+			t.Fatalf("GetBits(%q) size = %v, expect %v", test.content, r, test.bits)
+		}
+	}
+}
+
+func TestMinimalEncoder_encodeVersion(t *testing.T) {
+	content := strings.Repeat("0123456789", 100)
+
+	version, _ := decoder.Version_GetVersionForNumber(20)
+	rn, e := MinimalEncoder_encode(content, version, nil, false, decoder.ErrorCorrectionLevel_L)
+	if e != nil {
+		t.Fatalf("MinimalEncoder_encode returns error: %v", e)
+	}
+	if r := rn.GetVersion().GetVersionNumber(); r != 13 {
+		t.Fatalf("MinimalEncoder_encode version = %v, expect 13", r)
+	}
+
+	version, _ = decoder.Version_GetVersionForNumber(5)
+	_, e = MinimalEncoder_encode(content, version, nil, false, decoder.ErrorCorrectionLevel_L)
+	if e == nil {
+		t.Fatalf("MinimalEncoder_encode must be error")
+	}
+
+	_, e = MinimalEncoder_encode(strings.Repeat(content, 8), nil, nil, false, decoder.ErrorCorrectionLevel_L)
+	if e == nil {
+		t.Fatalf("MinimalEncoder_encode must be error")
+	}
+}
+
+func TestMinimalEncoder_getBits(t *testing.T) {
+	rn, _ := MinimalEncoder_encode("AAAAAAa", nil, nil, false, decoder.ErrorCorrectionLevel_L)
+	bits := gozxing.NewEmptyBitArray()
+	_ = rn.GetBits(bits)
+	// ALPHANUMERIC(6) "AA" "AA" "AA" BYTE(1) "a"
+	expect := " ..X..... ..XX...X XX..XX.. ..XXX..X X....XXX ..XX...X ........ .X.XX... .X"
+	if r := bits.String(); r != expect {
+		t.Fatalf("GetBits = %v, expect %v", r, expect)
+	}
+}
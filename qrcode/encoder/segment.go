@@ -0,0 +1,236 @@
+package encoder
+
+import (
+	"unicode/utf8"
+
+	textencoding "golang.org/x/text/encoding"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+// Segment A segment of the QR Code data.
+//
+// A segment is encoded exactly as specified: its mode indicator, character count indicator
+// (for the data segments) and data are written without any mode selection.
+type Segment struct {
+	mode    *decoder.Mode
+	data    string
+	charset *common.CharacterSetECI
+	value   int // application indicator of FNC1 in second position
+}
+
+// NewNumericSegment Creates a Numeric mode segment. The data must consist of the digits 0-9.
+func NewNumericSegment(data string) (*Segment, gozxing.WriterException) {
+	for i := 0; i < len(data); i++ {
+		if data[i] < '0' || data[i] > '9' {
+			return nil, gozxing.NewWriterException(
+				"IllegalArgumentException: Invalid numeric character %q", data[i])
+		}
+	}
+	return &Segment{mode: decoder.Mode_NUMERIC, data: data}, nil
+}
+
+// NewAlphanumericSegment Creates an Alphanumeric mode segment.
+// The data must consist of 0-9, A-Z, space and $%*+-./:
+func NewAlphanumericSegment(data string) (*Segment, gozxing.WriterException) {
+	for i := 0; i < len(data); i++ {
+		if getAlphanumericCode(data[i]) == -1 {
+			return nil, gozxing.NewWriterException(
+				"IllegalArgumentException: Invalid alphanumeric character %q", data[i])
+		}
+	}
+	return &Segment{mode: decoder.Mode_ALPHANUMERIC, data: data}, nil
+}
+
+// NewByteSegment Creates a Byte mode segment.
+//
+// No ECI designator is written by this segment; put an ECI segment in front of it if necessary.
+//
+// @param data the data
+// @param charset the character set to convert the data into bytes, or nil for the default (UTF-8)
+//
+func NewByteSegment(data string, charset *common.CharacterSetECI) (*Segment, gozxing.WriterException) {
+	if charset != nil {
+		if _, e := charset.GetCharset().NewEncoder().Bytes([]byte(data)); e != nil {
+			return nil, gozxing.WrapWriterException(e)
+		}
+	}
+	return &Segment{mode: decoder.Mode_BYTE, data: data, charset: charset}, nil
+}
+
+// NewKanjiSegment Creates a Kanji mode segment. The data must consist of double byte Shift_JIS characters.
+func NewKanjiSegment(data string) (*Segment, gozxing.WriterException) {
+	if !isOnlyDoubleByteKanji(data) {
+		return nil, gozxing.NewWriterException(
+			"IllegalArgumentException: Data contains non Kanji characters")
+	}
+	return &Segment{mode: decoder.Mode_KANJI, data: data}, nil
+}
+
+// NewHanziSegment Creates a Hanzi mode segment. The data must consist of double byte GB2312 characters.
+func NewHanziSegment(data string) (*Segment, gozxing.WriterException) {
+	if !isOnlyDoubleByteHanzi(data) {
+		return nil, gozxing.NewWriterException(
+			"IllegalArgumentException: Data contains non Hanzi characters")
+	}
+	return &Segment{mode: decoder.Mode_HANZI, data: data}, nil
+}
+
+// NewECISegment Creates an ECI designator segment.
+func NewECISegment(eci *common.CharacterSetECI) *Segment {
+	return &Segment{mode: decoder.Mode_ECI, charset: eci}
+}
+
+// NewFNC1FirstPositionSegment Creates a FNC1 in first position segment (GS1 format).
+func NewFNC1FirstPositionSegment() *Segment {
+	return &Segment{mode: decoder.Mode_FNC1_FIRST_POSITION}
+}
+
+// NewFNC1SecondPositionSegment Creates a FNC1 in second position segment (AIM application).
+//
+// @param applicationIndicator the application indicator, two digits (00-99) as the number,
+// or a letter as its ASCII value + 100.
+//
+func NewFNC1SecondPositionSegment(applicationIndicator int) (*Segment, gozxing.WriterException) {
+	if applicationIndicator < 0 || applicationIndicator > 255 {
+		return nil, gozxing.NewWriterException(
+			"IllegalArgumentException: Invalid application indicator %v", applicationIndicator)
+	}
+	return &Segment{mode: decoder.Mode_FNC1_SECOND_POSITION, value: applicationIndicator}, nil
+}
+
+func (this *Segment) GetMode() *decoder.Mode {
+	return this.mode
+}
+
+func (this *Segment) GetData() string {
+	return this.data
+}
+
+func (this *Segment) isDataSegment() bool {
+	switch this.mode {
+	case decoder.Mode_NUMERIC, decoder.Mode_ALPHANUMERIC, decoder.Mode_BYTE, decoder.Mode_KANJI, decoder.Mode_HANZI:
+		return true
+	}
+	return false
+}
+
+// getCharacterCount returns the value of the character count indicator
+func (this *Segment) getCharacterCount() int {
+	switch this.mode {
+	case decoder.Mode_BYTE:
+		bytes, _ := this.getEncoding().NewEncoder().Bytes([]byte(this.data))
+		return len(bytes)
+	case decoder.Mode_KANJI, decoder.Mode_HANZI:
+		return utf8.RuneCountInString(this.data)
+	default:
+		return len(this.data)
+	}
+}
+
+func (this *Segment) getEncoding() textencoding.Encoding {
+	if this.charset == nil {
+		return Encoder_DEFAULT_BYTE_MODE_ENCODING
+	}
+	return this.charset.GetCharset()
+}
+
+// getBits Appends the bits of the segment for the version.
+func (this *Segment) getBits(version *decoder.Version, bits *gozxing.BitArray) gozxing.WriterException {
+	switch this.mode {
+	case decoder.Mode_ECI:
+		appendECI(this.charset, bits)
+		return nil
+	case decoder.Mode_FNC1_FIRST_POSITION:
+		appendModeInfo(this.mode, bits)
+		return nil
+	case decoder.Mode_FNC1_SECOND_POSITION:
+		appendFNC1SecondPositionInfo(this.value, bits)
+		return nil
+	}
+	appendModeInfo(this.mode, bits)
+	if this.mode == decoder.Mode_HANZI {
+		appendHanziSubsetInfo(bits)
+	}
+	if e := appendLengthInfo(this.getCharacterCount(), version, this.mode, bits); e != nil {
+		return e
+	}
+	return appendBytes(this.data, this.mode, bits, this.getEncoding())
+}
+
+// appendSegments Appends the bits of all the segments for the version.
+func appendSegments(segments []*Segment, version *decoder.Version, bits *gozxing.BitArray) gozxing.WriterException {
+	for _, segment := range segments {
+		if e := segment.getBits(version, bits); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// Encoder_encodeSegments Encodes the segments into a QR Code as they are.
+//
+// The version is specified by the QR_VERSION hint or the smallest version which can contain
+// the segments is chosen. The mask pattern is specified by the QR_MASK_PATTERN hint
+// or chosen automatically. Other hints are ignored.
+//
+// @param segments the segments in order
+// @param ecLevel the error correction level
+// @param hints the encode hints
+// @return the QR Code
+//
+func Encoder_encodeSegments(segments []*Segment, ecLevel decoder.ErrorCorrectionLevel,
+	hints map[gozxing.EncodeHintType]interface{}) (*QRCode, gozxing.WriterException) {
+
+	if len(segments) == 0 {
+		return nil, gozxing.NewWriterException("IllegalArgumentException: No segments")
+	}
+
+	// The mode of the QR Code is the mode of the first data segment
+	var mode *decoder.Mode
+	for _, segment := range segments {
+		if segment.isDataSegment() {
+			mode = segment.mode
+			break
+		}
+	}
+	if mode == nil {
+		return nil, gozxing.NewWriterException("IllegalArgumentException: No data segments")
+	}
+
+	version, e := getVersionHint(hints)
+	if e != nil {
+		return nil, e
+	}
+
+	var bits *gozxing.BitArray
+	if version != nil {
+		bits = gozxing.NewEmptyBitArray()
+		if e = appendSegments(segments, version, bits); e != nil {
+			return nil, e
+		}
+		if !willFit(bits.GetSize(), version, ecLevel) {
+			return nil, newDataTooBigException("Data too big for requested version")
+		}
+	} else {
+		for versionNum := 1; versionNum <= 40; versionNum++ {
+			v, _ := decoder.Version_GetVersionForNumber(versionNum)
+			b := gozxing.NewEmptyBitArray()
+			if appendSegments(segments, v, b) != nil {
+				// the character count does not fit in the indicator of this version
+				continue
+			}
+			if willFit(b.GetSize(), v, ecLevel) {
+				version, bits = v, b
+				break
+			}
+		}
+		if version == nil {
+			return nil, newDataTooBigException("Data too big")
+		}
+	}
+
+	return buildQRCode(bits, mode, version, ecLevel, hints)
+}
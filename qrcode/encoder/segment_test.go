@@ -0,0 +1,186 @@
+package encoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+func TestNewSegment_Fail(t *testing.T) {
+	if _, e := NewNumericSegment("123A"); e == nil {
+		t.Fatalf("NewNumericSegment must be error")
+	}
+	if _, e := NewAlphanumericSegment("ABCa"); e == nil {
+		t.Fatalf("NewAlphanumericSegment must be error")
+	}
+	if _, e := NewByteSegment("日本", common.CharacterSetECI_ISO8859_1); e == nil {
+		t.Fatalf("NewByteSegment must be error")
+	}
+	if _, e := NewKanjiSegment("漢字A"); e == nil {
+		t.Fatalf("NewKanjiSegment must be error")
+	}
+	if _, e := NewHanziSegment("中文A"); e == nil {
+		t.Fatalf("NewHanziSegment must be error")
+	}
+	if _, e := NewFNC1SecondPositionSegment(256); e == nil {
+		t.Fatalf("NewFNC1SecondPositionSegment must be error")
+	}
+	if _, e := NewFNC1SecondPositionSegment(-1); e == nil {
+		t.Fatalf("NewFNC1SecondPositionSegment must be error")
+	}
+}
+
+func TestSegment_getBits(t *testing.T) {
+	version, _ := decoder.Version_GetVersionForNumber(1)
+
+	num, _ := NewNumericSegment("01234567")
+	alnum, _ := NewAlphanumericSegment("AC-42")
+	byt, _ := NewByteSegment("é", common.CharacterSetECI_ISO8859_1)
+	kanji, _ := NewKanjiSegment("点茗")
+	hanzi, _ := NewHanziSegment("中文")
+	fnc1, _ := NewFNC1SecondPositionSegment(37)
+
+	tests := []struct {
+		segment *Segment
+		mode    *decoder.Mode
+		expect  string
+	}{
+		{num, decoder.Mode_NUMERIC,
+			" ...X.... ..X..... ....XX.. .X.X.XX. .XX....X X"},
+		{alnum, decoder.Mode_ALPHANUMERIC,
+			" ..X..... ..X.X..X XX..XXX. XXX..XXX ..X....X ."},
+		{byt, decoder.Mode_BYTE,
+			" .X...... ...XXXX. X..X"},
+		{kanji, decoder.Mode_KANJI,
+			" X....... ..X..XX. XX..XXXX XXX.X.X. X.X.X."},
+		{hanzi, decoder.Mode_HANZI,
+			" XX.X...X ......X. X..X...X .XXXX.XX XX..X... XX"},
+		{NewECISegment(common.CharacterSetECI_UTF8), decoder.Mode_ECI,
+			" .XXX...X X.X."},
+		{NewFNC1FirstPositionSegment(), decoder.Mode_FNC1_FIRST_POSITION,
+			" .X.X"},
+		{fnc1, decoder.Mode_FNC1_SECOND_POSITION,
+			" X..X..X. .X.X"},
+	}
+	for _, test := range tests {
+		if r := test.segment.GetMode(); r != test.mode {
+			t.Fatalf("GetMode = %v, expect %v", r, test.mode)
+		}
+		bits := gozxing.NewEmptyBitArray()
+		if e := test.segment.getBits(version, bits); e != nil {
+			t.Fatalf("getBits(%v) returns error: %v", test.mode, e)
+		}
+		if r := bits.String(); r != test.expect {
+			t.Fatalf("getBits(%v) = %v, expect %v", test.mode, r, test.expect)
+		}
+	}
+
+	if r := num.GetData(); r != "01234567" {
+		t.Fatalf("GetData = %v, expect 01234567", r)
+	}
+}
+
+func TestEncoder_encodeSegments(t *testing.T) {
+	prefix, _ := NewAlphanumericSegment("PAY:")
+	amount, _ := NewNumericSegment("0012345")
+	text, _ := NewByteSegment("¡Hola!", common.CharacterSetECI_ISO8859_1)
+	kanji, _ := NewKanjiSegment("漢字")
+	hanzi, _ := NewHanziSegment("中文")
+	utf8, _ := NewByteSegment("Ελληνικά", common.CharacterSetECI_UTF8)
+	segments := []*Segment{
+		prefix, amount, NewECISegment(common.CharacterSetECI_ISO8859_1), text, kanji, hanzi,
+		NewECISegment(common.CharacterSetECI_UTF8), utf8,
+	}
+
+	qr, e := Encoder_encodeSegments(segments, decoder.ErrorCorrectionLevel_M, nil)
+	if e != nil {
+		t.Fatalf("Encoder_encodeSegments returns error: %v", e)
+	}
+	if r := qr.GetMode(); r != decoder.Mode_ALPHANUMERIC {
+		t.Fatalf("mode = %v, expect %v", r, decoder.Mode_ALPHANUMERIC)
+	}
+	if r := qr.GetVersion().GetVersionNumber(); r != 4 {
+		t.Fatalf("version = %v, expect 4", r)
+	}
+	testDecode(t, qr, "PAY:0012345¡Hola!漢字中文Ελληνικά")
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_VERSION:      10,
+		gozxing.EncodeHintType_QR_MASK_PATTERN: 3,
+	}
+	qr, e = Encoder_encodeSegments(segments, decoder.ErrorCorrectionLevel_H, hints)
+	if e != nil {
+		t.Fatalf("Encoder_encodeSegments returns error: %v", e)
+	}
+	if r := qr.GetVersion().GetVersionNumber(); r != 10 {
+		t.Fatalf("version = %v, expect 10", r)
+	}
+	if r := qr.GetMaskPattern(); r != 3 {
+		t.Fatalf("mask pattern = %v, expect 3", r)
+	}
+	if r := qr.GetECLevel(); r != decoder.ErrorCorrectionLevel_H {
+		t.Fatalf("ecLevel = %v, expect %v", r, decoder.ErrorCorrectionLevel_H)
+	}
+	testDecode(t, qr, "PAY:0012345¡Hola!漢字中文Ελληνικά")
+
+	gs1, _ := NewNumericSegment("0104912345123459")
+	qr, e = Encoder_encodeSegments([]*Segment{NewFNC1FirstPositionSegment(), gs1}, decoder.ErrorCorrectionLevel_L, nil)
+	if e != nil {
+		t.Fatalf("Encoder_encodeSegments returns error: %v", e)
+	}
+	testDecode(t, qr, "0104912345123459")
+
+	fnc1, _ := NewFNC1SecondPositionSegment(37)
+	aim, _ := NewAlphanumericSegment("AB%CD")
+	qr, e = Encoder_encodeSegments([]*Segment{fnc1, aim}, decoder.ErrorCorrectionLevel_L, nil)
+	if e != nil {
+		t.Fatalf("Encoder_encodeSegments returns error: %v", e)
+	}
+	if r := qr.GetMode(); r != decoder.Mode_ALPHANUMERIC {
+		t.Fatalf("mode = %v, expect %v", r, decoder.Mode_ALPHANUMERIC)
+	}
+}
+
+func TestEncoder_encodeSegments_Fail(t *testing.T) {
+	_, e := Encoder_encodeSegments(nil, decoder.ErrorCorrectionLevel_L, nil)
+	if e == nil {
+		t.Fatalf("Encoder_encodeSegments must be error")
+	}
+
+	segments := []*Segment{NewECISegment(common.CharacterSetECI_UTF8)}
+	_, e = Encoder_encodeSegments(segments, decoder.ErrorCorrectionLevel_L, nil)
+	if e == nil {
+		t.Fatalf("Encoder_encodeSegments must be error")
+	}
+
+	num, _ := NewNumericSegment(strings.Repeat("0", 42))
+	segments = []*Segment{num}
+	hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_VERSION: 1}
+	_, e = Encoder_encodeSegments(segments, decoder.ErrorCorrectionLevel_L, hints)
+	if e == nil {
+		t.Fatalf("Encoder_encodeSegments must be error")
+	}
+
+	hints[gozxing.EncodeHintType_QR_VERSION] = 41
+	_, e = Encoder_encodeSegments(segments, decoder.ErrorCorrectionLevel_L, hints)
+	if e == nil {
+		t.Fatalf("Encoder_encodeSegments must be error")
+	}
+
+	// character count exceeds the indicator of version 1
+	num, _ = NewNumericSegment(strings.Repeat("0", 1024))
+	hints[gozxing.EncodeHintType_QR_VERSION] = 1
+	_, e = Encoder_encodeSegments([]*Segment{num}, decoder.ErrorCorrectionLevel_L, hints)
+	if e == nil {
+		t.Fatalf("Encoder_encodeSegments must be error")
+	}
+
+	num, _ = NewNumericSegment(strings.Repeat("0", 7090))
+	_, e = Encoder_encodeSegments([]*Segment{num}, decoder.ErrorCorrectionLevel_L, nil)
+	if e == nil {
+		t.Fatalf("Encoder_encodeSegments must be error")
+	}
+}
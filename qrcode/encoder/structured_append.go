@@ -0,0 +1,168 @@
+package encoder
+
+import (
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+// StructuredAppend_MAX_SYMBOLS The maximum number of symbols in a structured append sequence.
+const StructuredAppend_MAX_SYMBOLS = 16
+
+// Encoder_encodeStructuredAppend Encodes the content into a sequence of QR Codes using structured append.
+//
+// The content is split into the smallest number of symbols (up to 16) that can hold it with the
+// given error correction level and hints. Each symbol is filled up to its capacity, using the smallest
+// version which keeps the number of symbols. Each symbol starts with a structured append header
+// that holds its position in the sequence, the number of symbols, and the parity of the whole message.
+// If the content fits in a single symbol, one symbol without the header is returned.
+//
+// @param content the content to encode
+// @param ecLevel the error correction level for all the symbols
+// @param hints   the encode hints, applied to every symbol
+// @return the QR Codes in sequence order
+//
+func Encoder_encodeStructuredAppend(content string, ecLevel decoder.ErrorCorrectionLevel,
+	hints map[gozxing.EncodeHintType]interface{}) ([]*QRCode, gozxing.WriterException) {
+
+	code, e := Encoder_encode(content, ecLevel, hints)
+	if e == nil {
+		return []*QRCode{code}, nil
+	}
+	if _, ok := e.(dataTooBigException); !ok {
+		return nil, e
+	}
+
+	parts, e := splitContentByCapacity(content, ecLevel, hints)
+	if e != nil {
+		return nil, e
+	}
+
+	// The parity is calculated from the data bytes in the symbols,
+	// which depend on the mode and the character set of each segment.
+	parity := 0
+	for _, part := range parts {
+		_, _, _, data, e := encodeBits(part, ecLevel, hints, newStructuredAppendHeader(0, len(parts), 0))
+		if e != nil {
+			return nil, e
+		}
+		for _, b := range data {
+			parity ^= int(b)
+		}
+	}
+
+	codes := make([]*QRCode, 0, len(parts))
+	for index, part := range parts {
+		code, e := encode(part, ecLevel, hints, newStructuredAppendHeader(index, len(parts), parity))
+		if e != nil {
+			return nil, e
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// splitContentByCapacity Splits the content into the smallest number of parts for the symbols.
+//
+// The number of parts is decided with the version of the QR_VERSION hint, or the version 40.
+// Without the hint, the parts are split for the smallest version which needs the same number of parts.
+//
+func splitContentByCapacity(content string, ecLevel decoder.ErrorCorrectionLevel,
+	hints map[gozxing.EncodeHintType]interface{}) ([]string, gozxing.WriterException) {
+
+	requestedVersion, e := getVersionHint(hints)
+	if e != nil {
+		return nil, e
+	}
+	if requestedVersion != nil {
+		return splitContentForVersion(content, ecLevel, hints, requestedVersion.GetVersionNumber())
+	}
+
+	parts, e := splitContentForVersion(content, ecLevel, hints, 40)
+	if e != nil {
+		return nil, e
+	}
+	low, high := 1, 40
+	for low < high {
+		mid := (low + high) / 2
+		p, e := splitContentForVersion(content, ecLevel, hints, mid)
+		if e != nil {
+			if _, ok := e.(dataTooBigException); !ok {
+				return nil, e
+			}
+		}
+		if e == nil && len(p) == len(parts) {
+			high = mid
+			parts = p
+		} else {
+			low = mid + 1
+		}
+	}
+	return parts, nil
+}
+
+// splitContentForVersion Splits the content into the parts which fill the symbols of the version.
+func splitContentForVersion(content string, ecLevel decoder.ErrorCorrectionLevel,
+	hints map[gozxing.EncodeHintType]interface{}, versionNumber int) ([]string, gozxing.WriterException) {
+
+	versionHints := make(map[gozxing.EncodeHintType]interface{}, len(hints)+1)
+	for k, v := range hints {
+		versionHints[k] = v
+	}
+	versionHints[gozxing.EncodeHintType_QR_VERSION] = versionNumber
+
+	// The header has the same size for any position, number of symbols and parity.
+	header := newStructuredAppendHeader(0, StructuredAppend_MAX_SYMBOLS, 0)
+
+	runes := []rune(content)
+	parts := make([]string, 0, StructuredAppend_MAX_SYMBOLS)
+	for start := 0; start < len(runes); {
+		if len(parts) == StructuredAppend_MAX_SYMBOLS {
+			return nil, newDataTooBigException(
+				"Data too big for %v symbols of version %v", StructuredAppend_MAX_SYMBOLS, versionNumber)
+		}
+		// Find the longest part which fits in the symbol,
+		// no symbol holds more than 7089 characters (numeric mode of version 40-L).
+		low, high := start, len(runes)
+		if high > start+7089 {
+			high = start + 7089
+		}
+		for low < high {
+			mid := (low + high + 1) / 2
+			_, _, _, _, e := encodeBits(string(runes[start:mid]), ecLevel, versionHints, header)
+			if e == nil {
+				low = mid
+			} else if _, ok := e.(dataTooBigException); ok {
+				high = mid - 1
+			} else {
+				return nil, e
+			}
+		}
+		if low == start {
+			return nil, newDataTooBigException("Data too big for version %v", versionNumber)
+		}
+		parts = append(parts, string(runes[start:low]))
+		start = low
+	}
+	return parts, nil
+}
+
+// newStructuredAppendHeader Creates the structured append header.
+func newStructuredAppendHeader(index, total, parity int) *gozxing.BitArray {
+	header := gozxing.NewEmptyBitArray()
+	appendStructuredAppendHeader(index, total, parity, header)
+	return header
+}
+
+// appendStructuredAppendHeader Appends the structured append header.
+//
+// @param index  the position of the symbol in the sequence (0 to 15)
+// @param total  the number of the symbols (1 to 16)
+// @param parity the parity data of the whole message
+// @param bits   the bit array to append the header
+//
+func appendStructuredAppendHeader(index, total, parity int, bits *gozxing.BitArray) {
+	appendModeInfo(decoder.Mode_STRUCTURED_APPEND, bits)
+	_ = bits.AppendBits(index, 4)
+	_ = bits.AppendBits(total-1, 4)
+	_ = bits.AppendBits(parity, 8)
+}
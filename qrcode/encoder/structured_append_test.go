@@ -0,0 +1,185 @@
+package encoder
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+func TestSplitContentForVersion(t *testing.T) {
+	ecLevel := decoder.ErrorCorrectionLevel_L
+
+	// version 1-L holds 35 digits with the header
+	content := strings.Repeat("0123456789", 10)
+	parts, e := splitContentForVersion(content, ecLevel, nil, 1)
+	if e != nil {
+		t.Fatalf("splitContentForVersion returns error: %v", e)
+	}
+	expect := []string{content[:35], content[35:70], content[70:]}
+	if !reflect.DeepEqual(parts, expect) {
+		t.Fatalf("splitContentForVersion = %q, expect %q", parts, expect)
+	}
+
+	// version 1-L holds 15 bytes with the header, 20 symbols are needed
+	_, e = splitContentForVersion(strings.Repeat("abcdefghij", 30), ecLevel, nil, 1)
+	if _, ok := e.(dataTooBigException); !ok {
+		t.Fatalf("splitContentForVersion must be dataTooBigException, %T", e)
+	}
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_CHARACTER_SET: "ISO-8859-1",
+	}
+	_, e = splitContentForVersion("abc点", ecLevel, hints, 1)
+	if _, ok := e.(dataTooBigException); ok || e == nil {
+		t.Fatalf("splitContentForVersion must be error other than dataTooBigException, %v", e)
+	}
+}
+
+func TestSplitContentByCapacity(t *testing.T) {
+	ecLevel := decoder.ErrorCorrectionLevel_L
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_VERSION: "x",
+	}
+	_, e := splitContentByCapacity("abc", ecLevel, hints)
+	if e == nil {
+		t.Fatalf("splitContentByCapacity must be error")
+	}
+
+	// version 40-L holds 2951 bytes with the header
+	content := strings.Repeat("abcdefghij", 300)
+	parts, e := splitContentByCapacity(content, ecLevel, nil)
+	if e != nil {
+		t.Fatalf("splitContentByCapacity returns error: %v", e)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("splitContentByCapacity returns %v parts, expect 2", len(parts))
+	}
+	if r := strings.Join(parts, ""); r != content {
+		t.Fatalf("joined parts = %q, expect %q", r, content)
+	}
+	// the parts are split for the version 27-L, the smallest one holding 1500 bytes (1526 bytes with the header)
+	if l := len(parts[0]); l != 1526 {
+		t.Fatalf("len(parts[0]) = %v, expect 1526", l)
+	}
+}
+
+func TestAppendStructuredAppendHeader(t *testing.T) {
+	bits := gozxing.NewEmptyBitArray()
+	appendStructuredAppendHeader(2, 5, 0xa5, bits)
+	expect := " ..XX..X. .X..X.X. .X.X"
+	if r := bits.String(); r != expect {
+		t.Fatalf("appendStructuredAppendHeader = %q, expect %q", r, expect)
+	}
+}
+
+func TestEncoder_encodeStructuredAppend(t *testing.T) {
+	ecLevel := decoder.ErrorCorrectionLevel_L
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_CHARACTER_SET: "unknown",
+	}
+	_, e := Encoder_encodeStructuredAppend("ABC", ecLevel, hints)
+	if e == nil {
+		t.Fatalf("Encoder_encodeStructuredAppend must be error")
+	}
+
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_CHARACTER_SET: "ISO-8859-1",
+	}
+	_, e = Encoder_encodeStructuredAppend("点", ecLevel, hints)
+	if e == nil {
+		t.Fatalf("Encoder_encodeStructuredAppend must be error")
+	}
+
+	codes, e := Encoder_encodeStructuredAppend("ABC", ecLevel, nil)
+	if e != nil {
+		t.Fatalf("Encoder_encodeStructuredAppend returns error: %v", e)
+	}
+	if len(codes) != 1 {
+		t.Fatalf("Encoder_encodeStructuredAppend returns %v codes, expect 1", len(codes))
+	}
+
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_VERSION: 1,
+	}
+	codes, e = Encoder_encodeStructuredAppend(strings.Repeat("0123456789", 10), ecLevel, hints)
+	if e != nil {
+		t.Fatalf("Encoder_encodeStructuredAppend returns error: %v", e)
+	}
+	if len(codes) != 3 {
+		t.Fatalf("Encoder_encodeStructuredAppend returns %v codes, expect 3", len(codes))
+	}
+	for _, code := range codes {
+		if v := code.GetVersion().GetVersionNumber(); v != 1 {
+			t.Fatalf("version = %v, expect 1", v)
+		}
+	}
+
+	_, e = Encoder_encodeStructuredAppend(strings.Repeat("abcdefghij", 30), ecLevel, hints)
+	if e == nil {
+		t.Fatalf("Encoder_encodeStructuredAppend must be error")
+	}
+
+	// the errors other than the capacity are returned as is
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_VERSION:    1,
+		gozxing.EncodeHintType_CHARACTER_SET: "ISO-8859-1",
+	}
+	_, e = Encoder_encodeStructuredAppend(strings.Repeat("点", 100), ecLevel, hints)
+	if _, ok := e.(dataTooBigException); ok || e == nil {
+		t.Fatalf("Encoder_encodeStructuredAppend must be error other than dataTooBigException, %v", e)
+	}
+}
+
+func TestEncoder_encodeStructuredAppendParity(t *testing.T) {
+	ecLevel := decoder.ErrorCorrectionLevel_H
+
+	// 'ß' is written in ISO-8859-1 by QR_COMPACT, and in UTF-8 otherwise
+	content := strings.Repeat("Weiß", 401)
+	for _, compact := range []bool{false, true} {
+		hints := map[gozxing.EncodeHintType]interface{}{
+			gozxing.EncodeHintType_QR_COMPACT: compact,
+		}
+		codes, e := Encoder_encodeStructuredAppend(content, ecLevel, hints)
+		if e != nil {
+			t.Fatalf("Encoder_encodeStructuredAppend(compact=%v) returns error: %v", compact, e)
+		}
+		if len(codes) != 2 {
+			t.Fatalf("Encoder_encodeStructuredAppend(compact=%v) returns %v codes, expect 2", compact, len(codes))
+		}
+
+		parity := 0
+		text := ""
+		results := make([]*common.DecoderResult, 0, len(codes))
+		for _, code := range codes {
+			matrix, _ := gozxing.ParseStringToBitMatrix(code.matrix.String(), " 1", " 0")
+			result, e := decoder.NewDecoder().Decode(matrix, nil)
+			if e != nil {
+				t.Fatalf("Decode(compact=%v) returns error: %v", compact, e)
+			}
+			for _, segment := range result.GetSegments() {
+				for _, b := range segment.GetBytes() {
+					parity ^= int(b)
+				}
+			}
+			text += result.GetText()
+			results = append(results, result)
+		}
+		if text != content {
+			t.Fatalf("decoded text(compact=%v) = %q, expect %q", compact, text, content)
+		}
+		for i, result := range results {
+			if p := result.GetStructuredAppendParity(); p != parity {
+				t.Fatalf("parity(compact=%v)[%v] = %v, expect %v", compact, i, p, parity)
+			}
+			if s := result.GetStructuredAppendSequenceNumber(); s != i<<4|1 {
+				t.Fatalf("sequence(compact=%v)[%v] = %#x, expect %#x", compact, i, s, i<<4|1)
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package qrcode
 
 import (
+	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/makiuchi-d/gozxing"
@@ -14,6 +16,8 @@ type QRCodeReader struct {
 	decoder *decoder.Decoder
 }
 
+var _ gozxing.ReaderWithContext = &QRCodeReader{}
+
 func NewQRCodeReader() gozxing.Reader {
 	return &QRCodeReader{
 		decoder.NewDecoder(),
@@ -28,7 +32,32 @@ func (this *QRCodeReader) DecodeWithoutHints(image *gozxing.BinaryBitmap) (*gozx
 	return this.Decode(image, nil)
 }
 
+// Decode Locates and decodes a QR code in an image.
+//
+// If the ALSO_INVERTED hint is set, the inverted image is also tried.
+//
+// @return a String representing the content encoded by the QR code
+// @throws NotFoundException if a QR code cannot be found
+// @throws FormatException if a QR code cannot be decoded
+// @throws ChecksumException if error correction fails
+//
 func (this *QRCodeReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return this.DecodeContext(context.Background(), image, hints)
+}
+
+// DecodeContext Locates and decodes a QR code in an image as Decode,
+// and stops finding the finder patterns when the context is done.
+//
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func (this *QRCodeReader) DecodeContext(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	return gozxing.Reader_DecodeAlsoInverted(image, hints,
+		func(image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+			return this.decode(ctx, image, hints)
+		})
+}
+
+func (this *QRCodeReader) decode(ctx context.Context, image *gozxing.BinaryBitmap, hints map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
 	var decoderResult *common.DecoderResult
 	var points []gozxing.ResultPoint
 
@@ -47,7 +76,7 @@ func (this *QRCodeReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.
 		}
 		points = []gozxing.ResultPoint{}
 	} else {
-		detectorResult, e := detector.NewDetector(blackMatrix).Detect(hints)
+		detectorResult, e := detector.NewDetector(blackMatrix).DetectContext(ctx, hints)
 		if e != nil {
 			return nil, e
 		}
@@ -59,7 +88,8 @@ func (this *QRCodeReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.
 	}
 
 	// If the code was mirrored: swap the bottom-left and the top-right points.
-	if metadata, ok := decoderResult.GetOther().(*decoder.QRCodeDecoderMetaData); ok {
+	metadata, _ := decoderResult.GetOther().(*decoder.QRCodeDecoderMetaData)
+	if metadata != nil {
 		metadata.ApplyMirroredCorrection(points)
 	}
 
@@ -80,11 +110,39 @@ func (this *QRCodeReader) Decode(image *gozxing.BinaryBitmap, hints map[gozxing.
 			gozxing.ResultMetadataType_STRUCTURED_APPEND_PARITY,
 			decoderResult.GetStructuredAppendParity())
 	}
+	if fnc1Position := decoderResult.GetFNC1Position(); fnc1Position != 0 {
+		result.PutMetadata(gozxing.ResultMetadataType_FNC1_POSITION, fnc1Position)
+		if ai := decoderResult.GetApplicationIndicator(); ai >= 0 {
+			result.PutMetadata(gozxing.ResultMetadataType_APPLICATION_INDICATOR, applicationIndicatorString(ai))
+		}
+	}
 	result.PutMetadata(
 		gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER, "]Q"+strconv.Itoa(decoderResult.GetSymbologyModifier()))
+	if segments := decoderResult.GetSegments(); len(segments) > 0 {
+		result.PutMetadata(gozxing.ResultMetadataType_DECODED_SEGMENTS, segments)
+	}
+	if metadata != nil {
+		if version := metadata.GetVersion(); version != nil {
+			dimension := version.GetDimensionForVersion()
+			result.PutMetadata(gozxing.ResultMetadataType_SYMBOL_VERSION, version.GetVersionNumber())
+			result.PutMetadata(gozxing.ResultMetadataType_MASK_PATTERN, metadata.GetMaskPattern())
+			result.PutMetadata(gozxing.ResultMetadataType_SYMBOL_ROWS, dimension)
+			result.PutMetadata(gozxing.ResultMetadataType_SYMBOL_COLUMNS, dimension)
+		}
+		result.PutMetadata(gozxing.ResultMetadataType_MIRRORED, metadata.IsMirrored())
+	}
 	return result, nil
 }
 
+// applicationIndicatorString returns the application indicator as two digits (00-99),
+// or as the letter whose ASCII value + 100 is the indicator.
+func applicationIndicatorString(applicationIndicator int) string {
+	if applicationIndicator < 100 {
+		return fmt.Sprintf("%02d", applicationIndicator)
+	}
+	return string(rune(applicationIndicator - 100))
+}
+
 func (this *QRCodeReader) Reset() {
 	// do nothing
 }
@@ -1,9 +1,16 @@
 package qrcode
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
+	errors "golang.org/x/xerrors"
+
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+	"github.com/makiuchi-d/gozxing/qrcode/encoder"
 	"github.com/makiuchi-d/gozxing/testutil"
 )
 
@@ -241,6 +248,24 @@ func testStructuredAppend(t testing.TB, file string, metadata map[gozxing.Result
 	}
 }
 
+func testSymbolMetadata(t testing.TB, file string, metadata map[gozxing.ResultMetadataType]interface{},
+	version, mask int, mirrored bool) {
+	t.Helper()
+	dimension := 17 + 4*version
+	wants := map[gozxing.ResultMetadataType]interface{}{
+		gozxing.ResultMetadataType_SYMBOL_VERSION: version,
+		gozxing.ResultMetadataType_MASK_PATTERN:   mask,
+		gozxing.ResultMetadataType_SYMBOL_ROWS:    dimension,
+		gozxing.ResultMetadataType_SYMBOL_COLUMNS: dimension,
+		gozxing.ResultMetadataType_MIRRORED:       mirrored,
+	}
+	for k, v := range wants {
+		if r, ok := metadata[k]; !ok || r != v {
+			t.Fatalf("Decode(%s) metadata[%v] = %v, expect %v", file, k, r, v)
+		}
+	}
+}
+
 func TestQRCodeReader_DecodeImage(t *testing.T) {
 	var file string
 	var result *gozxing.Result
@@ -248,6 +273,7 @@ func TestQRCodeReader_DecodeImage(t *testing.T) {
 	// ISO/IEC 18004:2000 Figure 1
 	file = "testdata/version1.png"
 	result = testDecodeImage(t, file, "QR Code Symbol")
+	testSymbolMetadata(t, file, result.GetResultMetadata(), 1, 5, false)
 	points := result.GetResultPoints()
 	// [0]: bottom-left; [1]: top-left; [2]: top-right
 	if points[0].GetX() < points[1].GetX()-10 || points[1].GetX()+10 < points[0].GetX() {
@@ -258,6 +284,7 @@ func TestQRCodeReader_DecodeImage(t *testing.T) {
 	}
 	file = "testdata/version1_mirrored.png"
 	result = testDecodeImage(t, file, "QR Code Symbol")
+	testSymbolMetadata(t, file, result.GetResultMetadata(), 1, 5, true)
 	points = result.GetResultPoints()
 	if points[0].GetX() < points[1].GetX()-10 || points[1].GetX()+10 < points[0].GetX() {
 		t.Fatalf("ResultPoint BottomLeft.X != TopLeft.X")
@@ -271,8 +298,9 @@ func TestQRCodeReader_DecodeImage(t *testing.T) {
 	testDecodeImage(t, file, "MECARD:N:Google 411,;TEL:18665881077;;")
 
 	file = "testdata/qrcode-2.jpg"
-	testDecodeImage(t, file,
+	result = testDecodeImage(t, file,
 		"UI office hours signup\r\nhttp://www.corp.google.com/sparrow/ui_office_hours/ \r\n")
+	testSymbolMetadata(t, file, result.GetResultMetadata(), 7, 3, false)
 
 	file = "testdata/qrcode-3.jpg"
 	testDecodeImage(t, file, "MECARD:N:Sean Owen;TEL:+12125658770;EMAIL:srowen@google.com;;")
@@ -362,3 +390,115 @@ func TestQRCodeReaderBlackbox(t *testing.T) {
 		testutil.TestFile(t, reader, test.file, test.wants, format, test.hints, test.metadata)
 	}
 }
+
+func TestQRCodeReader_DecodeSymbolMetadata(t *testing.T) {
+	writer := NewQRCodeWriter()
+	reader := NewQRCodeReader()
+	pure := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_PURE_BARCODE: true,
+	}
+	for mask := 0; mask < 8; mask++ {
+		hints := map[gozxing.EncodeHintType]interface{}{
+			gozxing.EncodeHintType_QR_VERSION:      3,
+			gozxing.EncodeHintType_QR_MASK_PATTERN: mask,
+		}
+		img, e := writer.Encode("symbol metadata", gozxing.BarcodeFormat_QR_CODE, 0, 0, hints)
+		if e != nil {
+			t.Fatalf("Encode returns error, %v", e)
+		}
+		result, e := reader.Decode(testutil.NewBinaryBitmapFromBitMatrix(img), pure)
+		if e != nil {
+			t.Fatalf("Decode returns error, %v", e)
+		}
+		testSymbolMetadata(t, "mask", result.GetResultMetadata(), 3, mask, false)
+	}
+}
+
+func TestQRCodeReader_DecodeSegments(t *testing.T) {
+	numeric, _ := encoder.NewNumericSegment("0123")
+	byteSegment, _ := encoder.NewByteSegment("Weiß", common.CharacterSetECI_ISO8859_1)
+	segments := []*encoder.Segment{
+		numeric,
+		encoder.NewECISegment(common.CharacterSetECI_ISO8859_1),
+		byteSegment,
+	}
+	code, e := encoder.Encoder_encodeSegments(segments, decoder.ErrorCorrectionLevel_L, nil)
+	if e != nil {
+		t.Fatalf("Encoder_encodeSegments returns error, %v", e)
+	}
+	img, _ := renderResult(code, 0, 0, 4)
+
+	result, err := NewQRCodeReader().DecodeWithoutHints(testutil.NewBinaryBitmapFromBitMatrix(img))
+	if err != nil {
+		t.Fatalf("Decode returns error, %v", err)
+	}
+	if txt, wants := result.GetText(), "0123Weiß"; txt != wants {
+		t.Fatalf("Decode text = %q, expect %q", txt, wants)
+	}
+	wants := []*common.DecodedSegment{
+		common.NewDecodedSegment("NUMERIC", -1, []byte("0123"), 0, 4),
+		common.NewDecodedSegment("BYTE", 1, []byte{0x57, 0x65, 0x69, 0xdf}, 4, 9),
+	}
+	if r := result.GetResultMetadata()[gozxing.ResultMetadataType_DECODED_SEGMENTS]; !reflect.DeepEqual(r, wants) {
+		t.Fatalf("DECODED_SEGMENTS = %v, expect %v", r, wants)
+	}
+}
+
+func TestQRCodeReader_DecodeAlsoInverted(t *testing.T) {
+	file := "testdata/qrcode-2.jpg"
+	bmp, _ := testutil.NewBinaryBitmapFromFile(file).Invert()
+	reader := NewQRCodeReader()
+
+	if _, e := reader.Decode(bmp, nil); e == nil {
+		t.Fatalf("Decode(inverted %s) without ALSO_INVERTED must be error", file)
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	result, e := reader.Decode(bmp, hints)
+	if e != nil {
+		t.Fatalf("Decode(inverted %s) returns error, %v", file, e)
+	}
+	if inv := result.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; inv != true {
+		t.Fatalf("Decode(inverted %s) INVERTED = %v, expect true", file, inv)
+	}
+
+	result, e = reader.Decode(testutil.NewBinaryBitmapFromFile(file), hints)
+	if e != nil {
+		t.Fatalf("Decode(%s) returns error, %v", file, e)
+	}
+	if inv, ok := result.GetResultMetadata()[gozxing.ResultMetadataType_INVERTED]; ok {
+		t.Fatalf("Decode(%s) INVERTED = %v, expect none", file, inv)
+	}
+}
+
+func TestQRCodeReader_DecodeContext(t *testing.T) {
+	file := "testdata/qrcode-2.jpg"
+	bmp := testutil.NewBinaryBitmapFromFile(file)
+	reader := NewQRCodeReader().(gozxing.ReaderWithContext)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_ALSO_INVERTED: true,
+	}
+	_, e := reader.DecodeContext(ctx, bmp, hints)
+	if _, ok := e.(gozxing.ReaderException); !ok {
+		t.Fatalf("DecodeContext(%s) must be ReaderException, %T", file, e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("DecodeContext(%s) must wrap context.Canceled, %v", file, e)
+	}
+
+	if _, e = reader.DecodeContext(context.Background(), bmp, nil); e != nil {
+		t.Fatalf("DecodeContext(%s) returns error, %v", file, e)
+	}
+}
+
+func TestQRCodeReader_Concurrent(t *testing.T) {
+	testutil.TestConcurrentDecode(t, NewQRCodeReader(),
+		[]string{"testdata/version1.png", "testdata/version1_mirrored.png", "testdata/qrcode-1.jpg"},
+		[]string{"QR Code Symbol", "QR Code Symbol", "MECARD:N:Google 411,;TEL:18665881077;;"},
+		nil)
+}
@@ -27,17 +27,60 @@ func (this *QRCodeWriter) Encode(
 	contents string, format gozxing.BarcodeFormat, width, height int,
 	hints map[gozxing.EncodeHintType]interface{}) (*gozxing.BitMatrix, error) {
 
+	errorCorrectionLevel, quietZone, e := parseHints(contents, format, width, height, hints)
+	if e != nil {
+		return nil, e
+	}
+
+	code, e := encoder.Encoder_encode(contents, errorCorrectionLevel, hints)
+	if e != nil {
+		return nil, e
+	}
+	return renderResult(code, width, height, quietZone)
+}
+
+// EncodeStructuredAppend Encodes the contents into a sequence of QR Codes using structured append.
+// The contents are split into up to 16 symbols, and each symbol is rendered with the requested size.
+// If the contents fit in a single symbol, one symbol without the structured append header is returned.
+func (this *QRCodeWriter) EncodeStructuredAppend(
+	contents string, format gozxing.BarcodeFormat, width, height int,
+	hints map[gozxing.EncodeHintType]interface{}) ([]*gozxing.BitMatrix, error) {
+
+	errorCorrectionLevel, quietZone, e := parseHints(contents, format, width, height, hints)
+	if e != nil {
+		return nil, e
+	}
+
+	codes, e := encoder.Encoder_encodeStructuredAppend(contents, errorCorrectionLevel, hints)
+	if e != nil {
+		return nil, e
+	}
+	matrices := make([]*gozxing.BitMatrix, 0, len(codes))
+	for _, code := range codes {
+		matrix, e := renderResult(code, width, height, quietZone)
+		if e != nil {
+			return nil, e
+		}
+		matrices = append(matrices, matrix)
+	}
+	return matrices, nil
+}
+
+// parseHints Validates the arguments and returns the error correction level and the quiet zone size.
+func parseHints(contents string, format gozxing.BarcodeFormat, width, height int,
+	hints map[gozxing.EncodeHintType]interface{}) (decoder.ErrorCorrectionLevel, int, error) {
+
 	if len(contents) == 0 {
-		return nil, gozxing.NewWriterException("IllegalArgumentException: Found empty contents")
+		return 0, 0, gozxing.NewWriterException("IllegalArgumentException: Found empty contents")
 	}
 
 	if format != gozxing.BarcodeFormat_QR_CODE {
-		return nil, gozxing.NewWriterException(
+		return 0, 0, gozxing.NewWriterException(
 			"IllegalArgumentException: Can only encode QR_CODE, but got %v", format)
 	}
 
 	if width < 0 || height < 0 {
-		return nil, gozxing.NewWriterException(
+		return 0, 0, gozxing.NewWriterException(
 			"IllegalArgumentException: Requested dimensions are too small: %vx%v", width, height)
 	}
 
@@ -50,11 +93,11 @@ func (this *QRCodeWriter) Encode(
 			} else if str, ok := ec.(string); ok {
 				ecl, e := decoder.ErrorCorrectionLevel_ValueOf(str)
 				if e != nil {
-					return nil, gozxing.NewWriterException("EncodeHintType_ERROR_CORRECTION: %w", e)
+					return 0, 0, gozxing.NewWriterException("EncodeHintType_ERROR_CORRECTION: %w", e)
 				}
 				errorCorrectionLevel = ecl
 			} else {
-				return nil, gozxing.NewWriterException(
+				return 0, 0, gozxing.NewWriterException(
 					"IllegalArgumentException: EncodeHintType_ERROR_CORRECTION %v", ec)
 			}
 		}
@@ -64,21 +107,17 @@ func (this *QRCodeWriter) Encode(
 			} else if str, ok := m.(string); ok {
 				qz, e := strconv.Atoi(str)
 				if e != nil {
-					return nil, gozxing.NewWriterException("EncodeHintType_MARGIN = \"%v\": %w", m, e)
+					return 0, 0, gozxing.NewWriterException("EncodeHintType_MARGIN = \"%v\": %w", m, e)
 				}
 				quietZone = qz
 			} else {
-				return nil, gozxing.NewWriterException(
+				return 0, 0, gozxing.NewWriterException(
 					"IllegalArgumentException: EncodeHintType_MARGIN %v", m)
 			}
 		}
 	}
 
-	code, e := encoder.Encoder_encode(contents, errorCorrectionLevel, hints)
-	if e != nil {
-		return nil, e
-	}
-	return renderResult(code, width, height, quietZone)
+	return errorCorrectionLevel, quietZone, nil
 }
 
 // renderResult Note that the input matrix uses 0 == white, 1 == black, while the output matrix uses
@@ -1,12 +1,14 @@
 package qrcode
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/qrcode/decoder"
 	"github.com/makiuchi-d/gozxing/qrcode/detector"
 	"github.com/makiuchi-d/gozxing/qrcode/encoder"
+	"github.com/makiuchi-d/gozxing/testutil"
 )
 
 func TestQRCodeWriter_renderResult(t *testing.T) {
@@ -243,3 +245,142 @@ func TestQRCodeWriter_EncodeWithMarginHint(t *testing.T) {
 		t.Fatalf("Encode result size = %vx%v, expect %vx%v", w, h, expect, expect)
 	}
 }
+
+func TestQRCodeWriter_EncodeStructuredAppend(t *testing.T) {
+	writer := NewQRCodeWriter()
+	formatQR := gozxing.BarcodeFormat_QR_CODE
+
+	_, e := writer.EncodeStructuredAppend("", formatQR, 0, 0, nil)
+	if e == nil {
+		t.Fatalf("EncodeStructuredAppend must be error")
+	}
+
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_VERSION: 2,
+		gozxing.EncodeHintType_MARGIN:     "a",
+	}
+	_, e = writer.EncodeStructuredAppend("test", formatQR, 0, 0, hints)
+	if e == nil {
+		t.Fatalf("EncodeStructuredAppend must be error")
+	}
+
+	hints[gozxing.EncodeHintType_QR_VERSION] = 40
+	_, e = writer.EncodeStructuredAppend(strings.Repeat("0", 7089*16+1), formatQR, 0, 0, hints)
+	if e == nil {
+		t.Fatalf("EncodeStructuredAppend must be error")
+	}
+
+	contents := "Structured append: 構造的連接 " + strings.Repeat("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ", 3)
+	hints = map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_QR_VERSION: 2,
+		gozxing.EncodeHintType_MARGIN:     2,
+	}
+	matrices, e := writer.EncodeStructuredAppend(contents, formatQR, 100, 100, hints)
+	if e != nil {
+		t.Fatalf("EncodeStructuredAppend returns error: %v", e)
+	}
+	total := len(matrices)
+	if total < 2 || total > 16 {
+		t.Fatalf("EncodeStructuredAppend returns %v symbols", total)
+	}
+
+	parity := 0
+	for _, b := range []byte(contents) {
+		parity ^= int(b)
+	}
+	reader := NewQRCodeReader()
+	text := ""
+	for i, matrix := range matrices {
+		if w, h := matrix.GetWidth(), matrix.GetHeight(); w != 100 || h != 100 {
+			t.Fatalf("symbol[%v] size = %vx%v, expect 100x100", i, w, h)
+		}
+		result, e := reader.DecodeWithoutHints(testutil.NewBinaryBitmapFromBitMatrix(matrix))
+		if e != nil {
+			t.Fatalf("symbol[%v] decode error: %v", i, e)
+		}
+		metadata := result.GetResultMetadata()
+		if r := metadata[gozxing.ResultMetadataType_STRUCTURED_APPEND_SEQUENCE]; r != i<<4|(total-1) {
+			t.Fatalf("symbol[%v] sequence = %v, expect %v", i, r, i<<4|(total-1))
+		}
+		if r := metadata[gozxing.ResultMetadataType_STRUCTURED_APPEND_PARITY]; r != parity {
+			t.Fatalf("symbol[%v] parity = %v, expect %v", i, r, parity)
+		}
+		text += result.GetText()
+	}
+	if text != contents {
+		t.Fatalf("decoded text = %q, expect %q", text, contents)
+	}
+
+	matrices, e = writer.EncodeStructuredAppend("small", formatQR, 0, 0, nil)
+	if e != nil {
+		t.Fatalf("EncodeStructuredAppend returns error: %v", e)
+	}
+	if len(matrices) != 1 {
+		t.Fatalf("EncodeStructuredAppend returns %v symbols, expect 1", len(matrices))
+	}
+}
+
+func TestQRCodeWriter_EncodeApplicationIndicator(t *testing.T) {
+	writer := NewQRCodeWriter()
+	reader := NewQRCodeReader()
+	formatQR := gozxing.BarcodeFormat_QR_CODE
+
+	tests := []struct {
+		contents    string
+		hints       map[gozxing.EncodeHintType]interface{}
+		text        string
+		fnc1        interface{}
+		ai          interface{}
+		symbologyID string
+	}{
+		{
+			"AB%CD",
+			map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: "37"},
+			"AB%CD", 2, "37", "]Q5",
+		},
+		{
+			"AB\x1dCD",
+			map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: "A"},
+			"AB\x1dCD", 2, "A", "]Q5",
+		},
+		{
+			"abc",
+			map[gozxing.EncodeHintType]interface{}{
+				gozxing.EncodeHintType_QR_APPLICATION_INDICATOR: 5,
+				gozxing.EncodeHintType_CHARACTER_SET:            "UTF-8",
+			},
+			"abc", 2, "05", "]Q6",
+		},
+		{
+			"0104912345123459",
+			map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_GS1_FORMAT: true},
+			"0104912345123459", 1, nil, "]Q3",
+		},
+		{
+			"abc", nil, "abc", nil, nil, "]Q1",
+		},
+	}
+	for _, test := range tests {
+		matrix, e := writer.Encode(test.contents, formatQR, 0, 0, test.hints)
+		if e != nil {
+			t.Fatalf("Encode(%q) returns error: %v", test.contents, e)
+		}
+		result, e := reader.DecodeWithoutHints(testutil.NewBinaryBitmapFromBitMatrix(matrix))
+		if e != nil {
+			t.Fatalf("Decode(%q) returns error: %v", test.contents, e)
+		}
+		if r := result.GetText(); r != test.text {
+			t.Fatalf("decoded text = %q, expect %q", r, test.text)
+		}
+		metadata := result.GetResultMetadata()
+		if r := metadata[gozxing.ResultMetadataType_FNC1_POSITION]; r != test.fnc1 {
+			t.Fatalf("FNC1_POSITION(%q) = %v, expect %v", test.contents, r, test.fnc1)
+		}
+		if r := metadata[gozxing.ResultMetadataType_APPLICATION_INDICATOR]; r != test.ai {
+			t.Fatalf("APPLICATION_INDICATOR(%q) = %v, expect %v", test.contents, r, test.ai)
+		}
+		if r := metadata[gozxing.ResultMetadataType_SYMBOLOGY_IDENTIFIER]; r != test.symbologyID {
+			t.Fatalf("SYMBOLOGY_IDENTIFIER(%q) = %v, expect %v", test.contents, r, test.symbologyID)
+		}
+	}
+}
@@ -1,5 +1,17 @@
 package gozxing
 
+import (
+	"context"
+
+	errors "golang.org/x/xerrors"
+)
+
+// Reader Implementations of this interface can decode an image of a barcode in some format
+// into the String it encodes.
+//
+// The readers in this library are safe for concurrent use by multiple goroutines,
+// and a BinaryBitmap can be shared by the readers. The readers which keep state between calls,
+// such as the RSS-14 reader, guard it with a lock, so that sharing them serializes the calls.
 type Reader interface {
 	/**
 	 * Locates and decodes a barcode in some format within an image.
@@ -34,3 +46,86 @@ type Reader interface {
 	 */
 	Reset()
 }
+
+// DecodeFunc is the signature of Reader.Decode
+type DecodeFunc func(image *BinaryBitmap, hints map[DecodeHintType]interface{}) (*Result, error)
+
+// Reader_DecodeAlsoInverted Decodes the image with the decode function, and when it fails and
+// the ALSO_INVERTED hint is set, decodes the inverted image again.
+// The result from the inverted image has the INVERTED metadata.
+// The inverted image is not decoded when the error is caused by the canceled or expired context.
+//
+// @param image image of barcode to decode
+// @param hints the decode hints
+// @param decode the function to decode the image
+// @return the decoded result
+// @throws ReaderException the exception from the original image if both images fail to decode
+//
+func Reader_DecodeAlsoInverted(image *BinaryBitmap, hints map[DecodeHintType]interface{}, decode DecodeFunc) (*Result, error) {
+	result, e := decode(image, hints)
+	if e == nil {
+		return result, nil
+	}
+	if _, ok := hints[DecodeHintType_ALSO_INVERTED]; !ok {
+		return nil, e
+	}
+	if _, ok := e.(ReaderException); !ok {
+		return nil, e
+	}
+	if errors.Is(e, context.Canceled) || errors.Is(e, context.DeadlineExceeded) {
+		return nil, e
+	}
+	inverted, err := image.Invert()
+	if err != nil {
+		return nil, e
+	}
+	result, err = decode(inverted, hints)
+	if err != nil {
+		return nil, e
+	}
+	result.PutMetadata(ResultMetadataType_INVERTED, true)
+	return result, nil
+}
+
+// ReaderWithContext is the Reader which can stop decoding when the context is done.
+type ReaderWithContext interface {
+	Reader
+
+	// DecodeContext Locates and decodes a barcode in some format within an image,
+	// and stops when the context is canceled or its deadline is exceeded.
+	//
+	// @param ctx the context to stop decoding
+	// @param image image of barcode to decode
+	// @param hints the decode hints
+	// @return String which the barcode encodes
+	// @throws ReaderException wrapping ctx.Err() if the context is done
+	DecodeContext(ctx context.Context, image *BinaryBitmap, hints map[DecodeHintType]interface{}) (*Result, error)
+}
+
+// Reader_CheckContext returns ctx.Err() wrapped in a ReaderException if the context is done, or nil
+func Reader_CheckContext(ctx context.Context) error {
+	if e := ctx.Err(); e != nil {
+		return WrapReaderException(e)
+	}
+	return nil
+}
+
+// Reader_DecodeContext Decodes the image by the reader with the context.
+// If the reader is not a ReaderWithContext, the context is checked only before decoding.
+//
+// @param ctx the context to stop decoding
+// @param reader the reader to decode the image
+// @param image image of barcode to decode
+// @param hints the decode hints
+// @return the decoded result
+// @throws ReaderException wrapping ctx.Err() if the context is done
+//
+func Reader_DecodeContext(ctx context.Context, reader Reader, image *BinaryBitmap, hints map[DecodeHintType]interface{}) (*Result, error) {
+	if r, ok := reader.(ReaderWithContext); ok {
+		return r.DecodeContext(ctx, image, hints)
+	}
+	if e := Reader_CheckContext(ctx); e != nil {
+		return nil, e
+	}
+	return reader.Decode(image, hints)
+}
@@ -0,0 +1,157 @@
+package gozxing
+
+import (
+	"context"
+	"testing"
+
+	errors "golang.org/x/xerrors"
+)
+
+func testDecodeFuncForInverted(err error) DecodeFunc {
+	return func(image *BinaryBitmap, hints map[DecodeHintType]interface{}) (*Result, error) {
+		if _, ok := image.binarizer.GetLuminanceSource().(*InvertedLuminanceSource); !ok {
+			return nil, err
+		}
+		return NewResult("inverted", nil, nil, BarcodeFormat_QR_CODE), nil
+	}
+}
+
+func TestReader_DecodeAlsoInverted(t *testing.T) {
+	bmp, _ := NewBinaryBitmap(&testBinarizer{newTestLuminanceSource(16)})
+	hints := map[DecodeHintType]interface{}{DecodeHintType_ALSO_INVERTED: true}
+
+	decode := func(image *BinaryBitmap, hints map[DecodeHintType]interface{}) (*Result, error) {
+		return NewResult("original", nil, nil, BarcodeFormat_QR_CODE), nil
+	}
+	result, e := Reader_DecodeAlsoInverted(bmp, hints, decode)
+	if e != nil {
+		t.Fatalf("Reader_DecodeAlsoInverted returns error, %v", e)
+	}
+	if txt := result.GetText(); txt != "original" {
+		t.Fatalf("text = %v, expect original", txt)
+	}
+	if _, ok := result.GetResultMetadata()[ResultMetadataType_INVERTED]; ok {
+		t.Fatalf("result of the original image must not have INVERTED metadata")
+	}
+
+	notFound := NewNotFoundException("original")
+	_, e = Reader_DecodeAlsoInverted(bmp, nil, testDecodeFuncForInverted(notFound))
+	if e != notFound {
+		t.Fatalf("Reader_DecodeAlsoInverted without hint must return %v, %v", notFound, e)
+	}
+
+	result, e = Reader_DecodeAlsoInverted(bmp, hints, testDecodeFuncForInverted(notFound))
+	if e != nil {
+		t.Fatalf("Reader_DecodeAlsoInverted returns error, %v", e)
+	}
+	if txt := result.GetText(); txt != "inverted" {
+		t.Fatalf("text = %v, expect inverted", txt)
+	}
+	if inv, ok := result.GetResultMetadata()[ResultMetadataType_INVERTED]; !ok || inv != true {
+		t.Fatalf("INVERTED metadata = %v, expect true", inv)
+	}
+
+	illegal := errors.New("IllegalException")
+	_, e = Reader_DecodeAlsoInverted(bmp, hints, testDecodeFuncForInverted(illegal))
+	if e != illegal {
+		t.Fatalf("Reader_DecodeAlsoInverted must return %v, %v", illegal, e)
+	}
+
+	decode = func(image *BinaryBitmap, hints map[DecodeHintType]interface{}) (*Result, error) {
+		if _, ok := image.binarizer.GetLuminanceSource().(*InvertedLuminanceSource); ok {
+			return nil, NewFormatException("inverted")
+		}
+		return nil, notFound
+	}
+	_, e = Reader_DecodeAlsoInverted(bmp, hints, decode)
+	if e != notFound {
+		t.Fatalf("Reader_DecodeAlsoInverted must return the original error %v, %v", notFound, e)
+	}
+
+	for _, ctxErr := range []error{context.Canceled, context.DeadlineExceeded} {
+		canceled := WrapReaderException(ctxErr)
+		calls := 0
+		decode = func(image *BinaryBitmap, hints map[DecodeHintType]interface{}) (*Result, error) {
+			calls++
+			return testDecodeFuncForInverted(canceled)(image, hints)
+		}
+		_, e = Reader_DecodeAlsoInverted(bmp, hints, decode)
+		if e != canceled {
+			t.Fatalf("Reader_DecodeAlsoInverted must return %v, %v", canceled, e)
+		}
+		if calls != 1 {
+			t.Fatalf("Reader_DecodeAlsoInverted must not decode the inverted image after %v", ctxErr)
+		}
+	}
+}
+
+type testReader struct {
+	decoded bool
+}
+
+func (r *testReader) DecodeWithoutHints(image *BinaryBitmap) (*Result, error) {
+	return r.Decode(image, nil)
+}
+func (r *testReader) Decode(image *BinaryBitmap, hints map[DecodeHintType]interface{}) (*Result, error) {
+	r.decoded = true
+	return NewResult("decoded", nil, nil, BarcodeFormat_QR_CODE), nil
+}
+func (r *testReader) Reset() {}
+
+type testReaderWithContext struct {
+	testReader
+}
+
+func (r *testReaderWithContext) DecodeContext(ctx context.Context, image *BinaryBitmap, hints map[DecodeHintType]interface{}) (*Result, error) {
+	r.decoded = true
+	return NewResult("decoded with context", nil, nil, BarcodeFormat_QR_CODE), nil
+}
+
+func TestReader_CheckContext(t *testing.T) {
+	if e := Reader_CheckContext(context.Background()); e != nil {
+		t.Fatalf("Reader_CheckContext(Background) must be nil, %v", e)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e := Reader_CheckContext(ctx)
+	if _, ok := e.(ReaderException); !ok {
+		t.Fatalf("Reader_CheckContext must be ReaderException, %T", e)
+	}
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("Reader_CheckContext must wrap context.Canceled, %v", e)
+	}
+}
+
+func TestReader_DecodeContext(t *testing.T) {
+	bmp, _ := NewBinaryBitmap(&testBinarizer{newTestLuminanceSource(16)})
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reader := &testReader{}
+	result, e := Reader_DecodeContext(context.Background(), reader, bmp, nil)
+	if e != nil {
+		t.Fatalf("Reader_DecodeContext returns error, %v", e)
+	}
+	if txt := result.GetText(); txt != "decoded" {
+		t.Fatalf("text = %v, expect decoded", txt)
+	}
+
+	reader = &testReader{}
+	_, e = Reader_DecodeContext(canceled, reader, bmp, nil)
+	if !errors.Is(e, context.Canceled) {
+		t.Fatalf("Reader_DecodeContext must wrap context.Canceled, %v", e)
+	}
+	if reader.decoded {
+		t.Fatalf("reader must not be called with the canceled context")
+	}
+
+	readerWithContext := &testReaderWithContext{}
+	result, e = Reader_DecodeContext(canceled, readerWithContext, bmp, nil)
+	if e != nil {
+		t.Fatalf("Reader_DecodeContext returns error, %v", e)
+	}
+	if txt := result.GetText(); txt != "decoded with context" {
+		t.Fatalf("text = %v, expect decoded with context", txt)
+	}
+}
@@ -79,6 +79,71 @@ const (
 	 *  when prepending to the barcode content.
 	 */
 	ResultMetadataType_SYMBOLOGY_IDENTIFIER
+
+	/**
+	 * Position of FNC1 in the symbol, {@link Integer} 1 for the first position (GS1)
+	 * or 2 for the second position (AIM application indicator).
+	 */
+	ResultMetadataType_FNC1_POSITION
+
+	/**
+	 * AIM application indicator which follows FNC1 in second position, as a {@link String}
+	 * of two digits ("00"-"99") or one letter.
+	 */
+	ResultMetadataType_APPLICATION_INDICATOR
+
+	/**
+	 * Version number of the symbol, as an {@link Integer}.
+	 * QR Code version (1-40), or Data Matrix version number in the symbol size table.
+	 */
+	ResultMetadataType_SYMBOL_VERSION
+
+	/**
+	 * Data mask pattern (0-7) applied to a QR Code symbol, as an {@link Integer}.
+	 */
+	ResultMetadataType_MASK_PATTERN
+
+	/**
+	 * Number of module rows of the symbol, as an {@link Integer}.
+	 */
+	ResultMetadataType_SYMBOL_ROWS
+
+	/**
+	 * Number of module columns of the symbol, as an {@link Integer}.
+	 */
+	ResultMetadataType_SYMBOL_COLUMNS
+
+	/**
+	 * {@link Boolean} whether a Data Matrix symbol is a rectangular extension (DMRE) size.
+	 */
+	ResultMetadataType_DMRE
+
+	/**
+	 * {@link Boolean} whether an Aztec symbol is compact (true) or full-range (false).
+	 */
+	ResultMetadataType_AZTEC_COMPACT
+
+	/**
+	 * Number of data layers of an Aztec symbol, as an {@link Integer}.
+	 */
+	ResultMetadataType_AZTEC_LAYERS
+
+	/**
+	 * {@link Boolean} whether the symbol was read as a mirror image.
+	 */
+	ResultMetadataType_MIRRORED
+
+	/**
+	 * The segments of the decoded data in the order they were encoded, as a {@code []*common.DecodedSegment}.
+	 * Each segment has the encodation mode, the ECI value in effect and the decoded bytes.
+	 */
+	ResultMetadataType_DECODED_SEGMENTS
+
+	/**
+	 * {@link Boolean} true if the symbol was found in the inverted image (light on dark)
+	 * by the {@link DecodeHintType#ALSO_INVERTED} hint.
+	 */
+	ResultMetadataType_INVERTED
 )
 
 func (t ResultMetadataType) String() string {
@@ -107,6 +172,30 @@ func (t ResultMetadataType) String() string {
 		return "STRUCTURED_APPEND_PARITY"
 	case ResultMetadataType_SYMBOLOGY_IDENTIFIER:
 		return "SYMBOLOGY_IDENTIFIER"
+	case ResultMetadataType_FNC1_POSITION:
+		return "FNC1_POSITION"
+	case ResultMetadataType_APPLICATION_INDICATOR:
+		return "APPLICATION_INDICATOR"
+	case ResultMetadataType_SYMBOL_VERSION:
+		return "SYMBOL_VERSION"
+	case ResultMetadataType_MASK_PATTERN:
+		return "MASK_PATTERN"
+	case ResultMetadataType_SYMBOL_ROWS:
+		return "SYMBOL_ROWS"
+	case ResultMetadataType_SYMBOL_COLUMNS:
+		return "SYMBOL_COLUMNS"
+	case ResultMetadataType_DMRE:
+		return "DMRE"
+	case ResultMetadataType_AZTEC_COMPACT:
+		return "AZTEC_COMPACT"
+	case ResultMetadataType_AZTEC_LAYERS:
+		return "AZTEC_LAYERS"
+	case ResultMetadataType_MIRRORED:
+		return "MIRRORED"
+	case ResultMetadataType_DECODED_SEGMENTS:
+		return "DECODED_SEGMENTS"
+	case ResultMetadataType_INVERTED:
+		return "INVERTED"
 	default:
 		return "unknown metadata type"
 	}
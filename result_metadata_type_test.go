@@ -24,6 +24,18 @@ func TestResultMetadataTypeString(t *testing.T) {
 	testResultMetadataTypeString(t, ResultMetadataType_STRUCTURED_APPEND_SEQUENCE, "STRUCTURED_APPEND_SEQUENCE")
 	testResultMetadataTypeString(t, ResultMetadataType_STRUCTURED_APPEND_PARITY, "STRUCTURED_APPEND_PARITY")
 	testResultMetadataTypeString(t, ResultMetadataType_SYMBOLOGY_IDENTIFIER, "SYMBOLOGY_IDENTIFIER")
+	testResultMetadataTypeString(t, ResultMetadataType_FNC1_POSITION, "FNC1_POSITION")
+	testResultMetadataTypeString(t, ResultMetadataType_APPLICATION_INDICATOR, "APPLICATION_INDICATOR")
+	testResultMetadataTypeString(t, ResultMetadataType_SYMBOL_VERSION, "SYMBOL_VERSION")
+	testResultMetadataTypeString(t, ResultMetadataType_MASK_PATTERN, "MASK_PATTERN")
+	testResultMetadataTypeString(t, ResultMetadataType_SYMBOL_ROWS, "SYMBOL_ROWS")
+	testResultMetadataTypeString(t, ResultMetadataType_SYMBOL_COLUMNS, "SYMBOL_COLUMNS")
+	testResultMetadataTypeString(t, ResultMetadataType_DMRE, "DMRE")
+	testResultMetadataTypeString(t, ResultMetadataType_AZTEC_COMPACT, "AZTEC_COMPACT")
+	testResultMetadataTypeString(t, ResultMetadataType_AZTEC_LAYERS, "AZTEC_LAYERS")
+	testResultMetadataTypeString(t, ResultMetadataType_MIRRORED, "MIRRORED")
+	testResultMetadataTypeString(t, ResultMetadataType_DECODED_SEGMENTS, "DECODED_SEGMENTS")
+	testResultMetadataTypeString(t, ResultMetadataType_INVERTED, "INVERTED")
 
 	testResultMetadataTypeString(t, -1, "unknown metadata type")
 }
@@ -6,6 +6,7 @@ import (
 	_ "image/png"
 	"os"
 	"reflect"
+	"sync"
 	"testing"
 
 	errors "golang.org/x/xerrors"
@@ -150,3 +151,31 @@ func TestFile(t testing.TB, reader gozxing.Reader, file, expectText string,
 		}
 	}
 }
+
+// TestConcurrentDecode decodes the files by the reader shared among the goroutines,
+// which decode each image at the same time. Run with the race detector.
+func TestConcurrentDecode(t testing.TB, reader gozxing.Reader, files, expectTexts []string,
+	hints map[gozxing.DecodeHintType]interface{}) {
+	t.Helper()
+	const goroutinesPerFile = 4
+	var wg sync.WaitGroup
+	for i, file := range files {
+		bmp := NewBinaryBitmapFromFile(file)
+		expect := expectTexts[i]
+		for j := 0; j < goroutinesPerFile; j++ {
+			wg.Add(1)
+			go func(file string) {
+				defer wg.Done()
+				result, e := reader.Decode(bmp, hints)
+				if e != nil {
+					t.Errorf("TestConcurrentDecode(%v) reader.Decode failed: %v", file, e)
+					return
+				}
+				if txt := result.GetText(); txt != expect {
+					t.Errorf("TestConcurrentDecode(%v) = \"%v\", wants \"%v\"", file, txt, expect)
+				}
+			}(file)
+		}
+	}
+	wg.Wait()
+}
@@ -1,5 +1,8 @@
 package gozxing
 
+// Writer The base interface for all barcode writers.
+//
+// The writers in this library are safe for concurrent use by multiple goroutines.
 type Writer interface {
 	/**
 	 * Encode a barcode using the default settings.